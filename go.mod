@@ -4,14 +4,17 @@ go 1.21.6
 
 toolchain go1.23.10
 
-require github.com/robfig/cron/v3 v3.0.1
+require (
+	github.com/robfig/cron/v3 v3.0.1
+	github.com/shopspring/decimal v1.4.0
+	go.etcd.io/bbolt v1.3.10
+)
 
 require (
 	github.com/bitly/go-simplejson v0.5.0 // indirect
 	github.com/google/uuid v1.6.0 // indirect
 	github.com/gorilla/websocket v1.5.3 // indirect
 	github.com/jpillora/backoff v1.0.0 // indirect
-	github.com/shopspring/decimal v1.4.0 // indirect
 )
 
 require (
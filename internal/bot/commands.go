@@ -0,0 +1,156 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"task_scheduler/internal/config"
+	"task_scheduler/internal/core"
+	"task_scheduler/pkg/ccxt"
+	"task_scheduler/pkg/pushAPI/inbound"
+)
+
+// timeLayout 回复中的时间格式
+const timeLayout = "2006-01-02 15:04:05"
+
+// NewServer 根据主配置创建命令接收服务，未配置任何命令来源时返回 nil
+// Telegram bot_token 未写入配置时读取环境变量 TELEGRAM_BOT_TOKEN
+func NewServer(cfg config.InboundConfig) (*inbound.Server, error) {
+	inboundConfig := inbound.Config{
+		AllowedUsers: cfg.AllowedUsers,
+		Telegram: inbound.TelegramConfig{
+			BotToken:    cfg.Telegram.BotToken,
+			BaseURL:     cfg.Telegram.BaseURL,
+			PollTimeout: cfg.Telegram.PollTimeout,
+		},
+		Webhook: inbound.WebhookConfig{
+			Addr:   cfg.Webhook.Addr,
+			Path:   cfg.Webhook.Path,
+			Secret: cfg.Webhook.Secret,
+		},
+	}
+	if inboundConfig.Telegram.BotToken == "" {
+		inboundConfig.Telegram.BotToken = os.Getenv("TELEGRAM_BOT_TOKEN")
+	}
+	if !inboundConfig.Enabled() {
+		return nil, nil
+	}
+	return inbound.NewServer(inboundConfig)
+}
+
+// NewExchange 创建 /balance 使用的交易所，密钥读取环境变量 {NAME}_API_KEY、{NAME}_SECRET_KEY
+func NewExchange(cfg config.InboundConfig) (ccxt.Exchange, error) {
+	name := cfg.Exchange
+	if name == "" {
+		name = "binance"
+	}
+	return ccxt.NewExchange(name, ccxt.ConfigFromEnv(name))
+}
+
+// RegisterCommands 注册调度器控制命令，exchange 为空时 /balance 返回错误
+func RegisterCommands(server *inbound.Server, taskManager *core.TaskManager, exchange ccxt.Exchange) {
+	server.Handle("status", "查看所有任务的状态", func(ctx context.Context, cmd inbound.Command) (string, error) {
+		return formatStatus(taskManager.GetStatus()), nil
+	})
+
+	server.Handle("run", "<任务名> 立即执行任务", func(ctx context.Context, cmd inbound.Command) (string, error) {
+		name := cmd.Arg(0)
+		if name == "" {
+			return "", fmt.Errorf("用法: /run <任务名>")
+		}
+		// 使用命令的 ctx，命令接收服务停止时取消执行，不阻塞停机
+		result, err := taskManager.RunTask(ctx, name)
+		if err != nil {
+			return "", err
+		}
+		if !result.Success {
+			return fmt.Sprintf("任务 %s 执行失败（耗时 %s）: %s", name, result.Duration.Round(time.Millisecond), result.Error), nil
+		}
+		return fmt.Sprintf("任务 %s 执行成功，耗时 %s", name, result.Duration.Round(time.Millisecond)), nil
+	})
+
+	server.Handle("pause", "<任务名> 暂停任务的定时调度", func(ctx context.Context, cmd inbound.Command) (string, error) {
+		name := cmd.Arg(0)
+		if name == "" {
+			return "", fmt.Errorf("用法: /pause <任务名>")
+		}
+		if err := taskManager.PauseTask(name); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("任务 %s 已暂停", name), nil
+	})
+
+	server.Handle("resume", "<任务名> 恢复任务的定时调度", func(ctx context.Context, cmd inbound.Command) (string, error) {
+		name := cmd.Arg(0)
+		if name == "" {
+			return "", fmt.Errorf("用法: /resume <任务名>")
+		}
+		if err := taskManager.ResumeTask(name); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("任务 %s 已恢复", name), nil
+	})
+
+	server.Handle("balance", "查看交易所账户余额", func(ctx context.Context, cmd inbound.Command) (string, error) {
+		if exchange == nil {
+			return "", fmt.Errorf("未配置交易所")
+		}
+		ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+		defer cancel()
+
+		balances, err := exchange.GetBalances(ctx)
+		if err != nil {
+			return "", err
+		}
+		if len(balances) == 0 {
+			return "账户没有余额", nil
+		}
+
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("%s 账户余额:", exchange.Name()))
+		for _, balance := range balances {
+			sb.WriteString(fmt.Sprintf("\n%s: %s", balance.Asset, strconv.FormatFloat(balance.Free, 'f', -1, 64)))
+			if balance.Locked > 0 {
+				sb.WriteString(fmt.Sprintf("（冻结 %s）", strconv.FormatFloat(balance.Locked, 'f', -1, 64)))
+			}
+		}
+		return sb.String(), nil
+	})
+}
+
+// formatStatus 格式化任务状态
+func formatStatus(statuses []core.TaskStatus) string {
+	if len(statuses) == 0 {
+		return "没有已添加的任务"
+	}
+
+	var sb strings.Builder
+	for i, status := range statuses {
+		if i > 0 {
+			sb.WriteString("\n\n")
+		}
+		state := "调度中"
+		switch {
+		case status.Running:
+			state = "执行中"
+		case status.Paused:
+			state = "已暂停"
+		}
+		sb.WriteString(fmt.Sprintf("%s [%s]\n调度: %s", status.Name, state, status.Schedule))
+		if !status.Paused && !status.NextRun.IsZero() {
+			sb.WriteString(fmt.Sprintf("\n下次执行: %s", status.NextRun.Format(timeLayout)))
+		}
+		if result := status.LastResult; result != nil {
+			outcome := "成功"
+			if !result.Success {
+				outcome = "失败: " + result.Error
+			}
+			sb.WriteString(fmt.Sprintf("\n上次执行: %s %s", result.StartTime.Format(timeLayout), outcome))
+		}
+	}
+	return sb.String()
+}
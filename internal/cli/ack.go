@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"task_scheduler/pkg/pushAPI"
+)
+
+func init() {
+	register(&Command{
+		Name:  "ack",
+		Usage: "确认收到紧急消息，停止重发提醒",
+		Run:   runAck,
+	})
+}
+
+// runAck 确认紧急消息：task_scheduler ack [参数] <令牌>
+func runAck(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("ack", flag.ContinueOnError)
+	fs.SetOutput(out)
+	flags := pushConfigFlags(fs)
+
+	by, _ := os.Hostname()
+	fs.StringVar(&by, "by", by, "确认人")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("用法: task_scheduler ack [参数] <令牌>")
+	}
+
+	cfg, err := flags.load()
+	if err != nil {
+		return err
+	}
+
+	// 优先通过运行中实例的确认接口确认，连接不上时直接修改消息存储
+	token := fs.Arg(0)
+	if err := pushAPI.Acknowledge(cfg, token, by); err != nil {
+		if errors.Is(err, pushAPI.ErrAckNotFound) {
+			return fmt.Errorf("确认令牌 %s 不存在或已过期", token)
+		}
+		return fmt.Errorf("确认失败: %w", err)
+	}
+	fmt.Fprintf(out, "已确认: %s\n", token)
+	return nil
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"task_scheduler/internal/config"
+	"task_scheduler/pkg/pushAPI"
+)
+
+// Command 命令行子命令
+type Command struct {
+	Name  string                                   // 子命令名称
+	Usage string                                   // 简要说明
+	Run   func(args []string, out io.Writer) error // 执行函数
+}
+
+// commands 已注册的子命令
+var commands = map[string]*Command{}
+
+// register 注册子命令
+func register(cmd *Command) {
+	commands[cmd.Name] = cmd
+}
+
+// IsCommand 判断是否为已注册的子命令
+func IsCommand(name string) bool {
+	_, exists := commands[name]
+	return exists
+}
+
+// Run 执行子命令，args 为去掉程序名后的命令行参数
+func Run(args []string, out io.Writer) error {
+	if len(args) == 0 {
+		printUsage(out)
+		return fmt.Errorf("缺少子命令")
+	}
+
+	cmd, exists := commands[args[0]]
+	if !exists {
+		printUsage(out)
+		return fmt.Errorf("未知的子命令: %s", args[0])
+	}
+	return cmd.Run(args[1:], out)
+}
+
+// printUsage 输出子命令列表
+func printUsage(out io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(out, "用法: task_scheduler [子命令] [参数]")
+	fmt.Fprintln(out, "不带子命令时启动定时任务调度器。子命令：")
+	for _, name := range names {
+		fmt.Fprintf(out, "  %-10s %s\n", name, commands[name].Usage)
+	}
+}
+
+// defaultConfigPath 调度器使用的主配置文件
+const defaultConfigPath = "configs/config.yaml"
+
+// pushFlags 推送存储相关参数，未指定的参数使用主配置文件中 push 的值
+type pushFlags struct {
+	configPath string
+	workingDir string
+	historyDir string
+	store      string
+	storePath  string
+	ackAddr    string
+}
+
+// pushConfigFlags 注册推送存储相关参数，解析参数后调用 load 取得配置
+func pushConfigFlags(fs *flag.FlagSet) *pushFlags {
+	pf := &pushFlags{}
+	fs.StringVar(&pf.configPath, "config", defaultConfigPath, "主配置文件，读取其中的 push 配置")
+	fs.StringVar(&pf.workingDir, "working-dir", "", "推送工作目录，覆盖配置文件")
+	fs.StringVar(&pf.historyDir, "history-dir", "", "推送历史记录目录，覆盖配置文件")
+	fs.StringVar(&pf.store, "store", "", "消息存储类型：file 或 bolt，覆盖配置文件")
+	fs.StringVar(&pf.storePath, "store-path", "", "bolt数据库文件路径，覆盖配置文件，默认 {working-dir}/messages.db")
+	fs.StringVar(&pf.ackAddr, "ack-addr", "", "运行中实例的确认接口地址（如 :8089），ack 和 history 优先请求该实例，覆盖配置文件中的 ack.listen_addr")
+	return pf
+}
+
+// load 读取主配置文件中的推送配置，再应用命令行指定的参数
+// 使用默认配置文件路径且文件不存在时使用推送默认配置
+func (pf *pushFlags) load() (pushAPI.Config, error) {
+	cfg := pushAPI.DefaultConfig()
+	if _, err := os.Stat(pf.configPath); err == nil || pf.configPath != defaultConfigPath {
+		mainConfig, err := config.NewLoader(pf.configPath).LoadMainConfig()
+		if err != nil {
+			return cfg, err
+		}
+		cfg = mainConfig.Push.PushAPIConfig()
+	}
+
+	if pf.workingDir != "" {
+		cfg.WorkingDir = pf.workingDir
+	}
+	if pf.historyDir != "" {
+		cfg.HistoryDir = pf.historyDir
+	}
+	if pf.store != "" {
+		cfg.Store.Type = pf.store
+	}
+	if pf.storePath != "" {
+		cfg.Store.Path = pf.storePath
+	}
+	if pf.ackAddr != "" {
+		cfg.Ack.ListenAddr = pf.ackAddr
+	}
+	return cfg, nil
+}
@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"task_scheduler/pkg/pushAPI"
+)
+
+func init() {
+	register(&Command{
+		Name:  "history",
+		Usage: "查询推送历史记录",
+		Run:   runHistory,
+	})
+}
+
+// runHistory 查询推送历史记录并按指定格式输出
+func runHistory(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	fs.SetOutput(out)
+	flags := pushConfigFlags(fs)
+
+	var query pushAPI.HistoryQuery
+	var since, until, format string
+	fs.StringVar(&query.AppID, "app", "", "按AppID过滤")
+	fs.StringVar(&query.PusherName, "pusher", "", "按推送器过滤，如 wechat")
+	fs.StringVar(&query.Level, "level", "", "按级别过滤：normal 或 emergency")
+	fs.StringVar(&query.Status, "status", "", "按发送结果过滤：success 或 failed")
+	fs.StringVar(&query.MessageID, "id", "", "按消息ID查询")
+	fs.StringVar(&query.Keyword, "q", "", "标题或内容包含的关键字")
+	fs.StringVar(&since, "since", "", "起始时间，如 2024-01-02、\"2024-01-02 15:04\" 或 7d、12h（相对现在）")
+	fs.StringVar(&until, "until", "", "截止时间（不含），格式同 -since")
+	fs.IntVar(&query.Offset, "offset", 0, "跳过的记录数")
+	fs.IntVar(&query.Limit, "limit", 50, "返回的最大记录数，0 表示不限制")
+	fs.StringVar(&format, "format", "table", "输出格式：table、json 或 csv")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	cfg, err := flags.load()
+	if err != nil {
+		return err
+	}
+	if query.Since, err = parseTimeArg(since); err != nil {
+		return fmt.Errorf("解析 -since 失败: %w", err)
+	}
+	if query.Until, err = parseTimeArg(until); err != nil {
+		return fmt.Errorf("解析 -until 失败: %w", err)
+	}
+
+	result, err := pushAPI.QueryHistory(cfg, query)
+	if err != nil {
+		return fmt.Errorf("查询历史记录失败: %w", err)
+	}
+
+	switch format {
+	case "table":
+		return writeHistoryTable(out, result, query.Offset)
+	case "json":
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	case "csv":
+		return writeHistoryCSV(out, result)
+	default:
+		return fmt.Errorf("不支持的输出格式: %s", format)
+	}
+}
+
+// parseTimeArg 解析时间参数：日期、日期时间、RFC3339，或相对现在的 Nd / Go duration
+func parseTimeArg(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil {
+			return time.Now().AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+
+	for _, layout := range []string{"2006-01-02", "2006-01-02 15:04", "2006-01-02 15:04:05"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("无法识别的时间: %s", value)
+}
+
+// writeHistoryTable 以表格形式输出历史记录
+func writeHistoryTable(out io.Writer, result *pushAPI.HistoryResult, offset int) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "时间\t状态\tAppID\t推送器\t级别\t标题\t消息ID")
+	for _, record := range result.Records {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			record.Timestamp.Local().Format("2006-01-02 15:04:05"),
+			record.Status,
+			record.AppID,
+			record.PusherName,
+			record.Level,
+			truncate(record.Title, 30),
+			record.MessageID,
+		)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(result.Records) == 0 {
+		fmt.Fprintf(out, "共 %d 条记录\n", result.Total)
+	} else {
+		fmt.Fprintf(out, "共 %d 条记录，显示第 %d-%d 条\n", result.Total, offset+1, offset+len(result.Records))
+	}
+	return nil
+}
+
+// writeHistoryCSV 以CSV形式输出历史记录
+func writeHistoryCSV(out io.Writer, result *pushAPI.HistoryResult) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{"timestamp", "status", "app_id", "pusher_name", "level", "title", "content", "message_id", "receivers", "error_reason"})
+	for _, record := range result.Records {
+		w.Write([]string{
+			record.Timestamp.Format(time.RFC3339),
+			record.Status,
+			record.AppID,
+			record.PusherName,
+			record.Level,
+			record.Title,
+			record.Content,
+			record.MessageID,
+			strings.Join(record.Receivers, ";"),
+			record.ErrorReason,
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// truncate 按字符截断过长文本
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max]) + "…"
+}
@@ -3,17 +3,74 @@ package config
 import (
 	"fmt"
 	"log"
+	"time"
 
 	"task_scheduler/internal/plugins"
+	"task_scheduler/pkg/pushAPI"
 
 	"github.com/spf13/viper"
 )
 
 // Config 主配置结构
 type Config struct {
-	LogLevel   string       `mapstructure:"log_level"`
-	PluginsDir string       `mapstructure:"plugins_dir"`
-	Tasks      []TaskConfig `mapstructure:"tasks"`
+	LogLevel   string        `mapstructure:"log_level"`
+	PluginsDir string        `mapstructure:"plugins_dir"`
+	Tasks      []TaskConfig  `mapstructure:"tasks"`
+	Inbound    InboundConfig `mapstructure:"inbound"`
+	Push       PushConfig    `mapstructure:"push"`
+}
+
+// PushConfig 推送存储与紧急消息确认配置，调度器中的任务和命令行（history、ack）共用，未配置的字段使用推送默认配置
+type PushConfig struct {
+	WorkingDir  string `mapstructure:"working_dir"`  // 工作目录（延迟和定时消息）
+	HistoryDir  string `mapstructure:"history_dir"`  // 历史消息记录目录
+	TemplateDir string `mapstructure:"template_dir"` // 消息模板目录
+	Store       struct {
+		Type string `mapstructure:"type"` // 存储类型：file 或 bolt，bolt 只支持单进程
+		Path string `mapstructure:"path"` // bolt数据库文件路径
+	} `mapstructure:"store"`
+	Ack struct {
+		Window      time.Duration `mapstructure:"window"`       // 确认等待时间，0 表示不启用
+		MaxAttempts int           `mapstructure:"max_attempts"` // 最多重发次数
+		ListenAddr  string        `mapstructure:"listen_addr"`  // 确认接口监听地址，命令行确认和历史查询优先请求该接口
+		PublicURL   string        `mapstructure:"public_url"`   // 消息中展示的确认地址
+	} `mapstructure:"ack"`
+}
+
+// PushAPIConfig 在推送默认配置上应用配置文件中的值
+func (pc PushConfig) PushAPIConfig() pushAPI.Config {
+	cfg := pushAPI.DefaultConfig()
+	if pc.WorkingDir != "" {
+		cfg.WorkingDir = pc.WorkingDir
+	}
+	if pc.HistoryDir != "" {
+		cfg.HistoryDir = pc.HistoryDir
+	}
+	if pc.TemplateDir != "" {
+		cfg.TemplateDir = pc.TemplateDir
+	}
+	cfg.Store = pushAPI.StoreConfig{Type: pc.Store.Type, Path: pc.Store.Path}
+	cfg.Ack.Window = pc.Ack.Window
+	cfg.Ack.MaxAttempts = pc.Ack.MaxAttempts
+	cfg.Ack.ListenAddr = pc.Ack.ListenAddr
+	cfg.Ack.PublicURL = pc.Ack.PublicURL
+	return cfg
+}
+
+// InboundConfig 聊天机器人命令接收配置
+type InboundConfig struct {
+	AllowedUsers []string `mapstructure:"allowed_users"` // 允许执行命令的用户ID
+	Exchange     string   `mapstructure:"exchange"`      // /balance 查询的交易所，默认 binance
+	Telegram     struct {
+		BotToken    string        `mapstructure:"bot_token"`    // 为空时读取环境变量 TELEGRAM_BOT_TOKEN
+		BaseURL     string        `mapstructure:"base_url"`     // Bot API地址
+		PollTimeout time.Duration `mapstructure:"poll_timeout"` // 长轮询等待时间
+	} `mapstructure:"telegram"`
+	Webhook struct {
+		Addr   string `mapstructure:"addr"`   // 监听地址，为空时不启用
+		Path   string `mapstructure:"path"`   // 请求路径
+		Secret string `mapstructure:"secret"` // 签名密钥
+	} `mapstructure:"webhook"`
 }
 
 // TaskConfig 任务配置结构
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
 	"sync"
 	"time"
 
@@ -12,12 +13,16 @@ import (
 	"github.com/robfig/cron/v3"
 )
 
+// defaultTaskTimeout 任务单次执行的默认超时时间
+const defaultTaskTimeout = 30 * time.Second
+
 // TaskManager 任务管理器
 type TaskManager struct {
 	cron    *cron.Cron
 	tasks   map[string]*ManagedTask
 	plugins map[string]plugins.Plugin
 	results []plugins.TaskResult
+	running map[string]bool
 	mu      sync.RWMutex
 	ctx     context.Context
 	cancel  context.CancelFunc
@@ -29,6 +34,17 @@ type ManagedTask struct {
 	Plugin  plugins.Plugin
 	Task    plugins.Task
 	EntryID cron.EntryID
+	Paused  bool // 暂停后定时调度跳过执行，手动执行不受影响
+}
+
+// TaskStatus 任务状态
+type TaskStatus struct {
+	Name       string              `json:"name"`
+	Schedule   string              `json:"schedule"`
+	Paused     bool                `json:"paused"`
+	Running    bool                `json:"running"`
+	NextRun    time.Time           `json:"next_run"`
+	LastResult *plugins.TaskResult `json:"last_result,omitempty"`
 }
 
 // NewTaskManager 创建任务管理器
@@ -38,6 +54,7 @@ func NewTaskManager() *TaskManager {
 		cron:    cron.New(cron.WithSeconds()),
 		tasks:   make(map[string]*ManagedTask),
 		plugins: make(map[string]plugins.Plugin),
+		running: make(map[string]bool),
 		ctx:     ctx,
 		cancel:  cancel,
 	}
@@ -74,8 +91,20 @@ func (tm *TaskManager) AddTask(info plugins.TaskInfo) error {
 	}
 
 	// 添加定时任务
-	entryID, err := tm.cron.AddFunc(info.Schedule, func() {
-		tm.executeTask(task, info)
+	var entryID cron.EntryID
+	entryID, err = tm.cron.AddFunc(info.Schedule, func() {
+		if tm.isPaused(info.Name) {
+			log.Printf("任务已暂停，跳过本次调度: %s", info.Name)
+			return
+		}
+		// 调度器在执行前将 Prev 设为本次的计划时间
+		run := plugins.RunInfo{TaskName: info.Name, ScheduledTime: tm.cron.Entry(entryID).Prev}
+		if run.ScheduledTime.IsZero() {
+			run.ScheduledTime = time.Now().Truncate(time.Second)
+		}
+		if _, err := tm.executeTask(tm.ctx, task, info, run); err != nil {
+			log.Printf("跳过本次调度: %v", err)
+		}
 	})
 	if err != nil {
 		return fmt.Errorf("添加定时任务失败: %w", err)
@@ -94,16 +123,25 @@ func (tm *TaskManager) AddTask(info plugins.TaskInfo) error {
 	return nil
 }
 
-// executeTask 执行任务
-func (tm *TaskManager) executeTask(task plugins.Task, info plugins.TaskInfo) {
+// executeTask 在 parent 下执行任务，同一任务正在执行时返回错误，避免定时调度与手动执行重叠
+// 执行信息通过 ctx 传给任务，交易类插件据此生成幂等的订单号
+func (tm *TaskManager) executeTask(parent context.Context, task plugins.Task, info plugins.TaskInfo, run plugins.RunInfo) (plugins.TaskResult, error) {
+	tm.mu.Lock()
+	if tm.running[info.Name] {
+		tm.mu.Unlock()
+		return plugins.TaskResult{}, fmt.Errorf("任务正在执行: %s", info.Name)
+	}
+	tm.running[info.Name] = true
+	tm.mu.Unlock()
+
 	startTime := time.Now()
 	result := plugins.TaskResult{
 		TaskName:  info.Name,
 		StartTime: startTime,
 	}
 
-	// 创建带超时的上下文
-	ctx, cancel := context.WithTimeout(tm.ctx, 30*time.Second)
+	// 创建带超时的上下文，任务可通过 TimeoutTask 指定超时时间
+	ctx, cancel := context.WithTimeout(plugins.WithRunInfo(parent, run), taskTimeout(task))
 	defer cancel()
 
 	// 执行任务
@@ -123,12 +161,107 @@ func (tm *TaskManager) executeTask(task plugins.Task, info plugins.TaskInfo) {
 
 	// 保存执行结果
 	tm.mu.Lock()
+	delete(tm.running, info.Name)
 	tm.results = append(tm.results, result)
 	// 只保留最近100条记录
 	if len(tm.results) > 100 {
 		tm.results = tm.results[len(tm.results)-100:]
 	}
 	tm.mu.Unlock()
+
+	return result, nil
+}
+
+// taskTimeout 返回任务单次执行的超时时间
+func taskTimeout(task plugins.Task) time.Duration {
+	if timeoutTask, ok := task.(plugins.TimeoutTask); ok && timeoutTask.Timeout() > 0 {
+		return timeoutTask.Timeout()
+	}
+	return defaultTaskTimeout
+}
+
+// RunTask 立即执行任务并等待完成，暂停的任务同样可以手动执行
+// ctx 或任务管理器停止时取消执行，命令接收服务停止时据此结束执行中的手动任务
+func (tm *TaskManager) RunTask(ctx context.Context, name string) (plugins.TaskResult, error) {
+	tm.mu.RLock()
+	managedTask, exists := tm.tasks[name]
+	tm.mu.RUnlock()
+	if !exists {
+		return plugins.TaskResult{}, fmt.Errorf("任务不存在: %s", name)
+	}
+
+	log.Printf("手动执行任务: %s", name)
+	run := plugins.RunInfo{TaskName: name, ScheduledTime: time.Now().Truncate(time.Second), Manual: true}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(tm.ctx, cancel)
+	defer stop()
+	return tm.executeTask(ctx, managedTask.Task, managedTask.Info, run)
+}
+
+// PauseTask 暂停任务的定时调度
+func (tm *TaskManager) PauseTask(name string) error {
+	return tm.setPaused(name, true)
+}
+
+// ResumeTask 恢复任务的定时调度
+func (tm *TaskManager) ResumeTask(name string) error {
+	return tm.setPaused(name, false)
+}
+
+// setPaused 设置任务暂停状态
+func (tm *TaskManager) setPaused(name string, paused bool) error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	managedTask, exists := tm.tasks[name]
+	if !exists {
+		return fmt.Errorf("任务不存在: %s", name)
+	}
+	managedTask.Paused = paused
+	if paused {
+		log.Printf("任务已暂停: %s", name)
+	} else {
+		log.Printf("任务已恢复: %s", name)
+	}
+	return nil
+}
+
+// isPaused 判断任务是否已暂停
+func (tm *TaskManager) isPaused(name string) bool {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	managedTask, exists := tm.tasks[name]
+	return exists && managedTask.Paused
+}
+
+// GetStatus 获取所有任务的状态，按任务名称排序
+func (tm *TaskManager) GetStatus() []TaskStatus {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	statuses := make([]TaskStatus, 0, len(tm.tasks))
+	for name, managedTask := range tm.tasks {
+		status := TaskStatus{
+			Name:     name,
+			Schedule: managedTask.Info.Schedule,
+			Paused:   managedTask.Paused,
+			Running:  tm.running[name],
+			NextRun:  tm.cron.Entry(managedTask.EntryID).Next,
+		}
+		for i := len(tm.results) - 1; i >= 0; i-- {
+			if tm.results[i].TaskName == name {
+				result := tm.results[i]
+				status.LastResult = &result
+				break
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
 }
 
 // Start 启动任务管理器
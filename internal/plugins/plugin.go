@@ -17,6 +17,13 @@ type Task interface {
 	ValidateConfig(config map[string]interface{}) error
 }
 
+// TimeoutTask 需要自定义单次执行超时时间的任务，如等待订单成交的交易任务
+// 未实现时任务管理器使用默认超时时间
+type TimeoutTask interface {
+	// Timeout 返回单次执行的超时时间
+	Timeout() time.Duration
+}
+
 // Plugin 定义插件接口
 type Plugin interface {
 	// Name 返回插件名称
@@ -46,3 +53,24 @@ type TaskResult struct {
 	Success   bool          `json:"success"`
 	Error     string        `json:"error,omitempty"`
 }
+
+// RunInfo 单次执行的信息，通过 Execute 的 ctx 传给任务
+type RunInfo struct {
+	TaskName      string    `json:"task_name"`
+	ScheduledTime time.Time `json:"scheduled_time"` // 定时调度的计划时间，手动执行时为触发时间
+	Manual        bool      `json:"manual"`         // 是否手动执行
+}
+
+// runInfoKey RunInfo 在 context 中的键
+type runInfoKey struct{}
+
+// WithRunInfo 在 context 中附加执行信息
+func WithRunInfo(ctx context.Context, info RunInfo) context.Context {
+	return context.WithValue(ctx, runInfoKey{}, info)
+}
+
+// RunInfoFromContext 读取执行信息，未附加时返回 false
+func RunInfoFromContext(ctx context.Context) (RunInfo, bool) {
+	info, ok := ctx.Value(runInfoKey{}).(RunInfo)
+	return info, ok
+}
@@ -5,6 +5,8 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"task_scheduler/internal/bot"
+	"task_scheduler/internal/cli"
 	"task_scheduler/internal/config"
 	"task_scheduler/internal/core"
 	"task_scheduler/pkg/ccxt"
@@ -13,6 +15,14 @@ import (
 )
 
 func main() {
+	// 子命令（如 history）执行后直接退出
+	if len(os.Args) > 1 && cli.IsCommand(os.Args[1]) {
+		if err := cli.Run(os.Args[1:], os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	// main_autoBuy()
 	// main_ccxt()
 	main_main()
@@ -54,7 +64,7 @@ func main_main() {
 	// 注册插件
 	// taskManager.RegisterPlugin(app1.NewPlugin())
 	// taskManager.RegisterPlugin(app2.NewPlugin())
-	taskManager.RegisterPlugin(autobuy.NewPlugin())
+	taskManager.RegisterPlugin(autobuy.NewPluginWithPushConfig(mainConfig.Push.PushAPIConfig()))
 
 	// 加载所有任务配置
 	tasks, err := loader.LoadAllTasks(mainConfig)
@@ -73,6 +83,22 @@ func main_main() {
 	// 启动任务管理器
 	taskManager.Start()
 
+	// 启动聊天机器人命令接收
+	commandServer, err := bot.NewServer(mainConfig.Inbound)
+	if err != nil {
+		log.Fatalf("创建命令接收服务失败: %v", err)
+	}
+	if commandServer != nil {
+		exchange, err := bot.NewExchange(mainConfig.Inbound)
+		if err != nil {
+			log.Printf("创建交易所失败，/balance 不可用: %v", err)
+		}
+		bot.RegisterCommands(commandServer, taskManager, exchange)
+		if err := commandServer.Start(); err != nil {
+			log.Fatalf("启动命令接收服务失败: %v", err)
+		}
+	}
+
 	// 测试代码
 	// taskMap := taskManager.GetTasks()
 	// log.Println("taskMap: ", taskMap)
@@ -87,6 +113,9 @@ func main_main() {
 
 	// 优雅停止
 	log.Println("正在停止定时任务调度器...")
+	if commandServer != nil {
+		commandServer.Stop()
+	}
 	taskManager.Stop()
 	log.Println("定时任务调度器已停止")
 
@@ -0,0 +1,316 @@
+package ccxt
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+	"github.com/shopspring/decimal"
+)
+
+// binanceName 币安在注册表中的名称
+const binanceName = "binance"
+
+// Client 实现 Exchange 接口
+var _ Exchange = (*Client)(nil)
+
+func init() {
+	RegisterExchange(binanceName, func(cfg ExchangeConfig) (Exchange, error) {
+		baseURL := cfg.BaseURL
+		if baseURL == "" {
+			baseURL = optionString(cfg.Options, "base_url", "")
+		}
+		client, err := NewClientWithConfig(ClientConfig{
+			APIKey:     cfg.APIKey,
+			SecretKey:  cfg.SecretKey,
+			ProxyURL:   cfg.ProxyURL,
+			BaseURL:    baseURL,
+			HTTPClient: cfg.HTTPClient,
+			Prices:     cfg.Prices,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	})
+}
+
+// Name 返回交易所名称
+func (c *Client) Name() string {
+	return binanceName
+}
+
+// GetPrice 获取交易对的最新成交价
+func (c *Client) GetPrice(ctx context.Context, symbol string) (float64, error) {
+	price, err := c.GetLatestPrice(ctx, symbol)
+	if err != nil {
+		return 0, err
+	}
+	priceFloat, err := parseFloat(price.Price)
+	if err != nil {
+		return 0, fmt.Errorf("解析%s价格失败: %w", symbol, err)
+	}
+	return priceFloat, nil
+}
+
+// GetBookTicker 获取交易对的最优挂单，设置了行情缓存时优先读取缓存
+func (c *Client) GetBookTicker(ctx context.Context, symbol string) (*BookTicker, error) {
+	if c.prices != nil {
+		if ticker, ok := c.prices.BookTicker(symbol); ok {
+			return ticker, nil
+		}
+	}
+
+	tickers, err := c.spotClient.NewListBookTickersService().Symbol(symbol).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取%s订单盘口失败: %w", symbol, binanceError(err))
+	}
+	if len(tickers) == 0 {
+		return nil, fmt.Errorf("未找到%s的订单盘口", symbol)
+	}
+
+	ticker := tickers[0]
+	return &BookTicker{
+		Symbol:   ticker.Symbol,
+		BidPrice: parseNumber(ticker.BidPrice),
+		BidQty:   parseNumber(ticker.BidQuantity),
+		AskPrice: parseNumber(ticker.AskPrice),
+		AskQty:   parseNumber(ticker.AskQuantity),
+	}, nil
+}
+
+// GetKlines 获取K线数据
+func (c *Client) GetKlines(ctx context.Context, query KlineQuery) ([]Kline, error) {
+	service := c.spotClient.NewKlinesService().Symbol(query.Symbol).Interval(query.Interval)
+	if !query.StartTime.IsZero() {
+		service.StartTime(query.StartTime.UnixMilli())
+	}
+	if !query.EndTime.IsZero() {
+		service.EndTime(query.EndTime.UnixMilli())
+	}
+	if query.Limit > 0 {
+		service.Limit(query.Limit)
+	}
+
+	klines, err := service.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取%s K线数据失败: %w", query.Symbol, binanceError(err))
+	}
+
+	result := make([]Kline, 0, len(klines))
+	for _, kline := range klines {
+		result = append(result, Kline{
+			OpenTime:  time.UnixMilli(kline.OpenTime),
+			Open:      parseNumber(kline.Open),
+			High:      parseNumber(kline.High),
+			Low:       parseNumber(kline.Low),
+			Close:     parseNumber(kline.Close),
+			Volume:    parseNumber(kline.Volume),
+			CloseTime: time.UnixMilli(kline.CloseTime),
+		})
+	}
+	return result, nil
+}
+
+// GetSymbolInfo 获取交易对的下单规则，首次查询后缓存
+func (c *Client) GetSymbolInfo(ctx context.Context, symbol string) (*SymbolInfo, error) {
+	c.symbolsMu.Lock()
+	info, exists := c.symbols[symbol]
+	c.symbolsMu.Unlock()
+	if exists {
+		return info, nil
+	}
+
+	exchangeInfo, err := c.spotClient.NewExchangeInfoService().Symbol(symbol).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取%s交易规则失败: %w", symbol, binanceError(err))
+	}
+	if len(exchangeInfo.Symbols) == 0 {
+		return nil, fmt.Errorf("%w: 交易对不存在 %s", ErrInvalidOrder, symbol)
+	}
+
+	s := exchangeInfo.Symbols[0]
+	info = &SymbolInfo{
+		Symbol:         s.Symbol,
+		BaseAsset:      s.BaseAsset,
+		QuoteAsset:     s.QuoteAsset,
+		QuotePrecision: int32(s.QuoteAssetPrecision),
+	}
+	if filter := s.PriceFilter(); filter != nil {
+		info.TickSize = parseDecimal(filter.TickSize)
+		info.MinPrice = parseDecimal(filter.MinPrice)
+		info.MaxPrice = parseDecimal(filter.MaxPrice)
+	}
+	if filter := s.LotSizeFilter(); filter != nil {
+		info.StepSize = parseDecimal(filter.StepSize)
+		info.MinQty = parseDecimal(filter.MinQuantity)
+		info.MaxQty = parseDecimal(filter.MaxQuantity)
+	}
+	if filter := s.NotionalFilter(); filter != nil {
+		info.MinNotional = parseDecimal(filter.MinNotional)
+	}
+
+	c.symbolsMu.Lock()
+	if c.symbols == nil {
+		c.symbols = make(map[string]*SymbolInfo)
+	}
+	c.symbols[symbol] = info
+	c.symbolsMu.Unlock()
+	return info, nil
+}
+
+// GetBalances 获取账户中余额不为零的资产
+func (c *Client) GetBalances(ctx context.Context) ([]Balance, error) {
+	account, err := c.GetAccountBalance(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return account.Balances, nil
+}
+
+// PlaceOrder 下单，限价单使用 GTC，价格和数量按交易规则舍入
+func (c *Client) PlaceOrder(ctx context.Context, req OrderRequest) (*Order, error) {
+	// 按交易规则舍入并预先检查，避免提交后才被交易所拒绝
+	info, err := c.GetSymbolInfo(ctx, req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	req = info.Normalize(req)
+	if err := info.Validate(req); err != nil {
+		return nil, err
+	}
+
+	service := c.spotClient.NewCreateOrderService().
+		Symbol(req.Symbol).
+		Side(binance.SideType(req.Side)).
+		Type(binance.OrderType(req.Type))
+	if req.Quantity > 0 {
+		service.Quantity(info.RoundQuantity(req.Quantity).String())
+	}
+	if req.QuoteQuantity > 0 {
+		service.QuoteOrderQty(info.RoundQuoteQuantity(req.QuoteQuantity).String())
+	}
+	if req.Type == OrderTypeLimit {
+		service.Price(info.RoundPrice(req.Price).String()).TimeInForce(binance.TimeInForceTypeGTC)
+	}
+	if req.ClientOrderID != "" {
+		service.NewClientOrderID(req.ClientOrderID)
+	}
+
+	resp, err := service.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s下单失败: %w", req.Symbol, binanceError(err))
+	}
+	order := &Order{
+		ID:            strconv.FormatInt(resp.OrderID, 10),
+		ClientOrderID: resp.ClientOrderID,
+		Symbol:        resp.Symbol,
+		Side:          OrderSide(resp.Side),
+		Type:          OrderType(resp.Type),
+		Status:        OrderStatus(resp.Status),
+		Price:         parseNumber(resp.Price),
+		Quantity:      parseNumber(resp.OrigQuantity),
+		ExecutedQty:   parseNumber(resp.ExecutedQuantity),
+		QuoteQty:      parseNumber(resp.CummulativeQuoteQuantity),
+		CreatedAt:     time.UnixMilli(resp.TransactTime),
+	}
+	// FULL 响应中包含每笔成交的手续费
+	for _, fill := range resp.Fills {
+		if order.Fees == nil {
+			order.Fees = make(map[string]float64)
+		}
+		order.Fees[fill.CommissionAsset] += parseNumber(fill.Commission)
+	}
+	return order.withAvgPrice(), nil
+}
+
+// GetOrder 查询订单
+func (c *Client) GetOrder(ctx context.Context, symbol, orderID string) (*Order, error) {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 无效的订单号 %s", ErrInvalidOrder, orderID)
+	}
+
+	resp, err := c.spotClient.NewGetOrderService().Symbol(symbol).OrderID(id).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("查询订单%s失败: %w", orderID, binanceError(err))
+	}
+	return binanceOrder(resp), nil
+}
+
+// GetOrderByClientID 按客户端订单号查询订单
+func (c *Client) GetOrderByClientID(ctx context.Context, symbol, clientOrderID string) (*Order, error) {
+	resp, err := c.spotClient.NewGetOrderService().Symbol(symbol).OrigClientOrderID(clientOrderID).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("查询订单%s失败: %w", clientOrderID, binanceError(err))
+	}
+	return binanceOrder(resp), nil
+}
+
+// CancelOrder 撤销订单
+func (c *Client) CancelOrder(ctx context.Context, symbol, orderID string) (*Order, error) {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 无效的订单号 %s", ErrInvalidOrder, orderID)
+	}
+
+	resp, err := c.spotClient.NewCancelOrderService().Symbol(symbol).OrderID(id).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("撤销订单%s失败: %w", orderID, binanceError(err))
+	}
+	return (&Order{
+		ID:            strconv.FormatInt(resp.OrderID, 10),
+		ClientOrderID: resp.OrigClientOrderID,
+		Symbol:        resp.Symbol,
+		Side:          OrderSide(resp.Side),
+		Type:          OrderType(resp.Type),
+		Status:        OrderStatus(resp.Status),
+		Price:         parseNumber(resp.Price),
+		Quantity:      parseNumber(resp.OrigQuantity),
+		ExecutedQty:   parseNumber(resp.ExecutedQuantity),
+		QuoteQty:      parseNumber(resp.CummulativeQuoteQuantity),
+		CreatedAt:     time.UnixMilli(resp.TransactTime),
+	}).withAvgPrice(), nil
+}
+
+// binanceOrder 转换查询订单的响应
+func binanceOrder(resp *binance.Order) *Order {
+	return (&Order{
+		ID:            strconv.FormatInt(resp.OrderID, 10),
+		ClientOrderID: resp.ClientOrderID,
+		Symbol:        resp.Symbol,
+		Side:          OrderSide(resp.Side),
+		Type:          OrderType(resp.Type),
+		Status:        OrderStatus(resp.Status),
+		Price:         parseNumber(resp.Price),
+		Quantity:      parseNumber(resp.OrigQuantity),
+		ExecutedQty:   parseNumber(resp.ExecutedQuantity),
+		QuoteQty:      parseNumber(resp.CummulativeQuoteQuantity),
+		CreatedAt:     time.UnixMilli(resp.Time),
+	}).withAvgPrice()
+}
+
+// parseNumber 解析交易所返回的数字字符串，无效时返回0
+func parseNumber(s string) float64 {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
+// parseDecimal 解析交易规则中的数字字符串，无效时返回0
+func parseDecimal(s string) decimal.Decimal {
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Zero
+	}
+	return d
+}
+
+// formatNumber 格式化数字，不使用科学计数法
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
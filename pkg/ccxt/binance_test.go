@@ -0,0 +1,75 @@
+package ccxt_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"task_scheduler/pkg/ccxt"
+	"task_scheduler/pkg/ccxt/binancetest"
+)
+
+func TestBinanceClientOffline(t *testing.T) {
+	ctx := context.Background()
+	server := binancetest.NewServer()
+	defer server.Close()
+	server.AddSymbol(binancetest.Symbol{Symbol: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT", TickSize: "0.01", StepSize: "0.00001", MinNotional: "5", QuoteAssetPrecision: 8})
+	server.SetBookTicker("BTCUSDT", 99990, 100000)
+	server.SetBalance("USDT", 1000)
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	server.SetKlines("BTCUSDT", []binancetest.Kline{{OpenTime: day, Close: 95000}, {OpenTime: day.AddDate(0, 0, 1), Close: 97000}})
+
+	exchange, err := ccxt.NewExchange("binance", ccxt.ExchangeConfig{APIKey: "KEY", SecretKey: "SECRET", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	if requests := server.Requests(); len(requests) != 0 {
+		t.Errorf("创建客户端时不应访问网络: %v", requests)
+	}
+
+	if ticker, err := exchange.GetBookTicker(ctx, "BTCUSDT"); err != nil || ticker.AskPrice != 100000 {
+		t.Errorf("最优挂单不正确: %+v %v", ticker, err)
+	}
+	klines, err := exchange.GetKlines(ctx, ccxt.KlineQuery{Symbol: "BTCUSDT", Interval: "1d", StartTime: day.AddDate(0, 0, 1)})
+	if err != nil || len(klines) != 1 || klines[0].Close != 97000 {
+		t.Errorf("K线不正确: %+v %v", klines, err)
+	}
+
+	// 市价单按金额买入，立即成交
+	order, err := exchange.PlaceOrder(ctx, ccxt.OrderRequest{Symbol: "BTCUSDT", Side: ccxt.SideBuy, Type: ccxt.OrderTypeMarket, QuoteQuantity: 100, ClientOrderID: "auto-buy-1"})
+	if err != nil || !order.Filled() || order.AvgPrice != 100000 {
+		t.Fatalf("市价单应立即成交: %+v %v", order, err)
+	}
+	if found, err := exchange.GetOrderByClientID(ctx, "BTCUSDT", "auto-buy-1"); err != nil || found.ID != order.ID {
+		t.Errorf("按客户端订单号查询失败: %+v %v", found, err)
+	}
+	if _, err := exchange.GetOrderByClientID(ctx, "BTCUSDT", "missing"); !errors.Is(err, ccxt.ErrOrderNotFound) {
+		t.Errorf("不存在的订单应返回 ErrOrderNotFound: %v", err)
+	}
+
+	// 低于卖一价的限价单挂单，撤单后解冻资金
+	order, err = exchange.PlaceOrder(ctx, ccxt.OrderRequest{Symbol: "BTCUSDT", Side: ccxt.SideBuy, Type: ccxt.OrderTypeLimit, Quantity: 0.001, Price: 90000})
+	if err != nil || order.Status != ccxt.OrderStatusNew {
+		t.Fatalf("限价单应挂单: %+v %v", order, err)
+	}
+	if order, err = exchange.CancelOrder(ctx, "BTCUSDT", order.ID); err != nil || order.Status != ccxt.OrderStatusCanceled {
+		t.Errorf("撤单失败: %+v %v", order, err)
+	}
+	balances, err := exchange.GetBalances(ctx)
+	if err != nil {
+		t.Fatalf("查询余额失败: %v", err)
+	}
+	if usdt, btc := ccxt.FindBalance(balances, "USDT"), ccxt.FindBalance(balances, "BTC"); usdt.Free != 900 || usdt.Locked != 0 || btc.Free != 0.001 {
+		t.Errorf("余额不正确: %+v %+v", usdt, btc)
+	}
+
+	// 预设的错误按分类返回
+	server.Fail("POST", "/api/v3/order", 400, -2010, "Account has insufficient balance for requested action.")
+	if _, err := exchange.PlaceOrder(ctx, ccxt.OrderRequest{Symbol: "BTCUSDT", Side: ccxt.SideBuy, Type: ccxt.OrderTypeMarket, QuoteQuantity: 100}); !errors.Is(err, ccxt.ErrInsufficientBalance) {
+		t.Errorf("应返回 ErrInsufficientBalance: %v", err)
+	}
+	if _, err := exchange.PlaceOrder(ctx, ccxt.OrderRequest{Symbol: "BTCUSDT", Side: ccxt.SideBuy, Type: ccxt.OrderTypeMarket, QuoteQuantity: 4}); !errors.Is(err, ccxt.ErrMinNotional) {
+		t.Errorf("低于最小金额应返回 ErrMinNotional: %v", err)
+	}
+}
@@ -0,0 +1,742 @@
+// Package binancetest 提供基于 httptest 的币安现货 REST 模拟服务器，用于离线测试 ccxt.Client 及依赖它的插件
+package binancetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Kline 模拟的K线
+type Kline struct {
+	OpenTime  time.Time
+	Open      float64
+	High      float64
+	Low       float64
+	Close     float64
+	Volume    float64
+	CloseTime time.Time // 为零时取 OpenTime 加一天减1毫秒
+}
+
+// Symbol 交易对及其下单规则，规则为空字符串时不下发对应过滤器
+type Symbol struct {
+	Symbol              string
+	BaseAsset           string
+	QuoteAsset          string
+	TickSize            string // PRICE_FILTER 价格步长
+	StepSize            string // LOT_SIZE 数量步长
+	MinQty              string // LOT_SIZE 最小数量
+	MinNotional         string // NOTIONAL 最小下单金额
+	QuoteAssetPrecision int
+}
+
+// Order 服务器上的订单
+type Order struct {
+	ID            int64
+	ClientOrderID string
+	Symbol        string
+	Side          string
+	Type          string
+	Status        string
+	Price         float64
+	Quantity      float64
+	ExecutedQty   float64
+	QuoteQty      float64
+	Time          time.Time
+
+	locked float64 // 挂单冻结的资产数量
+}
+
+// failure 预设的错误响应
+type failure struct {
+	status  int
+	code    int64
+	message string
+	header  http.Header
+}
+
+// balance 账户余额
+type balance struct {
+	free   float64
+	locked float64
+}
+
+// ticker 行情
+type ticker struct {
+	price float64
+	bid   float64
+	ask   float64
+}
+
+// Server 模拟币安现货 REST 接口的测试服务器
+// 覆盖 ping、服务器时间、最新价、最优挂单、K线、交易规则、账户和订单接口；
+// 市价单按最优挂单立即成交，限价单价格可成交时立即成交，否则挂单直到调用 FillOrder 或撤单
+type Server struct {
+	URL        string  // 服务器地址，作为客户端的 BaseURL
+	Commission float64 // 成交手续费率，从收到的资产中扣除，默认0
+
+	server *httptest.Server
+
+	mu        sync.Mutex
+	now       func() time.Time
+	symbols   map[string]Symbol
+	tickers   map[string]*ticker
+	klines    map[string][]Kline
+	balances  map[string]*balance
+	orders    []*Order
+	nextID    int64
+	failures  map[string][]failure
+	overrides map[string]http.HandlerFunc
+	requests  []string
+	weight    int
+}
+
+// NewServer 启动模拟服务器，使用完毕后调用 Close
+func NewServer() *Server {
+	s := &Server{
+		now:       time.Now,
+		symbols:   make(map[string]Symbol),
+		tickers:   make(map[string]*ticker),
+		klines:    make(map[string][]Kline),
+		balances:  make(map[string]*balance),
+		nextID:    1,
+		failures:  make(map[string][]failure),
+		overrides: make(map[string]http.HandlerFunc),
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.server.URL
+	return s
+}
+
+// Close 关闭服务器
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Client 返回访问服务器的 HTTP 客户端
+func (s *Server) Client() *http.Client {
+	return s.server.Client()
+}
+
+// SetClock 设置服务器时间
+func (s *Server) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+// AddSymbol 添加交易对及其下单规则
+func (s *Server) AddSymbol(symbol Symbol) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.symbols[symbol.Symbol] = symbol
+}
+
+// SetPrice 设置最新成交价，未设置最优挂单时买一卖一均取该价格
+func (s *Server) SetPrice(symbol string, price float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.ticker(symbol)
+	t.price = price
+	if t.bid == 0 && t.ask == 0 {
+		t.bid, t.ask = price, price
+	}
+}
+
+// SetBookTicker 设置最优挂单
+func (s *Server) SetBookTicker(symbol string, bid, ask float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.ticker(symbol)
+	t.bid, t.ask = bid, ask
+	if t.price == 0 {
+		t.price = (bid + ask) / 2
+	}
+}
+
+// SetKlines 设置K线，按开盘时间排序
+func (s *Server) SetKlines(symbol string, klines []Kline) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sorted := append([]Kline(nil), klines...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].OpenTime.Before(sorted[j].OpenTime) })
+	s.klines[symbol] = sorted
+}
+
+// SetBalance 设置资产的可用余额
+func (s *Server) SetBalance(asset string, free float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.balance(asset).free = free
+}
+
+// Balance 返回资产的可用和冻结余额
+func (s *Server) Balance(asset string) (free, locked float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b := s.balance(asset)
+	return b.free, b.locked
+}
+
+// Orders 返回服务器上的全部订单
+func (s *Server) Orders() []Order {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	orders := make([]Order, 0, len(s.orders))
+	for _, order := range s.orders {
+		orders = append(orders, *order)
+	}
+	return orders
+}
+
+// FillOrder 以挂单价格成交未成交的限价单
+func (s *Server) FillOrder(orderID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, order := range s.orders {
+		if order.ID == orderID {
+			if order.Status != "NEW" {
+				return fmt.Errorf("订单%d状态为%s，无法成交", orderID, order.Status)
+			}
+			s.unlock(order)
+			s.fill(order, order.Price)
+			return nil
+		}
+	}
+	return fmt.Errorf("订单不存在: %d", orderID)
+}
+
+// Fail 让接口的下一次请求返回币安格式的错误，多次调用依次生效
+// 如 Fail("POST", "/api/v3/order", 400, -2010, "Account has insufficient balance for requested action.")
+func (s *Server) Fail(method, path string, status int, code int64, message string) {
+	s.FailWithHeader(method, path, status, code, message, nil)
+}
+
+// FailWithHeader 同 Fail，并在响应中附加头部（如 429 时的 Retry-After）
+func (s *Server) FailWithHeader(method, path string, status int, code int64, message string, header http.Header) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := method + " " + path
+	s.failures[key] = append(s.failures[key], failure{status: status, code: code, message: message, header: header})
+}
+
+// Handle 用自定义处理函数替换接口的响应
+func (s *Server) Handle(method, path string, handler http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.overrides[method+" "+path] = handler
+}
+
+// SetUsedWeight 设置响应头 X-MBX-USED-WEIGHT-1M 返回的已用请求权重，为0时不返回
+func (s *Server) SetUsedWeight(weight int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.weight = weight
+}
+
+// Requests 返回收到的请求，格式为 "GET /api/v3/ping"
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// serveHTTP 分发请求
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	key := r.Method + " " + r.URL.Path
+
+	s.mu.Lock()
+	s.requests = append(s.requests, key)
+	if s.weight > 0 {
+		w.Header().Set("X-MBX-USED-WEIGHT-1M", strconv.Itoa(s.weight))
+	}
+	override := s.overrides[key]
+	var fail *failure
+	if queued := s.failures[key]; len(queued) > 0 {
+		fail = &queued[0]
+		s.failures[key] = queued[1:]
+	}
+	s.mu.Unlock()
+
+	if fail != nil {
+		for name, values := range fail.header {
+			for _, value := range values {
+				w.Header().Add(name, value)
+			}
+		}
+		writeError(w, fail.status, fail.code, fail.message)
+		return
+	}
+	if override != nil {
+		override(w, r)
+		return
+	}
+
+	params, err := requestParams(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, -1100, "Illegal characters found in parameter.")
+		return
+	}
+	if signedEndpoint(key) && r.Header.Get("X-MBX-APIKEY") == "" {
+		writeError(w, http.StatusUnauthorized, -2014, "API-key format invalid.")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch key {
+	case "GET /api/v3/ping":
+		writeJSON(w, map[string]interface{}{})
+	case "GET /api/v3/time":
+		writeJSON(w, map[string]int64{"serverTime": s.now().UnixMilli()})
+	case "GET /api/v3/ticker/price":
+		s.handlePrice(w, params)
+	case "GET /api/v3/ticker/bookTicker":
+		s.handleBookTicker(w, params)
+	case "GET /api/v3/klines":
+		s.handleKlines(w, params)
+	case "GET /api/v3/exchangeInfo":
+		s.handleExchangeInfo(w, params)
+	case "GET /api/v3/account":
+		s.handleAccount(w, params)
+	case "POST /api/v3/order":
+		s.handleCreateOrder(w, params)
+	case "GET /api/v3/order":
+		s.handleGetOrder(w, params)
+	case "DELETE /api/v3/order":
+		s.handleCancelOrder(w, params)
+	default:
+		writeError(w, http.StatusNotFound, -1000, "Unknown endpoint: "+key)
+	}
+}
+
+// handlePrice 最新价，支持 symbol 和 symbols 参数，都不带时返回全部
+func (s *Server) handlePrice(w http.ResponseWriter, params url.Values) {
+	price := func(symbol string, t *ticker) map[string]string {
+		return map[string]string{"symbol": symbol, "price": formatFloat(t.price)}
+	}
+	if symbol := params.Get("symbol"); symbol != "" {
+		t, exists := s.tickers[symbol]
+		if !exists {
+			writeError(w, http.StatusBadRequest, -1121, "Invalid symbol.")
+			return
+		}
+		writeJSON(w, price(symbol, t))
+		return
+	}
+
+	symbols := sortedKeys(s.tickers)
+	if raw := params.Get("symbols"); raw != "" {
+		symbols = nil
+		if err := json.Unmarshal([]byte(raw), &symbols); err != nil {
+			writeError(w, http.StatusBadRequest, -1100, "Illegal characters found in parameter 'symbols'.")
+			return
+		}
+	}
+	prices := make([]map[string]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		t, exists := s.tickers[symbol]
+		if !exists {
+			writeError(w, http.StatusBadRequest, -1121, "Invalid symbol.")
+			return
+		}
+		prices = append(prices, price(symbol, t))
+	}
+	writeJSON(w, prices)
+}
+
+// handleBookTicker 最优挂单
+func (s *Server) handleBookTicker(w http.ResponseWriter, params url.Values) {
+	symbol := params.Get("symbol")
+	t, exists := s.tickers[symbol]
+	if !exists {
+		writeError(w, http.StatusBadRequest, -1121, "Invalid symbol.")
+		return
+	}
+	writeJSON(w, map[string]string{
+		"symbol":   symbol,
+		"bidPrice": formatFloat(t.bid),
+		"bidQty":   "1",
+		"askPrice": formatFloat(t.ask),
+		"askQty":   "1",
+	})
+}
+
+// handleKlines K线，支持 startTime、endTime 和 limit
+func (s *Server) handleKlines(w http.ResponseWriter, params url.Values) {
+	startTime, _ := strconv.ParseInt(params.Get("startTime"), 10, 64)
+	endTime, _ := strconv.ParseInt(params.Get("endTime"), 10, 64)
+	limit, _ := strconv.Atoi(params.Get("limit"))
+	if limit <= 0 {
+		limit = 500
+	}
+
+	rows := make([][]interface{}, 0)
+	for _, kline := range s.klines[params.Get("symbol")] {
+		openTime := kline.OpenTime.UnixMilli()
+		if (startTime > 0 && openTime < startTime) || (endTime > 0 && openTime > endTime) {
+			continue
+		}
+		closeTime := kline.CloseTime
+		if closeTime.IsZero() {
+			closeTime = kline.OpenTime.Add(24*time.Hour - time.Millisecond)
+		}
+		rows = append(rows, []interface{}{
+			openTime, formatFloat(kline.Open), formatFloat(kline.High), formatFloat(kline.Low), formatFloat(kline.Close),
+			formatFloat(kline.Volume), closeTime.UnixMilli(), "0", 0, "0", "0", "0",
+		})
+		if len(rows) == limit {
+			break
+		}
+	}
+	writeJSON(w, rows)
+}
+
+// handleExchangeInfo 交易规则
+func (s *Server) handleExchangeInfo(w http.ResponseWriter, params url.Values) {
+	names := sortedKeys(s.symbols)
+	if symbol := params.Get("symbol"); symbol != "" {
+		if _, exists := s.symbols[symbol]; !exists {
+			writeError(w, http.StatusBadRequest, -1121, "Invalid symbol.")
+			return
+		}
+		names = []string{symbol}
+	}
+
+	symbols := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		symbol := s.symbols[name]
+		filters := []map[string]interface{}{}
+		if symbol.TickSize != "" {
+			filters = append(filters, map[string]interface{}{"filterType": "PRICE_FILTER", "minPrice": symbol.TickSize, "maxPrice": "1000000.00", "tickSize": symbol.TickSize})
+		}
+		if symbol.StepSize != "" {
+			minQty := symbol.MinQty
+			if minQty == "" {
+				minQty = symbol.StepSize
+			}
+			filters = append(filters, map[string]interface{}{"filterType": "LOT_SIZE", "minQty": minQty, "maxQty": "9000.00000000", "stepSize": symbol.StepSize})
+		}
+		if symbol.MinNotional != "" {
+			filters = append(filters, map[string]interface{}{"filterType": "NOTIONAL", "minNotional": symbol.MinNotional, "applyMinToMarket": true, "maxNotional": "9000000.00", "applyMaxToMarket": false, "avgPriceMins": 5})
+		}
+		symbols = append(symbols, map[string]interface{}{
+			"symbol":              symbol.Symbol,
+			"status":              "TRADING",
+			"baseAsset":           symbol.BaseAsset,
+			"quoteAsset":          symbol.QuoteAsset,
+			"quoteAssetPrecision": symbol.QuoteAssetPrecision,
+			"orderTypes":          []string{"LIMIT", "MARKET"},
+			"filters":             filters,
+		})
+	}
+	writeJSON(w, map[string]interface{}{
+		"timezone":   "UTC",
+		"serverTime": s.now().UnixMilli(),
+		"symbols":    symbols,
+	})
+}
+
+// handleAccount 账户信息
+func (s *Server) handleAccount(w http.ResponseWriter, params url.Values) {
+	omitZero := params.Get("omitZeroBalances") == "true"
+	balances := make([]map[string]string, 0, len(s.balances))
+	for _, asset := range sortedKeys(s.balances) {
+		b := s.balances[asset]
+		if omitZero && b.free == 0 && b.locked == 0 {
+			continue
+		}
+		balances = append(balances, map[string]string{"asset": asset, "free": formatFloat(b.free), "locked": formatFloat(b.locked)})
+	}
+	writeJSON(w, map[string]interface{}{
+		"canTrade":    true,
+		"canWithdraw": true,
+		"canDeposit":  true,
+		"accountType": "SPOT",
+		"updateTime":  s.now().UnixMilli(),
+		"balances":    balances,
+	})
+}
+
+// handleCreateOrder 下单
+func (s *Server) handleCreateOrder(w http.ResponseWriter, params url.Values) {
+	symbolName := params.Get("symbol")
+	symbol, exists := s.symbols[symbolName]
+	t := s.tickers[symbolName]
+	if !exists || t == nil {
+		writeError(w, http.StatusBadRequest, -1121, "Invalid symbol.")
+		return
+	}
+	clientOrderID := params.Get("newClientOrderId")
+	if clientOrderID == "" {
+		clientOrderID = fmt.Sprintf("binancetest-%d", s.nextID)
+	}
+	for _, order := range s.orders {
+		if order.ClientOrderID == clientOrderID && order.Status == "NEW" {
+			writeError(w, http.StatusBadRequest, -2010, "Duplicate order sent.")
+			return
+		}
+	}
+
+	order := &Order{
+		ID:            s.nextID,
+		ClientOrderID: clientOrderID,
+		Symbol:        symbolName,
+		Side:          params.Get("side"),
+		Type:          params.Get("type"),
+		Status:        "NEW",
+		Time:          s.now(),
+	}
+	order.Price, _ = strconv.ParseFloat(params.Get("price"), 64)
+	order.Quantity, _ = strconv.ParseFloat(params.Get("quantity"), 64)
+	quoteQuantity, _ := strconv.ParseFloat(params.Get("quoteOrderQty"), 64)
+
+	// 成交价：买单按卖一价，卖单按买一价
+	fillPrice := t.ask
+	if order.Side == "SELL" {
+		fillPrice = t.bid
+	}
+	switch order.Type {
+	case "MARKET":
+		if order.Quantity <= 0 && quoteQuantity > 0 && fillPrice > 0 {
+			order.Quantity = quoteQuantity / fillPrice
+		}
+	case "LIMIT":
+		if order.Price <= 0 {
+			writeError(w, http.StatusBadRequest, -1013, "Invalid price.")
+			return
+		}
+	default:
+		writeError(w, http.StatusBadRequest, -1116, "Invalid orderType.")
+		return
+	}
+	if order.Quantity <= 0 {
+		writeError(w, http.StatusBadRequest, -1013, "Invalid quantity.")
+		return
+	}
+
+	notionalPrice := order.Price
+	if order.Type == "MARKET" {
+		notionalPrice = fillPrice
+	}
+	if minNotional, _ := strconv.ParseFloat(symbol.MinNotional, 64); minNotional > 0 && order.Quantity*notionalPrice < minNotional {
+		writeError(w, http.StatusBadRequest, -1013, "Filter failure: NOTIONAL")
+		return
+	}
+
+	// 检查并冻结资金
+	asset, amount := symbol.QuoteAsset, order.Quantity*fillPrice
+	if order.Type == "LIMIT" {
+		amount = order.Quantity * order.Price
+	}
+	if order.Side == "SELL" {
+		asset, amount = symbol.BaseAsset, order.Quantity
+	}
+	if s.balance(asset).free < amount-1e-9 {
+		writeError(w, http.StatusBadRequest, -2010, "Account has insufficient balance for requested action.")
+		return
+	}
+	s.balance(asset).free -= amount
+	s.balance(asset).locked += amount
+	order.locked = amount
+
+	s.nextID++
+	s.orders = append(s.orders, order)
+	marketable := order.Type == "MARKET" ||
+		(order.Side == "BUY" && order.Price >= t.ask) ||
+		(order.Side == "SELL" && order.Price <= t.bid)
+	var fills []map[string]string
+	if marketable {
+		s.unlock(order)
+		fills = s.fill(order, fillPrice)
+	}
+
+	response := orderResponse(order)
+	response["transactTime"] = order.Time.UnixMilli()
+	response["fills"] = fills
+	writeJSON(w, response)
+}
+
+// handleGetOrder 查询订单
+func (s *Server) handleGetOrder(w http.ResponseWriter, params url.Values) {
+	order := s.findOrder(params)
+	if order == nil {
+		writeError(w, http.StatusBadRequest, -2013, "Order does not exist.")
+		return
+	}
+	response := orderResponse(order)
+	response["time"] = order.Time.UnixMilli()
+	response["updateTime"] = s.now().UnixMilli()
+	writeJSON(w, response)
+}
+
+// handleCancelOrder 撤销订单
+func (s *Server) handleCancelOrder(w http.ResponseWriter, params url.Values) {
+	order := s.findOrder(params)
+	if order == nil || order.Status != "NEW" {
+		writeError(w, http.StatusBadRequest, -2011, "Unknown order sent.")
+		return
+	}
+	s.unlock(order)
+	order.Status = "CANCELED"
+
+	response := orderResponse(order)
+	response["origClientOrderId"] = order.ClientOrderID
+	response["transactTime"] = s.now().UnixMilli()
+	writeJSON(w, response)
+}
+
+// findOrder 按 orderId 或 origClientOrderId 查找订单，同一客户端订单号取最近的订单
+func (s *Server) findOrder(params url.Values) *Order {
+	orderID, _ := strconv.ParseInt(params.Get("orderId"), 10, 64)
+	clientOrderID := params.Get("origClientOrderId")
+	for i := len(s.orders) - 1; i >= 0; i-- {
+		order := s.orders[i]
+		if order.Symbol != params.Get("symbol") {
+			continue
+		}
+		if (orderID > 0 && order.ID == orderID) || (clientOrderID != "" && order.ClientOrderID == clientOrderID) {
+			return order
+		}
+	}
+	return nil
+}
+
+// unlock 解冻挂单冻结的资产
+func (s *Server) unlock(order *Order) {
+	symbol := s.symbols[order.Symbol]
+	asset := symbol.QuoteAsset
+	if order.Side == "SELL" {
+		asset = symbol.BaseAsset
+	}
+	s.balance(asset).locked -= order.locked
+	s.balance(asset).free += order.locked
+	order.locked = 0
+}
+
+// fill 按成交价全部成交并结算余额，返回成交明细
+func (s *Server) fill(order *Order, price float64) []map[string]string {
+	symbol := s.symbols[order.Symbol]
+	quoteQty := order.Quantity * price
+	commission, commissionAsset := order.Quantity*s.Commission, symbol.BaseAsset
+	if order.Side == "BUY" {
+		s.balance(symbol.QuoteAsset).free -= quoteQty
+		s.balance(symbol.BaseAsset).free += order.Quantity - commission
+	} else {
+		commission, commissionAsset = quoteQty*s.Commission, symbol.QuoteAsset
+		s.balance(symbol.BaseAsset).free -= order.Quantity
+		s.balance(symbol.QuoteAsset).free += quoteQty - commission
+	}
+	order.ExecutedQty = order.Quantity
+	order.QuoteQty = quoteQty
+	order.Status = "FILLED"
+
+	return []map[string]string{{
+		"price":           formatFloat(price),
+		"qty":             formatFloat(order.Quantity),
+		"commission":      formatFloat(commission),
+		"commissionAsset": commissionAsset,
+	}}
+}
+
+// ticker 返回交易对的行情，不存在时创建
+func (s *Server) ticker(symbol string) *ticker {
+	t, exists := s.tickers[symbol]
+	if !exists {
+		t = &ticker{}
+		s.tickers[symbol] = t
+	}
+	return t
+}
+
+// balance 返回资产余额，不存在时创建
+func (s *Server) balance(asset string) *balance {
+	b, exists := s.balances[asset]
+	if !exists {
+		b = &balance{}
+		s.balances[asset] = b
+	}
+	return b
+}
+
+// orderResponse 订单的公共响应字段
+func orderResponse(order *Order) map[string]interface{} {
+	return map[string]interface{}{
+		"symbol":              order.Symbol,
+		"orderId":             order.ID,
+		"clientOrderId":       order.ClientOrderID,
+		"price":               formatFloat(order.Price),
+		"origQty":             formatFloat(order.Quantity),
+		"executedQty":         formatFloat(order.ExecutedQty),
+		"cummulativeQuoteQty": formatFloat(order.QuoteQty),
+		"status":              order.Status,
+		"timeInForce":         "GTC",
+		"type":                order.Type,
+		"side":                order.Side,
+	}
+}
+
+// requestParams 合并查询参数和表单参数（DELETE 请求的表单同样在请求体中）
+func requestParams(r *http.Request) (url.Values, error) {
+	params := r.URL.Query()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range form {
+		params[key] = append(params[key], values...)
+	}
+	return params, nil
+}
+
+// signedEndpoint 需要API密钥的接口
+func signedEndpoint(key string) bool {
+	switch key {
+	case "GET /api/v3/account", "POST /api/v3/order", "GET /api/v3/order", "DELETE /api/v3/order":
+		return true
+	}
+	return false
+}
+
+// writeJSON 写入JSON响应
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError 写入币安格式的错误响应
+func writeError(w http.ResponseWriter, status int, code int64, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{"code": code, "msg": message})
+}
+
+// formatFloat 按币安的格式输出数字字符串
+func formatFloat(f float64) string {
+	if f == math.Trunc(f) {
+		return strconv.FormatFloat(f, 'f', 2, 64)
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// sortedKeys 按字母顺序返回映射的键
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
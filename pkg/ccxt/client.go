@@ -2,12 +2,13 @@ package ccxt
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
-	"math"
-	"os"
+	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/adshao/go-binance/v2"
@@ -19,47 +20,87 @@ type Client struct {
 	apiKey     string
 	secretKey  string
 	proxyUrl   string
+
+	symbolsMu sync.Mutex
+	symbols   map[string]*SymbolInfo // 交易对下单规则缓存
+
+	limiter *RateLimiter // 请求权重限制器
+	prices  *PriceCache  // 行情缓存，可为 nil
 }
 
-// NewClient 创建新的CCXT客户端
-func NewClient(apiKey, secretKey, proxyUrl string) *Client {
-	if proxyUrl == "" {
-		proxyUrl = os.Getenv("HTTPS_PROXY")
-		if proxyUrl == "" {
-			proxyUrl = "http://127.0.0.1:7890" // 默认回退
+// defaultHTTPTimeout 请求交易所的默认超时时间
+const defaultHTTPTimeout = 30 * time.Second
+
+// ClientConfig 客户端配置
+type ClientConfig struct {
+	APIKey     string
+	SecretKey  string
+	ProxyURL   string       // 代理地址，为空时按环境变量 HTTPS_PROXY 等设置
+	BaseURL    string       // 接口地址，为空时使用 https://api.binance.com，测试时可指向 binancetest 服务器
+	HTTPClient *http.Client // 自定义HTTP客户端，设置后忽略 ProxyURL
+	RateLimit  *RateLimiter // 请求权重限制器，为空时使用同一API密钥共享的限制器
+	Prices     *PriceCache  // 行情缓存，查询最新价和最优挂单时优先读取，通常来自 MarketStream.Cache()
+}
+
+// NewClientWithConfig 按配置创建客户端，创建时不访问网络
+func NewClientWithConfig(cfg ClientConfig) (*Client, error) {
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		proxy := http.ProxyFromEnvironment
+		if cfg.ProxyURL != "" {
+			proxyURL, err := url.Parse(cfg.ProxyURL)
+			if err != nil || proxyURL.Scheme == "" || proxyURL.Host == "" {
+				return nil, fmt.Errorf("代理地址无效: %s", cfg.ProxyURL)
+			}
+			proxy = http.ProxyURL(proxyURL)
 		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = proxy
+		httpClient = &http.Client{Transport: transport, Timeout: defaultHTTPTimeout}
+	}
+
+	spotClient := binance.NewClient(cfg.APIKey, cfg.SecretKey)
+	if cfg.BaseURL != "" {
+		spotClient.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
 	}
+	limiter := cfg.RateLimit
+	if limiter == nil {
+		limiter = SharedRateLimiter(cfg.APIKey, spotClient.BaseURL, RateLimitConfig{})
+	}
+	transport := httpClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	limited := *httpClient
+	limited.Transport = &rateLimitedTransport{limiter: limiter, next: transport}
+	spotClient.HTTPClient = &limited
+
+	return &Client{
+		spotClient: spotClient,
+		apiKey:     cfg.APIKey,
+		secretKey:  cfg.SecretKey,
+		proxyUrl:   cfg.ProxyURL,
+		limiter:    limiter,
+		prices:     cfg.Prices,
+	}, nil
+}
+
+// NewClient 创建新的CCXT客户端，代理地址无效时记录日志并按环境变量设置代理
+func NewClient(apiKey, secretKey, proxyUrl string) *Client {
 	if apiKey == "" || secretKey == "" {
 		log.Println("apiKey or secretKey is empty")
 	}
-	cli := &Client{
-		spotClient: binance.NewProxiedClient(apiKey, secretKey, proxyUrl),
-		apiKey:     apiKey,
-		secretKey:  secretKey,
-		proxyUrl:   proxyUrl,
-	}
-	btcPrice, err := cli.GetBTCPrice(context.Background())
+	cli, err := NewClientWithConfig(ClientConfig{APIKey: apiKey, SecretKey: secretKey, ProxyURL: proxyUrl})
 	if err != nil {
-		log.Println("CCXT Client 初始化结果: \n BTC价格获取失败", err)
-	} else {
-		log.Println("CCXT Client 初始化结果: \n BTC价格", btcPrice)
+		log.Printf("创建CCXT客户端失败: %v，忽略代理设置", err)
+		cli, _ = NewClientWithConfig(ClientConfig{APIKey: apiKey, SecretKey: secretKey})
 	}
 	return cli
 }
 
 // NewClientWithoutAuth 创建无需认证的客户端（仅用于公开接口）
 func NewClientWithoutAuth(proxyUrl string) *Client {
-	if proxyUrl == "" {
-		proxyUrl = os.Getenv("HTTPS_PROXY")
-		if proxyUrl == "" {
-			proxyUrl = "http://127.0.0.1:7890"
-		}
-	}
-	return &Client{
-		spotClient: binance.NewProxiedClient("", "", proxyUrl),
-		apiKey:     "",
-		secretKey:  "",
-	}
+	return NewClient("", "", proxyUrl)
 }
 
 // Ping 测试交易所连通性
@@ -67,17 +108,23 @@ func (c *Client) Ping(ctx context.Context) error {
 	// 使用Ping接口测试连通性
 	err := c.spotClient.NewPingService().Do(ctx)
 	if err != nil {
-		return fmt.Errorf("ping交易所失败: %w", err)
+		return fmt.Errorf("ping交易所失败: %w", binanceError(err))
 	}
 	return nil
 }
 
-// GetLatestPrice 获取最新价格
+// GetLatestPrice 获取最新价格，设置了行情缓存时优先读取缓存
 func (c *Client) GetLatestPrice(ctx context.Context, symbol string) (*binance.SymbolPrice, error) {
+	if c.prices != nil {
+		if price, ok := c.prices.Price(symbol); ok {
+			return &binance.SymbolPrice{Symbol: symbol, Price: strconv.FormatFloat(price, 'f', -1, 64)}, nil
+		}
+	}
+
 	// 获取单个交易对的最新价格
 	price, err := c.spotClient.NewListPricesService().Symbol(symbol).Do(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("获取%s价格失败: %w", symbol, err)
+		return nil, fmt.Errorf("获取%s价格失败: %w", symbol, binanceError(err))
 	}
 
 	if len(price) == 0 {
@@ -87,54 +134,77 @@ func (c *Client) GetLatestPrice(ctx context.Context, symbol string) (*binance.Sy
 	return price[0], nil
 }
 
-// GetLatestPrices 获取多个交易对的最新价格
-func (c *Client) GetLatestPrices(ctx context.Context) ([]*binance.SymbolPrice, error) {
-	// 获取所有交易对的最新价格
-	prices, err := c.spotClient.NewListPricesService().Symbol("BTCUSDT").Do(ctx)
+// GetLatestPrices 获取多个交易对的最新价格，不指定交易对时返回全部交易对
+func (c *Client) GetLatestPrices(ctx context.Context, symbols ...string) ([]*binance.SymbolPrice, error) {
+	service := c.spotClient.NewListPricesService()
+	if len(symbols) == 1 {
+		service.Symbol(symbols[0])
+	} else if len(symbols) > 1 {
+		service.Symbols(symbols)
+	}
+	prices, err := service.Do(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("获取价格列表失败: %w", err)
+		return nil, fmt.Errorf("获取价格列表失败: %w", binanceError(err))
 	}
 
 	return prices, nil
 }
 
-// GetBTCPrice 获取比特币最新价格
-func (c *Client) GetBTCPrice(ctx context.Context) (float64, error) {
-	price, err := c.GetLatestPrice(ctx, "BTCUSDT")
-	if err != nil {
-		return 0, err
+// GetPrices 获取多个交易对的最新价格，键为交易对；行情缓存中已有的交易对不再请求交易所
+func (c *Client) GetPrices(ctx context.Context, symbols ...string) (map[string]float64, error) {
+	result := make(map[string]float64, len(symbols))
+	var missing []string
+	for _, symbol := range symbols {
+		if c.prices != nil {
+			if price, ok := c.prices.Price(symbol); ok {
+				result[symbol] = price
+				continue
+			}
+		}
+		missing = append(missing, symbol)
+	}
+	if len(symbols) > 0 && len(missing) == 0 {
+		return result, nil
 	}
 
-	// 将字符串价格转换为float64
-	priceFloat, err := parseFloat(price.Price)
+	prices, err := c.GetLatestPrices(ctx, missing...)
 	if err != nil {
-		return 0, fmt.Errorf("解析价格失败: %w", err)
+		return nil, err
+	}
+	for _, price := range prices {
+		priceFloat, err := parseFloat(price.Price)
+		if err != nil {
+			return nil, fmt.Errorf("解析%s价格失败: %w", price.Symbol, err)
+		}
+		result[price.Symbol] = priceFloat
+	}
+	for _, symbol := range missing {
+		if _, exists := result[symbol]; !exists {
+			return nil, fmt.Errorf("未找到%s的价格数据", symbol)
+		}
 	}
+	return result, nil
+}
 
-	return priceFloat, nil
+// GetBTCPrice 获取比特币最新价格
+//
+// Deprecated: 使用 GetPrice(ctx, "BTCUSDT")
+func (c *Client) GetBTCPrice(ctx context.Context) (float64, error) {
+	return c.GetPrice(ctx, "BTCUSDT")
 }
 
 // GetETHPrice 获取以太坊最新价格
+//
+// Deprecated: 使用 GetPrice(ctx, "ETHUSDT")
 func (c *Client) GetETHPrice(ctx context.Context) (float64, error) {
-	price, err := c.GetLatestPrice(ctx, "ETHUSDT")
-	if err != nil {
-		return 0, err
-	}
-
-	// 将字符串价格转换为float64
-	priceFloat, err := parseFloat(price.Price)
-	if err != nil {
-		return 0, fmt.Errorf("解析价格失败: %w", err)
-	}
-
-	return priceFloat, nil
+	return c.GetPrice(ctx, "ETHUSDT")
 }
 
 // GetServerTime 获取服务器时间
 func (c *Client) GetServerTime(ctx context.Context) (time.Time, error) {
 	serverTime, err := c.spotClient.NewServerTimeService().Do(ctx)
 	if err != nil {
-		return time.Time{}, fmt.Errorf("获取服务器时间失败: %w", err)
+		return time.Time{}, fmt.Errorf("获取服务器时间失败: %w", binanceError(err))
 	}
 
 	return time.Unix(serverTime/1000, (serverTime%1000)*1000000), nil
@@ -153,110 +223,46 @@ func (c *Client) HealthCheck(ctx context.Context) error {
 	}
 
 	// 3. 测试获取BTC价格
-	if _, err := c.GetBTCPrice(ctx); err != nil {
+	if _, err := c.GetPrice(ctx, "BTCUSDT"); err != nil {
 		return fmt.Errorf("获取BTC价格失败: %w", err)
 	}
 
 	return nil
 }
 
-// GetKlines 获取K线数据
-func (c *Client) GetKlines(ctx context.Context, symbol string, interval string, limit int) ([]*binance.Kline, error) {
-	klines, err := c.spotClient.NewKlinesService().
-		Symbol(symbol).
-		Interval(interval).
-		Limit(limit).
-		Do(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("获取%s K线数据失败: %w", symbol, err)
-	}
-
-	return klines, nil
+// GetKlinesWithTimeRange 获取指定时间范围的K线数据，超过单次请求上限时自动分页
+func (c *Client) GetKlinesWithTimeRange(ctx context.Context, symbol string, interval string, startTime, endTime time.Time) ([]Kline, error) {
+	return FetchKlines(ctx, c, KlineQuery{Symbol: symbol, Interval: interval, StartTime: startTime, EndTime: endTime})
 }
 
-// GetKlinesWithTimeRange 获取指定时间范围的K线数据
-func (c *Client) GetKlinesWithTimeRange(ctx context.Context, symbol string, interval string, startTime, endTime time.Time) ([]*binance.Kline, error) {
-	klines, err := c.spotClient.NewKlinesService().
-		Symbol(symbol).
-		Interval(interval).
-		StartTime(startTime.UnixMilli()).
-		EndTime(endTime.UnixMilli()).
-		Do(ctx)
+// GetHistoryPrices 获取交易对最近 count 根K线的收盘价，每个元素为 [开盘时间戳(毫秒), 收盘价]
+// 如 GetHistoryPrices(ctx, "ETHUSDT", "1d", 200)，超过单次请求上限时自动分页
+func (c *Client) GetHistoryPrices(ctx context.Context, symbol, interval string, count int) ([][]float64, error) {
+	klines, err := FetchKlines(ctx, c, KlineQuery{Symbol: symbol, Interval: interval, Limit: count})
 	if err != nil {
-		return nil, fmt.Errorf("获取%s K线数据失败: %w", symbol, err)
-	}
-
-	return klines, nil
-}
-
-// GetBTCHistoryPrices 获取比特币历史价格数据（用于AHR999计算）
-func (c *Client) GetBTCHistoryPrices(ctx context.Context, days int) ([][]float64, error) {
-	// 计算需要的K线数量（每天1根K线）
-	limit := days
-
-	// 获取日K线数据
-	klines, err := c.GetKlines(ctx, "BTCUSDT", "1d", limit)
-	if err != nil {
-		return nil, fmt.Errorf("获取BTC历史K线数据失败: %w", err)
-	}
-
-	// 转换为所需的格式 [][]float64，每个元素为 [timestamp, price]
-	var prices [][]float64
-	for _, kline := range klines {
-		// 将时间戳转换为毫秒
-		timestamp := float64(kline.OpenTime)
-
-		// 使用收盘价作为当日价格
-		price, err := parseFloat(kline.Close)
-		if err != nil {
-			continue // 跳过无效价格
-		}
-
-		prices = append(prices, []float64{timestamp, price})
+		return nil, fmt.Errorf("获取%s历史K线数据失败: %w", symbol, err)
 	}
-
-	return prices, nil
+	return closePrices(klines), nil
 }
 
-// GetBTCHistoryPricesForDate 获取指定日期附近的比特币价格数据
-func (c *Client) GetBTCHistoryPricesForDate(ctx context.Context, targetDate time.Time, daysBefore int) ([][]float64, error) {
-	// 计算时间范围
-	endTime := targetDate.Add(24 * time.Hour)          // 目标日期的下一天
-	startTime := targetDate.AddDate(0, 0, -daysBefore) // daysBefore天前
-
-	// 获取指定时间范围的K线数据
-	klines, err := c.GetKlinesWithTimeRange(ctx, "BTCUSDT", "1d", startTime, endTime)
+// GetHistoryPricesBetween 获取交易对在时间范围内的收盘价，格式同 GetHistoryPrices
+func (c *Client) GetHistoryPricesBetween(ctx context.Context, symbol, interval string, startTime, endTime time.Time) ([][]float64, error) {
+	klines, err := c.GetKlinesWithTimeRange(ctx, symbol, interval, startTime, endTime)
 	if err != nil {
-		return nil, fmt.Errorf("获取BTC历史K线数据失败: %w", err)
-	}
-
-	// 转换为所需的格式 [][]float64，每个元素为 [timestamp, price]
-	var prices [][]float64
-	for _, kline := range klines {
-		// 将时间戳转换为毫秒
-		timestamp := float64(kline.OpenTime)
-
-		// 使用收盘价作为当日价格
-		price, err := parseFloat(kline.Close)
-		if err != nil {
-			continue // 跳过无效价格
-		}
-
-		prices = append(prices, []float64{timestamp, price})
+		return nil, fmt.Errorf("获取%s历史K线数据失败: %w", symbol, err)
 	}
-
-	return prices, nil
+	return closePrices(klines), nil
 }
 
-// GetBTCPriceAtDate 获取指定日期的比特币价格
-func (c *Client) GetBTCPriceAtDate(ctx context.Context, targetDate time.Time) (float64, error) {
+// GetPriceAtDate 获取交易对在指定日期的价格（开盘时间最接近的日K线收盘价）
+func (c *Client) GetPriceAtDate(ctx context.Context, symbol string, targetDate time.Time) (float64, error) {
 	// 获取目标日期附近的价格数据（前后各1天）
 	endTime := targetDate.Add(24 * time.Hour)
 	startTime := targetDate.Add(-24 * time.Hour)
 
-	klines, err := c.GetKlinesWithTimeRange(ctx, "BTCUSDT", "1d", startTime, endTime)
+	klines, err := c.GetKlinesWithTimeRange(ctx, symbol, "1d", startTime, endTime)
 	if err != nil {
-		return 0, fmt.Errorf("获取BTC历史价格失败: %w", err)
+		return 0, fmt.Errorf("获取%s历史价格失败: %w", symbol, err)
 	}
 
 	// 找到最接近目标日期的价格
@@ -264,26 +270,51 @@ func (c *Client) GetBTCPriceAtDate(ctx context.Context, targetDate time.Time) (f
 	var minDiff time.Duration = 24 * time.Hour
 
 	for _, kline := range klines {
-		klineTime := time.Unix(kline.OpenTime/1000, 0)
-		diff := absDuration(targetDate.Sub(klineTime))
+		diff := absDuration(targetDate.Sub(kline.OpenTime))
 
 		if diff < minDiff {
-			price, err := parseFloat(kline.Close)
-			if err != nil {
-				continue
-			}
-			closestPrice = price
+			closestPrice = kline.Close
 			minDiff = diff
 		}
 	}
 
 	if closestPrice == 0 {
-		return 0, fmt.Errorf("未找到%s附近的价格数据", targetDate.Format("2006-01-02"))
+		return 0, fmt.Errorf("未找到%s在%s附近的价格数据", symbol, targetDate.Format("2006-01-02"))
 	}
 
 	return closestPrice, nil
 }
 
+// GetBTCHistoryPrices 获取比特币历史价格数据（用于AHR999计算）
+//
+// Deprecated: 使用 GetHistoryPrices(ctx, "BTCUSDT", "1d", days)
+func (c *Client) GetBTCHistoryPrices(ctx context.Context, days int) ([][]float64, error) {
+	return c.GetHistoryPrices(ctx, "BTCUSDT", "1d", days)
+}
+
+// GetBTCHistoryPricesForDate 获取指定日期附近的比特币价格数据
+//
+// Deprecated: 使用 GetHistoryPricesBetween
+func (c *Client) GetBTCHistoryPricesForDate(ctx context.Context, targetDate time.Time, daysBefore int) ([][]float64, error) {
+	return c.GetHistoryPricesBetween(ctx, "BTCUSDT", "1d", targetDate.AddDate(0, 0, -daysBefore), targetDate.Add(24*time.Hour))
+}
+
+// GetBTCPriceAtDate 获取指定日期的比特币价格
+//
+// Deprecated: 使用 GetPriceAtDate(ctx, "BTCUSDT", targetDate)
+func (c *Client) GetBTCPriceAtDate(ctx context.Context, targetDate time.Time) (float64, error) {
+	return c.GetPriceAtDate(ctx, "BTCUSDT", targetDate)
+}
+
+// closePrices 将K线转换为 [开盘时间戳(毫秒), 收盘价] 列表
+func closePrices(klines []Kline) [][]float64 {
+	prices := make([][]float64, 0, len(klines))
+	for _, kline := range klines {
+		prices = append(prices, []float64{float64(kline.OpenTime.UnixMilli()), kline.Close})
+	}
+	return prices
+}
+
 // absDuration 计算时间差的绝对值
 func absDuration(d time.Duration) time.Duration {
 	if d < 0 {
@@ -303,86 +334,82 @@ func parseFloat(s string) (float64, error) {
 	return f, nil
 }
 
-// jsonAnything 辅助函数：将任何类型转换按照美化后的json格式输出
-func jsonAnything(v any) string {
-	json, err := json.MarshalIndent(v, "", "  ")
-	if err != nil {
-		return "json marshal error"
-	}
-	return string(json)
+// Account 账户信息
+type Account struct {
+	CanTrade    bool      `json:"can_trade"`
+	CanWithdraw bool      `json:"can_withdraw"`
+	CanDeposit  bool      `json:"can_deposit"`
+	Balances    []Balance `json:"balances"` // 仅包含余额不为零的资产
+	UpdateTime  time.Time `json:"update_time"`
 }
 
 // 获取账户金额信息
-func (c *Client) GetAccountBalance(ctx context.Context) string {
+func (c *Client) GetAccountBalance(ctx context.Context) (*Account, error) {
 	// 传入 omitZeroBalances == true 来过滤掉零余额的资产
-	balance, err := c.spotClient.NewGetAccountService().OmitZeroBalances(true).Do(ctx)
+	account, err := c.spotClient.NewGetAccountService().OmitZeroBalances(true).Do(ctx)
 	if err != nil {
-		return fmt.Sprintf("获取账户金额信息失败: %v", err)
-	}
-	return jsonAnything(balance)
+		return nil, fmt.Errorf("获取账户金额信息失败: %w", binanceError(err))
+	}
+
+	balances := make([]Balance, 0, len(account.Balances))
+	for _, balance := range account.Balances {
+		balances = append(balances, Balance{
+			Asset:  balance.Asset,
+			Free:   parseNumber(balance.Free),
+			Locked: parseNumber(balance.Locked),
+		})
+	}
+	return &Account{
+		CanTrade:    account.CanTrade,
+		CanWithdraw: account.CanWithdraw,
+		CanDeposit:  account.CanDeposit,
+		Balances:    balances,
+		UpdateTime:  time.UnixMilli(int64(account.UpdateTime)),
+	}, nil
 }
 
-// 获取账户的BTC余额
-func (c *Client) GetBTCBalance(ctx context.Context) string {
-	account, err := c.spotClient.NewGetAccountService().OmitZeroBalances(true).Do(ctx)
+// GetAssetBalance 获取账户中资产的余额（如 BTC、ETH、SOL），没有该资产时数量为零
+func (c *Client) GetAssetBalance(ctx context.Context, asset string) (Balance, error) {
+	balances, err := c.GetBalances(ctx)
 	if err != nil {
-		return fmt.Sprintf("获取账户失败: %v", err)
+		return Balance{}, err
 	}
-	balance := account.Balances
-	for _, balance := range balance {
-		if balance.Asset == "BTC" {
-			return balance.Free
-		}
+	return FindBalance(balances, asset), nil
+}
+
+// 获取账户的BTC可用余额
+//
+// Deprecated: 使用 GetAssetBalance(ctx, "BTC")
+func (c *Client) GetBTCBalance(ctx context.Context) (float64, error) {
+	balance, err := c.GetAssetBalance(ctx, "BTC")
+	if err != nil {
+		return 0, err
 	}
-	return "0"
+	return balance.Free, nil
 }
 
 // 依照传入的 Symbol 和 Amount 按照市价购买指定数量的币
 // 参数: symbol: 币种名称(BTCUSDT), amount: 购买数量(USDT)
-func (c *Client) BuyCoinByMarketPrice(ctx context.Context, symbol string, amount float64) string {
-	order, err := c.spotClient.NewCreateOrderService().Symbol(symbol).Side(binance.SideTypeBuy).Type(binance.OrderTypeMarket).QuoteOrderQty(strconv.FormatFloat(amount, 'f', -1, 64)).Do(ctx)
-	if err != nil {
-		return fmt.Sprintf("购买%s失败: %v", symbol, err)
-	}
-	return jsonAnything(order)
+func (c *Client) BuyCoinByMarketPrice(ctx context.Context, symbol string, amount float64) (*Order, error) {
+	return c.PlaceOrder(ctx, OrderRequest{Symbol: symbol, Side: SideBuy, Type: OrderTypeMarket, QuoteQuantity: amount})
 }
 
 // 依照传入的 Symbol 和 Amount 按照最优价购买指定数量的币
 // 参数: symbol: 币种名称(BTCUSDT), amount: 购买金额(USDT)
-// 首先获取当前订单盘口，然后根据盘口价格计算最优价
-func (c *Client) BuyCoinByBestPrice(ctx context.Context, symbol string, amount float64) string {
-	bestSellPrice, _, err := c.GetBestPrice(ctx, symbol)
-	if err != nil {
-		return fmt.Sprintf("获取%s订单盘口失败: %v", symbol, err)
-	}
-	acount := amount / bestSellPrice
-	acount = math.Round(acount*100000) / 100000
-	fmt.Printf("购买数量: %.5f\n", acount)
-	order, err := c.spotClient.NewCreateOrderService().Symbol(symbol).Side(binance.SideTypeBuy).
-		Type(binance.OrderTypeLimit).
-		Price(strconv.FormatFloat(bestSellPrice, 'f', -1, 64)).
-		TimeInForce(binance.TimeInForceTypeGTC).
-		Quantity(strconv.FormatFloat(acount, 'f', -1, 64)).
-		Do(ctx)
-	if err != nil {
-		return fmt.Sprintf("购买%s失败: %v", symbol, err)
-	}
-	return jsonAnything(order)
+func (c *Client) BuyCoinByBestPrice(ctx context.Context, symbol string, amount float64) (*Order, error) {
+	return BuyAtBestPrice(ctx, c, symbol, amount)
 }
 
 // 获取当前订单盘口
 func (c *Client) GetBestPrice(ctx context.Context, symbol string) (bestSellPrice, bestBuyPrice float64, err error) {
-	orderBook, err := c.spotClient.NewListBookTickersService().Symbol(symbol).Do(ctx)
-	if err != nil {
-		return 0, 0, fmt.Errorf("获取%s订单盘口失败: %v", symbol, err)
-	}
-	bestSellPrice, err = parseFloat(orderBook[0].AskPrice)
+	ticker, err := c.GetBookTicker(ctx, symbol)
 	if err != nil {
-		return 0, 0, fmt.Errorf("获取%s订单盘口失败: %v", symbol, err)
+		return 0, 0, err
 	}
-	bestBuyPrice, err = parseFloat(orderBook[0].BidPrice)
-	if err != nil {
-		return 0, 0, fmt.Errorf("获取%s订单盘口失败: %v", symbol, err)
-	}
-	return
+	return ticker.AskPrice, ticker.BidPrice, nil
+}
+
+// RateLimitUsage 返回当前分钟的请求权重使用情况
+func (c *Client) RateLimitUsage() RateLimitUsage {
+	return c.limiter.Usage()
 }
@@ -0,0 +1,69 @@
+package ccxt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/adshao/go-binance/v2/common"
+)
+
+// 交易所错误分类，使用 errors.Is 判断
+var (
+	ErrInsufficientBalance = errors.New("余额不足")
+	ErrMinNotional         = errors.New("订单金额低于最小限制")
+	ErrRateLimited         = errors.New("请求过于频繁")
+	ErrOrderNotFound       = errors.New("订单不存在")
+	ErrInvalidOrder        = errors.New("订单参数无效")
+)
+
+// ExchangeError 交易所返回的业务错误
+type ExchangeError struct {
+	Exchange string // 交易所名称
+	Code     int64  // 交易所错误码
+	Message  string // 交易所错误信息
+	Kind     error  // 错误分类，无法归类时为空
+}
+
+// Error 实现error接口
+func (e *ExchangeError) Error() string {
+	if e.Kind != nil {
+		return fmt.Sprintf("%v: %s（%s错误码 %d）", e.Kind, e.Message, e.Exchange, e.Code)
+	}
+	return fmt.Sprintf("%s错误码 %d: %s", e.Exchange, e.Code, e.Message)
+}
+
+// Unwrap 返回错误分类
+func (e *ExchangeError) Unwrap() error {
+	return e.Kind
+}
+
+// binanceError 将 go-binance 返回的API错误转换为带分类的 ExchangeError，其他错误（如网络错误）原样返回
+func binanceError(err error) error {
+	var apiErr *common.APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+
+	exchangeErr := &ExchangeError{Exchange: binanceName, Code: apiErr.Code, Message: apiErr.Message}
+	if !apiErr.IsValid() {
+		exchangeErr.Message = string(apiErr.Response)
+	}
+
+	message := strings.ToLower(apiErr.Message)
+	switch {
+	case apiErr.Code == -1003 || apiErr.Code == -1015:
+		// TOO_MANY_REQUESTS、TOO_MANY_ORDERS，HTTP 429/418 时同样返回 -1003
+		exchangeErr.Kind = ErrRateLimited
+	case strings.Contains(message, "insufficient balance"):
+		exchangeErr.Kind = ErrInsufficientBalance
+	case strings.Contains(message, "notional"):
+		exchangeErr.Kind = ErrMinNotional
+	case apiErr.Code == -2013 || strings.Contains(message, "unknown order") || strings.Contains(message, "does not exist"):
+		exchangeErr.Kind = ErrOrderNotFound
+	case apiErr.Code == -1013 || apiErr.Code == -1111 || apiErr.Code == -2010:
+		// 其他过滤器失败（LOT_SIZE、PRICE_FILTER）、精度错误和下单被拒
+		exchangeErr.Kind = ErrInvalidOrder
+	}
+	return exchangeErr
+}
@@ -0,0 +1,39 @@
+package ccxt
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/adshao/go-binance/v2/common"
+)
+
+func TestBinanceError(t *testing.T) {
+	cases := []struct {
+		code    int64
+		message string
+		want    error
+	}{
+		{-2010, "Account has insufficient balance for requested action.", ErrInsufficientBalance},
+		{-1013, "Filter failure: NOTIONAL", ErrMinNotional},
+		{-1003, "Too many requests; current limit is 6000 request weight per 1 MINUTE.", ErrRateLimited},
+		{-2013, "Order does not exist.", ErrOrderNotFound},
+		{-1013, "Filter failure: LOT_SIZE", ErrInvalidOrder},
+	}
+	for _, c := range cases {
+		err := fmt.Errorf("下单失败: %w", binanceError(&common.APIError{Code: c.code, Message: c.message}))
+		if !errors.Is(err, c.want) {
+			t.Errorf("%s 应归类为 %v，实际: %v", c.message, c.want, err)
+		}
+		var exchangeErr *ExchangeError
+		if !errors.As(err, &exchangeErr) || exchangeErr.Code != c.code {
+			t.Errorf("应保留交易所错误码 %d: %v", c.code, err)
+		}
+	}
+
+	// 非API错误原样返回
+	netErr := errors.New("connection refused")
+	if binanceError(netErr) != netErr {
+		t.Error("网络错误不应被转换")
+	}
+}
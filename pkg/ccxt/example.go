@@ -35,27 +35,29 @@ func ExampleUsage() {
 		fmt.Printf("✓ 服务器时间: %s\n", serverTime.Format("2006-01-02 15:04:05"))
 	}
 
-	// 3. 获取BTC最新价格
-	fmt.Println("\n3. 获取BTC最新价格")
-	btcPrice, err := client.GetBTCPrice(ctx)
+	// 3. 获取BTC、ETH、SOL最新价格（一次请求）
+	fmt.Println("\n3. 获取BTC、ETH、SOL最新价格")
+	prices, err := client.GetPrices(ctx, "BTCUSDT", "ETHUSDT", "SOLUSDT")
 	if err != nil {
-		log.Printf("获取BTC价格失败: %v", err)
+		log.Printf("获取价格失败: %v", err)
 	} else {
-		fmt.Printf("✓ BTC价格: $%.2f\n", btcPrice)
+		for _, symbol := range []string{"BTCUSDT", "ETHUSDT", "SOLUSDT"} {
+			fmt.Printf("✓ %s价格: $%.2f\n", symbol, prices[symbol])
+		}
 	}
 
-	// 4. 获取ETH最新价格
-	fmt.Println("\n4. 获取ETH最新价格")
-	ethPrice, err := client.GetETHPrice(ctx)
+	// 4. 获取SOL日K线（超过1000根时自动分页）
+	fmt.Println("\n4. 获取SOL最近1500根小时K线")
+	solKlines, err := FetchKlines(ctx, client, KlineQuery{Symbol: "SOLUSDT", Interval: "1h", Limit: 1500})
 	if err != nil {
-		log.Printf("获取ETH价格失败: %v", err)
+		log.Printf("获取SOL K线失败: %v", err)
 	} else {
-		fmt.Printf("✓ ETH价格: $%.2f\n", ethPrice)
+		fmt.Printf("✓ 获取到 %d 根K线\n", len(solKlines))
 	}
 
 	// 5. 获取BTC历史价格数据（用于AHR999计算）
 	fmt.Println("\n5. 获取BTC历史价格数据")
-	historyPrices, err := client.GetBTCHistoryPrices(ctx, 200) // 获取200天数据
+	historyPrices, err := client.GetHistoryPrices(ctx, "BTCUSDT", "1d", 200) // 获取200天数据
 	if err != nil {
 		log.Printf("获取BTC历史价格失败: %v", err)
 	} else {
@@ -97,7 +99,14 @@ func ExampleUsageWithAuth() {
 	}
 
 	fmt.Println("\n=== 获取账户金额信息 ===")
-	fmt.Println(client.GetAccountBalance(context.Background()))
+	account, err := client.GetAccountBalance(context.Background())
+	if err != nil {
+		fmt.Printf("❌ 获取账户金额信息失败: %v\n", err)
+	} else {
+		for _, balance := range account.Balances {
+			fmt.Printf("✓ %s: 可用 %v, 冻结 %v\n", balance.Asset, balance.Free, balance.Locked)
+		}
+	}
 
 	fmt.Println("\n=== 购买BTC ===")
 	// fmt.Println(client.BuyCoinByMarketPrice(context.Background(), "BTCUSDT", 10))
@@ -109,8 +118,15 @@ func ExampleUsageWithAuth() {
 	fmt.Println("\n=== 最优价购买BTC ===")
 	// fmt.Println(client.BuyCoinByBestPrice(context.Background(), "BTCUSDT", 11))
 
-	fmt.Println("\n=== 获取BTC余额 ===")
-	fmt.Println(client.GetBTCBalance(context.Background()))
+	fmt.Println("\n=== 获取BTC、ETH、SOL余额 ===")
+	for _, asset := range []string{"BTC", "ETH", "SOL"} {
+		balance, err := client.GetAssetBalance(context.Background(), asset)
+		if err != nil {
+			fmt.Printf("❌ 获取%s余额失败: %v\n", asset, err)
+			continue
+		}
+		fmt.Printf("✓ %s余额: %v\n", asset, balance.Free)
+	}
 
 	fmt.Println("\n=== ExampleUsageBuyCoin 示例完成 ===")
 }
@@ -0,0 +1,195 @@
+package ccxt
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Exchange 交易所接口，屏蔽不同交易所SDK的差异
+// 交易对使用交易所原生格式（如 BTCUSDT），价格和数量统一为 float64
+type Exchange interface {
+	// Name 返回交易所名称，与注册表中的名称一致
+	Name() string
+
+	// GetPrice 获取交易对的最新成交价
+	GetPrice(ctx context.Context, symbol string) (float64, error)
+
+	// GetBookTicker 获取交易对的最优挂单（买一/卖一）
+	GetBookTicker(ctx context.Context, symbol string) (*BookTicker, error)
+
+	// GetKlines 获取K线数据
+	GetKlines(ctx context.Context, query KlineQuery) ([]Kline, error)
+
+	// GetSymbolInfo 获取交易对的下单规则（价格步长、数量步长、最小下单金额）
+	GetSymbolInfo(ctx context.Context, symbol string) (*SymbolInfo, error)
+
+	// GetBalances 获取余额不为零的资产
+	GetBalances(ctx context.Context) ([]Balance, error)
+
+	// PlaceOrder 下单
+	PlaceOrder(ctx context.Context, req OrderRequest) (*Order, error)
+
+	// GetOrder 查询订单
+	GetOrder(ctx context.Context, symbol, orderID string) (*Order, error)
+
+	// GetOrderByClientID 按客户端订单号查询订单，不存在时返回 ErrOrderNotFound
+	GetOrderByClientID(ctx context.Context, symbol, clientOrderID string) (*Order, error)
+
+	// CancelOrder 撤销订单
+	CancelOrder(ctx context.Context, symbol, orderID string) (*Order, error)
+}
+
+// BookTicker 最优挂单
+type BookTicker struct {
+	Symbol   string  `json:"symbol"`
+	BidPrice float64 `json:"bid_price"` // 最优买价
+	BidQty   float64 `json:"bid_qty"`
+	AskPrice float64 `json:"ask_price"` // 最优卖价
+	AskQty   float64 `json:"ask_qty"`
+}
+
+// KlineQuery K线查询条件
+type KlineQuery struct {
+	Symbol    string    // 交易对
+	Interval  string    // K线周期，如 1m、1h、1d
+	StartTime time.Time // 开始时间，零值表示不限制
+	EndTime   time.Time // 结束时间，零值表示不限制
+	Limit     int       // 返回数量，0 使用交易所默认值
+}
+
+// Kline K线
+type Kline struct {
+	OpenTime  time.Time `json:"open_time"`
+	Open      float64   `json:"open"`
+	High      float64   `json:"high"`
+	Low       float64   `json:"low"`
+	Close     float64   `json:"close"`
+	Volume    float64   `json:"volume"`
+	CloseTime time.Time `json:"close_time"`
+}
+
+// Balance 资产余额
+type Balance struct {
+	Asset  string  `json:"asset"`
+	Free   float64 `json:"free"`   // 可用数量
+	Locked float64 `json:"locked"` // 冻结数量（挂单中）
+}
+
+// FindBalance 在余额列表中查找资产，不存在时返回数量为零的余额
+func FindBalance(balances []Balance, asset string) Balance {
+	for _, balance := range balances {
+		if balance.Asset == asset {
+			return balance
+		}
+	}
+	return Balance{Asset: asset}
+}
+
+// OrderSide 买卖方向
+type OrderSide string
+
+const (
+	SideBuy  OrderSide = "BUY"
+	SideSell OrderSide = "SELL"
+)
+
+// OrderType 订单类型
+type OrderType string
+
+const (
+	OrderTypeMarket OrderType = "MARKET" // 市价单
+	OrderTypeLimit  OrderType = "LIMIT"  // 限价单（GTC）
+)
+
+// OrderStatus 订单状态
+type OrderStatus string
+
+const (
+	OrderStatusNew             OrderStatus = "NEW"
+	OrderStatusPartiallyFilled OrderStatus = "PARTIALLY_FILLED"
+	OrderStatusFilled          OrderStatus = "FILLED"
+	OrderStatusCanceled        OrderStatus = "CANCELED"
+	OrderStatusRejected        OrderStatus = "REJECTED"
+	OrderStatusExpired         OrderStatus = "EXPIRED"
+)
+
+// OrderRequest 下单请求
+type OrderRequest struct {
+	Symbol        string
+	Side          OrderSide
+	Type          OrderType
+	Quantity      float64 // 基础资产数量（如BTC）
+	QuoteQuantity float64 // 市价单按计价资产金额下单（如USDT），与 Quantity 二选一
+	Price         float64 // 限价单价格
+	ClientOrderID string  // 自定义订单号（可选）
+}
+
+// Order 订单
+type Order struct {
+	ID            string             `json:"id"`
+	ClientOrderID string             `json:"client_order_id"`
+	Symbol        string             `json:"symbol"`
+	Side          OrderSide          `json:"side"`
+	Type          OrderType          `json:"type"`
+	Status        OrderStatus        `json:"status"`
+	Price         float64            `json:"price"`          // 限价单价格，市价单为0
+	Quantity      float64            `json:"quantity"`       // 委托数量
+	ExecutedQty   float64            `json:"executed_qty"`   // 已成交数量
+	QuoteQty      float64            `json:"quote_qty"`      // 已成交金额
+	AvgPrice      float64            `json:"avg_price"`      // 成交均价
+	Fees          map[string]float64 `json:"fees,omitempty"` // 手续费，键为扣费资产（查询订单时交易所不返回）
+	CreatedAt     time.Time          `json:"created_at"`
+}
+
+// Filled 订单是否已全部成交
+func (o *Order) Filled() bool {
+	return o.Status == OrderStatusFilled
+}
+
+// withAvgPrice 由成交金额和数量计算成交均价
+func (o *Order) withAvgPrice() *Order {
+	if o.ExecutedQty > 0 {
+		o.AvgPrice = o.QuoteQty / o.ExecutedQty
+	}
+	return o
+}
+
+// BuyAtBestPrice 以当前卖一价挂限价单，买入价值 amount（计价资产，如USDT）的币
+// 挂单后立即返回，需要等待成交时使用 OrderTracker 跟踪
+func BuyAtBestPrice(ctx context.Context, exchange Exchange, symbol string, amount float64) (*Order, error) {
+	req, err := BestPriceBuyRequest(ctx, exchange, symbol, amount)
+	if err != nil {
+		return nil, err
+	}
+	return exchange.PlaceOrder(ctx, req)
+}
+
+// BestPriceBuyRequest 构造以卖一价买入价值 amount 的限价单请求
+// 首先获取当前订单盘口，然后根据卖一价计算买入数量，并按交易对的数量步长向下舍入
+func BestPriceBuyRequest(ctx context.Context, exchange Exchange, symbol string, amount float64) (OrderRequest, error) {
+	ticker, err := exchange.GetBookTicker(ctx, symbol)
+	if err != nil {
+		return OrderRequest{}, err
+	}
+	if ticker.AskPrice <= 0 {
+		return OrderRequest{}, fmt.Errorf("%s卖一价无效: %v", symbol, ticker.AskPrice)
+	}
+
+	info, err := exchange.GetSymbolInfo(ctx, symbol)
+	if err != nil {
+		return OrderRequest{}, err
+	}
+
+	req := info.Normalize(OrderRequest{
+		Symbol:   symbol,
+		Side:     SideBuy,
+		Type:     OrderTypeLimit,
+		Quantity: amount / ticker.AskPrice,
+		Price:    ticker.AskPrice,
+	})
+	if err := info.Validate(req); err != nil {
+		return OrderRequest{}, err
+	}
+	return req, nil
+}
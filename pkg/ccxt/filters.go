@@ -0,0 +1,118 @@
+package ccxt
+
+import (
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// SymbolInfo 交易对的下单规则，值为0的限制不检查
+type SymbolInfo struct {
+	Symbol         string          `json:"symbol"`
+	BaseAsset      string          `json:"base_asset"`
+	QuoteAsset     string          `json:"quote_asset"`
+	TickSize       decimal.Decimal `json:"tick_size"`       // 价格步长
+	MinPrice       decimal.Decimal `json:"min_price"`       // 最低价格
+	MaxPrice       decimal.Decimal `json:"max_price"`       // 最高价格
+	StepSize       decimal.Decimal `json:"step_size"`       // 数量步长
+	MinQty         decimal.Decimal `json:"min_qty"`         // 最小数量
+	MaxQty         decimal.Decimal `json:"max_qty"`         // 最大数量
+	MinNotional    decimal.Decimal `json:"min_notional"`    // 最小下单金额（价格×数量）
+	QuotePrecision int32           `json:"quote_precision"` // 按金额下市价单时金额的小数位数
+}
+
+// RoundPrice 将价格舍入到最近的价格步长
+func (s *SymbolInfo) RoundPrice(price float64) decimal.Decimal {
+	value := decimal.NewFromFloat(price)
+	if !s.TickSize.IsPositive() {
+		return value
+	}
+	return value.Div(s.TickSize).Round(0).Mul(s.TickSize)
+}
+
+// RoundQuantity 将数量向下舍入到数量步长，避免超出可用余额
+func (s *SymbolInfo) RoundQuantity(quantity float64) decimal.Decimal {
+	return floorToStep(decimal.NewFromFloat(quantity), s.StepSize)
+}
+
+// RoundQuoteQuantity 将下单金额向下舍入到计价资产精度
+func (s *SymbolInfo) RoundQuoteQuantity(amount float64) decimal.Decimal {
+	value := decimal.NewFromFloat(amount)
+	if s.QuotePrecision <= 0 {
+		return value
+	}
+	return value.RoundFloor(s.QuotePrecision)
+}
+
+// Normalize 按价格步长、数量步长和金额精度舍入下单请求
+func (s *SymbolInfo) Normalize(req OrderRequest) OrderRequest {
+	if req.Price > 0 {
+		req.Price = s.RoundPrice(req.Price).InexactFloat64()
+	}
+	if req.Quantity > 0 {
+		req.Quantity = s.RoundQuantity(req.Quantity).InexactFloat64()
+	}
+	if req.QuoteQuantity > 0 {
+		req.QuoteQuantity = s.RoundQuoteQuantity(req.QuoteQuantity).InexactFloat64()
+	}
+	return req
+}
+
+// Validate 下单前检查请求是否满足交易对的下单规则
+// 金额不足返回 ErrMinNotional，其他不满足规则的情况返回 ErrInvalidOrder
+// 按数量下的市价单没有价格，不检查最小下单金额
+func (s *SymbolInfo) Validate(req OrderRequest) error {
+	price := decimal.NewFromFloat(req.Price)
+	quantity := decimal.NewFromFloat(req.Quantity)
+
+	if req.Type == OrderTypeLimit {
+		if !price.IsPositive() {
+			return fmt.Errorf("%w: %s限价单价格必须大于0", ErrInvalidOrder, req.Symbol)
+		}
+		if s.MinPrice.IsPositive() && price.LessThan(s.MinPrice) {
+			return fmt.Errorf("%w: %s价格 %s 低于最低价格 %s", ErrInvalidOrder, req.Symbol, price, s.MinPrice)
+		}
+		if s.MaxPrice.IsPositive() && price.GreaterThan(s.MaxPrice) {
+			return fmt.Errorf("%w: %s价格 %s 高于最高价格 %s", ErrInvalidOrder, req.Symbol, price, s.MaxPrice)
+		}
+		if !onStep(price, s.TickSize) {
+			return fmt.Errorf("%w: %s价格 %s 不是价格步长 %s 的整数倍", ErrInvalidOrder, req.Symbol, price, s.TickSize)
+		}
+	}
+
+	if req.Quantity > 0 {
+		if s.MinQty.IsPositive() && quantity.LessThan(s.MinQty) {
+			return fmt.Errorf("%w: %s数量 %s 低于最小数量 %s", ErrInvalidOrder, req.Symbol, quantity, s.MinQty)
+		}
+		if s.MaxQty.IsPositive() && quantity.GreaterThan(s.MaxQty) {
+			return fmt.Errorf("%w: %s数量 %s 高于最大数量 %s", ErrInvalidOrder, req.Symbol, quantity, s.MaxQty)
+		}
+		if !onStep(quantity, s.StepSize) {
+			return fmt.Errorf("%w: %s数量 %s 不是数量步长 %s 的整数倍", ErrInvalidOrder, req.Symbol, quantity, s.StepSize)
+		}
+	} else if req.QuoteQuantity <= 0 {
+		return fmt.Errorf("%w: %s下单数量必须大于0", ErrInvalidOrder, req.Symbol)
+	}
+
+	notional := decimal.NewFromFloat(req.QuoteQuantity)
+	if req.Type == OrderTypeLimit {
+		notional = price.Mul(quantity)
+	}
+	if s.MinNotional.IsPositive() && notional.IsPositive() && notional.LessThan(s.MinNotional) {
+		return fmt.Errorf("%w: %s下单金额 %s 低于 %s", ErrMinNotional, req.Symbol, notional, s.MinNotional)
+	}
+	return nil
+}
+
+// floorToStep 向下舍入到步长的整数倍，步长为0时不舍入
+func floorToStep(value, step decimal.Decimal) decimal.Decimal {
+	if !step.IsPositive() {
+		return value
+	}
+	return value.Div(step).Floor().Mul(step)
+}
+
+// onStep 数值是否为步长的整数倍，步长为0时不检查
+func onStep(value, step decimal.Decimal) bool {
+	return !step.IsPositive() || value.Mod(step).IsZero()
+}
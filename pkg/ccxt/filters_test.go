@@ -0,0 +1,50 @@
+package ccxt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestSymbolInfo(t *testing.T) {
+	info := &SymbolInfo{
+		Symbol:      "BTCUSDT",
+		TickSize:    decimal.RequireFromString("0.01"),
+		StepSize:    decimal.RequireFromString("0.00001"),
+		MinQty:      decimal.RequireFromString("0.00001"),
+		MinNotional: decimal.RequireFromString("5"),
+	}
+
+	// 数量向下舍入，价格舍入到最近的步长
+	if got := info.RoundQuantity(100 / 97123.45).String(); got != "0.00102" {
+		t.Errorf("数量舍入错误: %s", got)
+	}
+	if got := info.RoundPrice(97123.456).String(); got != "97123.46" {
+		t.Errorf("价格舍入错误: %s", got)
+	}
+	// 0.1+0.2 等二进制误差不影响舍入结果
+	if got := info.RoundQuantity(0.1 + 0.2).String(); got != "0.3" {
+		t.Errorf("数量舍入错误: %s", got)
+	}
+
+	req := info.Normalize(OrderRequest{Symbol: "BTCUSDT", Side: SideBuy, Type: OrderTypeLimit, Quantity: 0.001029, Price: 97123.456})
+	if err := info.Validate(req); err != nil {
+		t.Errorf("舍入后的订单应通过检查: %v", err)
+	}
+
+	cases := []struct {
+		req  OrderRequest
+		want error
+	}{
+		{OrderRequest{Symbol: "BTCUSDT", Type: OrderTypeLimit, Quantity: 0.00004, Price: 97123.45}, ErrMinNotional},
+		{OrderRequest{Symbol: "BTCUSDT", Type: OrderTypeMarket, QuoteQuantity: 4}, ErrMinNotional},
+		{OrderRequest{Symbol: "BTCUSDT", Type: OrderTypeLimit, Quantity: 0.000015, Price: 97123.45}, ErrInvalidOrder},
+		{OrderRequest{Symbol: "BTCUSDT", Type: OrderTypeLimit, Quantity: 0.001, Price: 97123.455}, ErrInvalidOrder},
+	}
+	for _, c := range cases {
+		if err := info.Validate(c.req); !errors.Is(err, c.want) {
+			t.Errorf("%+v 期望 %v，实际: %v", c.req, c.want, err)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package ccxt
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// klinePageLimit 单次请求K线的最大数量（币安上限为1000）
+const klinePageLimit = 1000
+
+// intervalUnits K线周期单位对应的时长，月按31天估算
+var intervalUnits = map[byte]time.Duration{
+	's': time.Second,
+	'm': time.Minute,
+	'h': time.Hour,
+	'd': 24 * time.Hour,
+	'w': 7 * 24 * time.Hour,
+	'M': 31 * 24 * time.Hour,
+}
+
+// FetchKlines 获取K线，超过单次请求上限时按开盘时间自动分页
+//   - 指定 StartTime 时从开始时间往后取，直到 EndTime 或取满 Limit（0 表示不限数量）
+//   - 未指定 StartTime 时取截至 EndTime（默认当前时间）最近的 Limit 根
+func FetchKlines(ctx context.Context, exchange Exchange, query KlineQuery) ([]Kline, error) {
+	latest := query.StartTime.IsZero()
+	if latest {
+		if query.Limit <= klinePageLimit {
+			return exchange.GetKlines(ctx, query)
+		}
+		step, err := intervalDuration(query.Interval)
+		if err != nil {
+			return nil, err
+		}
+		end := query.EndTime
+		if end.IsZero() {
+			end = time.Now()
+		}
+		query.StartTime = end.Add(-time.Duration(query.Limit) * step)
+	}
+
+	var result []Kline
+	for {
+		page := query
+		page.Limit = klinePageLimit
+		if !latest && query.Limit > 0 && query.Limit-len(result) < page.Limit {
+			page.Limit = query.Limit - len(result)
+		}
+
+		klines, err := exchange.GetKlines(ctx, page)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, klines...)
+		if len(klines) < page.Limit || (!latest && query.Limit > 0 && len(result) >= query.Limit) {
+			break
+		}
+
+		// 下一页从本页最后一根K线之后开始；交易所未按开始时间返回时停止，避免死循环
+		next := klines[len(klines)-1].OpenTime.Add(time.Millisecond)
+		if !next.After(page.StartTime) || (!query.EndTime.IsZero() && next.After(query.EndTime)) {
+			break
+		}
+		query.StartTime = next
+	}
+
+	if latest && len(result) > query.Limit {
+		result = result[len(result)-query.Limit:]
+	}
+	return result, nil
+}
+
+// intervalDuration 解析K线周期，如 1m、4h、1d、1w、1M
+func intervalDuration(interval string) (time.Duration, error) {
+	if len(interval) < 2 {
+		return 0, fmt.Errorf("K线周期无效: %s", interval)
+	}
+	unit, exists := intervalUnits[interval[len(interval)-1]]
+	count, err := strconv.Atoi(interval[:len(interval)-1])
+	if !exists || err != nil || count <= 0 {
+		return 0, fmt.Errorf("K线周期无效: %s", interval)
+	}
+	return time.Duration(count) * unit, nil
+}
@@ -0,0 +1,60 @@
+package ccxt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"task_scheduler/pkg/ccxt/binancetest"
+)
+
+func TestFetchKlines(t *testing.T) {
+	ctx := context.Background()
+	server := binancetest.NewServer()
+	defer server.Close()
+	server.SetPrice("BTCUSDT", 100000)
+	server.SetPrice("ETHUSDT", 3000)
+	server.SetPrice("SOLUSDT", 150)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	klines := make([]binancetest.Kline, 2500)
+	for i := range klines {
+		klines[i] = binancetest.Kline{OpenTime: start.Add(time.Duration(i) * time.Hour), Close: float64(i), CloseTime: start.Add(time.Duration(i+1)*time.Hour - time.Millisecond)}
+	}
+	server.SetKlines("SOLUSDT", klines)
+
+	client, err := NewClientWithConfig(ClientConfig{BaseURL: server.URL, RateLimit: NewRateLimiter(RateLimitConfig{})})
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+
+	// 超过1000根时自动分页，结果连续不重复
+	result, err := FetchKlines(ctx, client, KlineQuery{Symbol: "SOLUSDT", Interval: "1h", StartTime: start})
+	if err != nil || len(result) != 2500 || result[2499].Close != 2499 {
+		t.Fatalf("分页获取K线不正确: %d %v", len(result), err)
+	}
+	for i := 1; i < len(result); i++ {
+		if !result[i].OpenTime.After(result[i-1].OpenTime) {
+			t.Fatalf("第%d根K线顺序不正确", i)
+		}
+	}
+
+	// 未指定开始时间时取截至结束时间最近的 Limit 根
+	end := start.Add(2199 * time.Hour)
+	result, err = FetchKlines(ctx, client, KlineQuery{Symbol: "SOLUSDT", Interval: "1h", EndTime: end, Limit: 1200})
+	if err != nil || len(result) != 1200 || result[0].Close != 1000 || result[1199].Close != 2199 {
+		t.Errorf("获取最近K线不正确: %d %v", len(result), err)
+	}
+	prices, err := client.GetHistoryPricesBetween(ctx, "SOLUSDT", "1h", start.Add(10*time.Hour), start.Add(1500*time.Hour))
+	if err != nil || len(prices) != 1491 || prices[0][1] != 10 {
+		t.Errorf("按时间范围获取历史价格不正确: %d %v", len(prices), err)
+	}
+
+	// 一次请求获取多个交易对的价格
+	quotes, err := client.GetPrices(ctx, "ETHUSDT", "SOLUSDT")
+	if err != nil || len(quotes) != 2 || quotes["ETHUSDT"] != 3000 || quotes["SOLUSDT"] != 150 {
+		t.Errorf("多交易对价格不正确: %v %v", quotes, err)
+	}
+	if _, err := intervalDuration("1x"); err == nil {
+		t.Errorf("无效的K线周期应返回错误")
+	}
+}
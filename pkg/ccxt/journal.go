@@ -0,0 +1,162 @@
+package ccxt
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// 订单日志事件
+const (
+	JournalIntent   = "intent"   // 准备下单
+	JournalExisting = "existing" // 交易所已有相同客户端订单号的订单，未重复下单
+	JournalPlaced   = "placed"   // 下单成功
+	JournalFailed   = "failed"   // 下单失败
+)
+
+// maxClientOrderIDLen 客户端订单号的最大长度，为补单序号预留空间（币安上限为36）
+const maxClientOrderIDLen = 32
+
+// clientOrderIDInvalid 客户端订单号中不允许的字符
+var clientOrderIDInvalid = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// ClientOrderID 由任务名和计划执行时间生成确定的客户端订单号，同一次调度重试时得到相同的订单号
+// 格式为 {任务名}-{UTC时间 YYYYMMDDhhmmss}，任务名中不允许的字符替换为下划线，过长时截断
+func ClientOrderID(taskName string, scheduledTime time.Time) string {
+	suffix := "-" + scheduledTime.UTC().Format("20060102150405")
+	name := clientOrderIDInvalid.ReplaceAllString(taskName, "_")
+	if limit := maxClientOrderIDLen - len(suffix); len(name) > limit {
+		name = name[:limit]
+	}
+	return name + suffix
+}
+
+// JournalEntry 订单日志记录
+type JournalEntry struct {
+	Time          time.Time    `json:"time"`
+	Event         string       `json:"event"`
+	ClientOrderID string       `json:"client_order_id"`
+	Request       OrderRequest `json:"request"`
+	OrderID       string       `json:"order_id,omitempty"`
+	Status        OrderStatus  `json:"status,omitempty"`
+	Error         string       `json:"error,omitempty"`
+}
+
+// OrderJournal 本地订单日志，每次下单意图和结果追加一行JSON，用于排查重复下单和崩溃恢复
+type OrderJournal struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewOrderJournal 创建订单日志
+func NewOrderJournal(path string) *OrderJournal {
+	return &OrderJournal{path: path}
+}
+
+// Record 追加一条记录
+func (j *OrderJournal) Record(entry JournalEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	if err := os.MkdirAll(filepath.Dir(j.path), 0755); err != nil {
+		return fmt.Errorf("创建订单日志目录失败: %w", err)
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("序列化订单日志失败: %w", err)
+	}
+
+	file, err := os.OpenFile(j.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("打开订单日志失败: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("写入订单日志失败: %w", err)
+	}
+	return file.Sync()
+}
+
+// Entries 读取客户端订单号的所有记录，clientOrderID 为空时返回全部
+func (j *OrderJournal) Entries(clientOrderID string) ([]JournalEntry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	file, err := os.Open(j.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("打开订单日志失败: %w", err)
+	}
+	defer file.Close()
+
+	var entries []JournalEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			// 崩溃时可能留下不完整的最后一行
+			continue
+		}
+		if clientOrderID == "" || entry.ClientOrderID == clientOrderID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// PlaceOrderOnce 幂等下单：先按客户端订单号查询，已有订单时直接返回（existing 为 true），否则下单
+// 下单意图和结果写入 journal（可为 nil）；查询失败时不下单，避免在无法确认的情况下重复买入
+func PlaceOrderOnce(ctx context.Context, exchange Exchange, journal *OrderJournal, req OrderRequest) (order *Order, existing bool, err error) {
+	if req.ClientOrderID == "" {
+		return nil, false, fmt.Errorf("%w: 幂等下单需要客户端订单号", ErrInvalidOrder)
+	}
+	record := func(entry JournalEntry) error {
+		if journal == nil {
+			return nil
+		}
+		entry.ClientOrderID = req.ClientOrderID
+		entry.Request = req
+		return journal.Record(entry)
+	}
+
+	if err := record(JournalEntry{Event: JournalIntent}); err != nil {
+		return nil, false, err
+	}
+
+	order, err = exchange.GetOrderByClientID(ctx, req.Symbol, req.ClientOrderID)
+	if err == nil {
+		if err := record(JournalEntry{Event: JournalExisting, OrderID: order.ID, Status: order.Status}); err != nil {
+			log.Printf("写入订单日志失败: %v", err)
+		}
+		return order, true, nil
+	}
+	if !errors.Is(err, ErrOrderNotFound) {
+		return nil, false, fmt.Errorf("确认订单%s是否已提交失败: %w", req.ClientOrderID, err)
+	}
+
+	// 下单后日志写入失败不影响结果，重试时仍能通过交易所查到订单
+	order, err = exchange.PlaceOrder(ctx, req)
+	if err != nil {
+		if recordErr := record(JournalEntry{Event: JournalFailed, Error: err.Error()}); recordErr != nil {
+			log.Printf("写入订单日志失败: %v", recordErr)
+		}
+		return nil, false, err
+	}
+	if err := record(JournalEntry{Event: JournalPlaced, OrderID: order.ID, Status: order.Status}); err != nil {
+		log.Printf("写入订单日志失败: %v", err)
+	}
+	return order, false, nil
+}
@@ -0,0 +1,57 @@
+package ccxt
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPlaceOrderOnce(t *testing.T) {
+	ctx := context.Background()
+	scheduled := time.Date(2025, 1, 1, 7, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	if id := ClientOrderID("auto buy/BTC", scheduled); id != "auto_buy_BTC-20241231230000" {
+		t.Errorf("客户端订单号不正确: %s", id)
+	}
+	if id := ClientOrderID(strings.Repeat("x", 40), scheduled); len(id) != maxClientOrderIDLen {
+		t.Errorf("过长的任务名应截断: %s", id)
+	}
+
+	exchange, err := NewPaperExchange(PaperConfig{
+		Feed:            KlineFeed{"BTCUSDT": {{OpenTime: scheduled.Add(-time.Hour), Close: 100}}},
+		InitialBalances: map[string]float64{"USDT": 1000},
+		Clock:           func() time.Time { return scheduled },
+	})
+	if err != nil {
+		t.Fatalf("创建模拟交易所失败: %v", err)
+	}
+	journal := NewOrderJournal(filepath.Join(t.TempDir(), "journal.jsonl"))
+	req := OrderRequest{Symbol: "BTCUSDT", Side: SideBuy, Type: OrderTypeMarket, QuoteQuantity: 100, ClientOrderID: ClientOrderID("auto-buy", scheduled)}
+
+	// 同一次调度重试时不重复下单
+	first, existing, err := PlaceOrderOnce(ctx, exchange, journal, req)
+	if err != nil || existing {
+		t.Fatalf("首次下单失败: %v %v", existing, err)
+	}
+	second, existing, err := PlaceOrderOnce(ctx, exchange, journal, req)
+	if err != nil || !existing || second.ID != first.ID {
+		t.Errorf("重试应返回已有订单: %+v %v %v", second, existing, err)
+	}
+	balances, _ := exchange.GetBalances(ctx)
+	if usdt := FindBalance(balances, "USDT"); usdt.Free < 899 {
+		t.Errorf("重试后不应再次扣款: %+v", usdt)
+	}
+
+	entries, err := journal.Entries(req.ClientOrderID)
+	if err != nil {
+		t.Fatalf("读取订单日志失败: %v", err)
+	}
+	var events []string
+	for _, entry := range entries {
+		events = append(events, entry.Event)
+	}
+	if got := strings.Join(events, ","); got != "intent,placed,intent,existing" {
+		t.Errorf("订单日志不正确: %s", got)
+	}
+}
@@ -0,0 +1,597 @@
+package ccxt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// paperName 模拟交易所在注册表中的名称
+	paperName = "paper"
+	// defaultPaperSpread 默认买一卖一价差比例
+	defaultPaperSpread = 0.0002
+)
+
+// paperQuoteAssets 拆分交易对时识别的计价资产
+var paperQuoteAssets = []string{"USDT", "USDC", "FDUSD", "BUSD", "BTC", "ETH", "BNB"}
+
+// PaperExchange 实现 Exchange 接口
+var _ Exchange = (*PaperExchange)(nil)
+
+// PaperConfig 模拟交易所配置
+type PaperConfig struct {
+	Feed            PriceFeed             // 行情来源
+	LedgerPath      string                // 账本文件，为空时不持久化
+	InitialBalances map[string]float64    // 账本不存在时的初始余额，如 {"USDT": 10000}
+	FeeRate         float64               // 手续费率，从收到的资产中扣除，如 0.001
+	Slippage        float64               // 市价单滑点比例，如 0.0005
+	Spread          float64               // 买一卖一价差比例，默认 0.0002
+	Clock           func() time.Time      // 当前时间，回放历史行情时可指定，默认 time.Now
+	Symbols         map[string]SymbolInfo // 交易对下单规则，未配置的交易对不限制步长和最小金额
+}
+
+// PaperExchange 进程内模拟的现货交易所
+// 价格取行情中当前时间所在K线的收盘价，限价单在每次调用时按最新价格撮合，账本在每次变更后写入文件
+type PaperExchange struct {
+	config PaperConfig
+	mu     sync.Mutex
+	ledger paperLedger
+}
+
+// paperLedger 模拟账户账本
+type paperLedger struct {
+	Balances map[string]*Balance `json:"balances"`
+	Orders   []*paperOrder       `json:"orders"`
+	NextID   int64               `json:"next_id"`
+}
+
+// paperOrder 模拟订单
+type paperOrder struct {
+	Order
+	Reserved float64 `json:"reserved"` // 挂单冻结的资产数量（买单为计价资产，卖单为基础资产）
+}
+
+// NewPaperExchange 创建模拟交易所，账本文件存在时恢复账户
+func NewPaperExchange(cfg PaperConfig) (*PaperExchange, error) {
+	if cfg.Feed == nil {
+		return nil, fmt.Errorf("模拟交易所缺少行情来源")
+	}
+	if cfg.Spread <= 0 {
+		cfg.Spread = defaultPaperSpread
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = time.Now
+	}
+
+	pe := &PaperExchange{
+		config: cfg,
+		ledger: paperLedger{Balances: make(map[string]*Balance), NextID: 1},
+	}
+	if err := pe.load(); err != nil {
+		return nil, err
+	}
+	return pe, nil
+}
+
+// Name 返回交易所名称
+func (pe *PaperExchange) Name() string {
+	return paperName
+}
+
+// GetPrice 获取交易对在当前时间的价格
+func (pe *PaperExchange) GetPrice(ctx context.Context, symbol string) (float64, error) {
+	pe.mu.Lock()
+	defer pe.mu.Unlock()
+
+	if err := pe.match(); err != nil {
+		return 0, err
+	}
+	return pe.priceAt(symbol)
+}
+
+// GetBookTicker 以当前价格加减半个价差模拟最优挂单
+func (pe *PaperExchange) GetBookTicker(ctx context.Context, symbol string) (*BookTicker, error) {
+	pe.mu.Lock()
+	defer pe.mu.Unlock()
+
+	if err := pe.match(); err != nil {
+		return nil, err
+	}
+	price, err := pe.priceAt(symbol)
+	if err != nil {
+		return nil, err
+	}
+	bid, ask := pe.quote(price)
+	return &BookTicker{Symbol: symbol, BidPrice: bid, AskPrice: ask}, nil
+}
+
+// GetKlines 返回行情中当前时间之前的K线
+func (pe *PaperExchange) GetKlines(ctx context.Context, query KlineQuery) ([]Kline, error) {
+	klines, err := pe.config.Feed.Klines(query.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	now := pe.config.Clock()
+	var result []Kline
+	for _, kline := range klines {
+		if kline.OpenTime.After(now) ||
+			(!query.StartTime.IsZero() && kline.OpenTime.Before(query.StartTime)) ||
+			(!query.EndTime.IsZero() && kline.OpenTime.After(query.EndTime)) {
+			continue
+		}
+		result = append(result, kline)
+	}
+
+	// 与交易所一致：指定开始时间时从最早的开始取，否则取最近的
+	if query.Limit > 0 && len(result) > query.Limit {
+		if !query.StartTime.IsZero() {
+			result = result[:query.Limit]
+		} else {
+			result = result[len(result)-query.Limit:]
+		}
+	}
+	return result, nil
+}
+
+// GetBalances 获取余额不为零的资产
+func (pe *PaperExchange) GetBalances(ctx context.Context) ([]Balance, error) {
+	pe.mu.Lock()
+	defer pe.mu.Unlock()
+
+	if err := pe.match(); err != nil {
+		return nil, err
+	}
+
+	var balances []Balance
+	for _, balance := range pe.ledger.Balances {
+		if balance.Free > 0 || balance.Locked > 0 {
+			balances = append(balances, *balance)
+		}
+	}
+	sort.Slice(balances, func(i, j int) bool { return balances[i].Asset < balances[j].Asset })
+	return balances, nil
+}
+
+// PlaceOrder 下单：市价单按当前价格加滑点立即成交，限价单冻结资产后等待价格触及
+func (pe *PaperExchange) PlaceOrder(ctx context.Context, req OrderRequest) (*Order, error) {
+	pe.mu.Lock()
+	defer pe.mu.Unlock()
+
+	if err := pe.match(); err != nil {
+		return nil, err
+	}
+	info, err := pe.symbolInfo(req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	req = info.Normalize(req)
+	if err := info.Validate(req); err != nil {
+		return nil, err
+	}
+	base, quote := info.BaseAsset, info.QuoteAsset
+	price, err := pe.priceAt(req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	if req.Side != SideBuy && req.Side != SideSell {
+		return nil, fmt.Errorf("%w: 无效的买卖方向 %s", ErrInvalidOrder, req.Side)
+	}
+
+	order := &paperOrder{Order: Order{
+		ID:            strconv.FormatInt(pe.ledger.NextID, 10),
+		ClientOrderID: req.ClientOrderID,
+		Symbol:        req.Symbol,
+		Side:          req.Side,
+		Type:          req.Type,
+		Status:        OrderStatusNew,
+		CreatedAt:     pe.config.Clock(),
+	}}
+	bid, ask := pe.quote(price)
+
+	switch req.Type {
+	case OrderTypeMarket:
+		fillPrice := ask * (1 + pe.config.Slippage)
+		if req.Side == SideSell {
+			fillPrice = bid * (1 - pe.config.Slippage)
+		}
+		quantity := req.Quantity
+		if quantity <= 0 && req.QuoteQuantity > 0 {
+			quantity = req.QuoteQuantity / fillPrice
+		}
+		if quantity <= 0 {
+			return nil, fmt.Errorf("%w: 下单数量必须大于0", ErrInvalidOrder)
+		}
+		order.Quantity = quantity
+
+		if req.Side == SideBuy {
+			if err := pe.debit(quote, quantity*fillPrice); err != nil {
+				return nil, err
+			}
+		} else {
+			if err := pe.debit(base, quantity); err != nil {
+				return nil, err
+			}
+		}
+		pe.fill(order, base, quote, fillPrice)
+
+	case OrderTypeLimit:
+		if req.Price <= 0 || req.Quantity <= 0 {
+			return nil, fmt.Errorf("%w: 限价单价格和数量必须大于0", ErrInvalidOrder)
+		}
+		order.Price = req.Price
+		order.Quantity = req.Quantity
+
+		asset, amount := quote, req.Quantity*req.Price
+		if req.Side == SideSell {
+			asset, amount = base, req.Quantity
+		}
+		if err := pe.debit(asset, amount); err != nil {
+			return nil, err
+		}
+		pe.balance(asset).Locked += amount
+		order.Reserved = amount
+		pe.tryFill(order, bid, ask)
+
+	default:
+		return nil, fmt.Errorf("%w: 不支持的订单类型 %s", ErrInvalidOrder, req.Type)
+	}
+
+	pe.ledger.NextID++
+	pe.ledger.Orders = append(pe.ledger.Orders, order)
+	if err := pe.save(); err != nil {
+		return nil, err
+	}
+	result := order.Order
+	return &result, nil
+}
+
+// GetSymbolInfo 获取交易对的下单规则
+func (pe *PaperExchange) GetSymbolInfo(ctx context.Context, symbol string) (*SymbolInfo, error) {
+	return pe.symbolInfo(symbol)
+}
+
+// symbolInfo 返回配置的下单规则，未配置时只拆分交易对
+func (pe *PaperExchange) symbolInfo(symbol string) (*SymbolInfo, error) {
+	info := pe.config.Symbols[symbol]
+	if info.BaseAsset == "" || info.QuoteAsset == "" {
+		base, quote, err := splitSymbol(symbol)
+		if err != nil {
+			return nil, err
+		}
+		info.BaseAsset, info.QuoteAsset = base, quote
+	}
+	info.Symbol = symbol
+	return &info, nil
+}
+
+// GetOrder 查询订单
+func (pe *PaperExchange) GetOrder(ctx context.Context, symbol, orderID string) (*Order, error) {
+	pe.mu.Lock()
+	defer pe.mu.Unlock()
+
+	if err := pe.match(); err != nil {
+		return nil, err
+	}
+	order, err := pe.findOrder(symbol, orderID)
+	if err != nil {
+		return nil, err
+	}
+	result := order.Order
+	return &result, nil
+}
+
+// GetOrderByClientID 按客户端订单号查询订单，有多个时返回最近的一个
+func (pe *PaperExchange) GetOrderByClientID(ctx context.Context, symbol, clientOrderID string) (*Order, error) {
+	pe.mu.Lock()
+	defer pe.mu.Unlock()
+
+	if err := pe.match(); err != nil {
+		return nil, err
+	}
+	for i := len(pe.ledger.Orders) - 1; i >= 0; i-- {
+		order := pe.ledger.Orders[i]
+		if clientOrderID != "" && order.ClientOrderID == clientOrderID && order.Symbol == symbol {
+			result := order.Order
+			return &result, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrOrderNotFound, symbol, clientOrderID)
+}
+
+// CancelOrder 撤销未成交的限价单并解冻资产
+func (pe *PaperExchange) CancelOrder(ctx context.Context, symbol, orderID string) (*Order, error) {
+	pe.mu.Lock()
+	defer pe.mu.Unlock()
+
+	if err := pe.match(); err != nil {
+		return nil, err
+	}
+	order, err := pe.findOrder(symbol, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != OrderStatusNew {
+		return nil, fmt.Errorf("%w: 订单%s状态为%s，无法撤销", ErrInvalidOrder, orderID, order.Status)
+	}
+
+	base, quote, _ := splitSymbol(order.Symbol)
+	asset := quote
+	if order.Side == SideSell {
+		asset = base
+	}
+	pe.balance(asset).Locked -= order.Reserved
+	pe.balance(asset).Free += order.Reserved
+	order.Reserved = 0
+	order.Status = OrderStatusCanceled
+
+	if err := pe.save(); err != nil {
+		return nil, err
+	}
+	result := order.Order
+	return &result, nil
+}
+
+// match 按当前价格撮合所有挂单，有成交时写入账本
+func (pe *PaperExchange) match() error {
+	filled := false
+	for _, order := range pe.ledger.Orders {
+		if order.Status != OrderStatusNew {
+			continue
+		}
+		price, err := pe.priceAt(order.Symbol)
+		if err != nil {
+			continue
+		}
+		bid, ask := pe.quote(price)
+		if pe.tryFill(order, bid, ask) {
+			filled = true
+		}
+	}
+	if filled {
+		return pe.save()
+	}
+	return nil
+}
+
+// tryFill 价格触及时成交限价单：买单在卖一价不高于限价时以较低者成交，卖单在买一价不低于限价时以较高者成交
+func (pe *PaperExchange) tryFill(order *paperOrder, bid, ask float64) bool {
+	fillPrice := 0.0
+	switch {
+	case order.Side == SideBuy && ask <= order.Price:
+		fillPrice = ask
+	case order.Side == SideSell && bid >= order.Price:
+		fillPrice = bid
+	default:
+		return false
+	}
+
+	base, quote, _ := splitSymbol(order.Symbol)
+	if order.Side == SideBuy {
+		// 以更低价格成交时退回多冻结的部分
+		pe.balance(quote).Locked -= order.Reserved
+		pe.balance(quote).Free += order.Reserved - order.Quantity*fillPrice
+	} else {
+		pe.balance(base).Locked -= order.Reserved
+	}
+	order.Reserved = 0
+	pe.fill(order, base, quote, fillPrice)
+	return true
+}
+
+// fill 按成交价全部成交，手续费从收到的资产中扣除
+func (pe *PaperExchange) fill(order *paperOrder, base, quote string, fillPrice float64) {
+	quoteQty := order.Quantity * fillPrice
+	feeAsset, fee := base, order.Quantity*pe.config.FeeRate
+	if order.Side == SideSell {
+		feeAsset, fee = quote, quoteQty*pe.config.FeeRate
+	}
+	if order.Side == SideBuy {
+		pe.balance(base).Free += order.Quantity - fee
+	} else {
+		pe.balance(quote).Free += quoteQty - fee
+	}
+	order.ExecutedQty = order.Quantity
+	order.QuoteQty = quoteQty
+	order.AvgPrice = fillPrice
+	order.Fees = map[string]float64{feeAsset: fee}
+	order.Status = OrderStatusFilled
+}
+
+// debit 扣减可用余额
+func (pe *PaperExchange) debit(asset string, amount float64) error {
+	balance := pe.balance(asset)
+	if balance.Free < amount {
+		return fmt.Errorf("%w: %s可用 %s，需要 %s", ErrInsufficientBalance, asset, formatNumber(balance.Free), formatNumber(amount))
+	}
+	balance.Free -= amount
+	return nil
+}
+
+// balance 获取资产余额，不存在时创建
+func (pe *PaperExchange) balance(asset string) *Balance {
+	balance, exists := pe.ledger.Balances[asset]
+	if !exists {
+		balance = &Balance{Asset: asset}
+		pe.ledger.Balances[asset] = balance
+	}
+	return balance
+}
+
+// findOrder 按订单号查找订单
+func (pe *PaperExchange) findOrder(symbol, orderID string) (*paperOrder, error) {
+	for _, order := range pe.ledger.Orders {
+		if order.ID == orderID && order.Symbol == symbol {
+			return order, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrOrderNotFound, symbol, orderID)
+}
+
+// priceAt 当前时间所在K线的收盘价
+func (pe *PaperExchange) priceAt(symbol string) (float64, error) {
+	klines, err := pe.config.Feed.Klines(symbol)
+	if err != nil {
+		return 0, err
+	}
+
+	now := pe.config.Clock()
+	i := sort.Search(len(klines), func(i int) bool { return klines[i].OpenTime.After(now) })
+	if i == 0 {
+		return 0, fmt.Errorf("没有%s在%s之前的行情数据", symbol, now.Format("2006-01-02 15:04:05"))
+	}
+	return klines[i-1].Close, nil
+}
+
+// quote 由价格计算买一卖一价
+func (pe *PaperExchange) quote(price float64) (bid, ask float64) {
+	half := pe.config.Spread / 2
+	return price * (1 - half), price * (1 + half)
+}
+
+// load 从账本文件恢复账户，文件不存在时使用初始余额
+func (pe *PaperExchange) load() error {
+	if pe.config.LedgerPath != "" {
+		data, err := os.ReadFile(pe.config.LedgerPath)
+		if err == nil {
+			if err := json.Unmarshal(data, &pe.ledger); err != nil {
+				return fmt.Errorf("解析模拟账本失败: %w", err)
+			}
+			if pe.ledger.Balances == nil {
+				pe.ledger.Balances = make(map[string]*Balance)
+			}
+			return nil
+		}
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("读取模拟账本失败: %w", err)
+		}
+	}
+
+	for asset, amount := range pe.config.InitialBalances {
+		pe.balance(asset).Free = amount
+	}
+	return pe.save()
+}
+
+// save 原子写入账本文件
+func (pe *PaperExchange) save() error {
+	if pe.config.LedgerPath == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(pe.config.LedgerPath), 0755); err != nil {
+		return fmt.Errorf("创建模拟账本目录失败: %w", err)
+	}
+
+	data, err := json.MarshalIndent(pe.ledger, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化模拟账本失败: %w", err)
+	}
+	tmpPath := pe.config.LedgerPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("写入模拟账本失败: %w", err)
+	}
+	if err := os.Rename(tmpPath, pe.config.LedgerPath); err != nil {
+		return fmt.Errorf("写入模拟账本失败: %w", err)
+	}
+	return nil
+}
+
+// splitSymbol 将交易对拆分为基础资产和计价资产，如 BTCUSDT -> BTC、USDT
+func splitSymbol(symbol string) (base, quote string, err error) {
+	for _, quote := range paperQuoteAssets {
+		if strings.HasSuffix(symbol, quote) && len(symbol) > len(quote) {
+			return strings.TrimSuffix(symbol, quote), quote, nil
+		}
+	}
+	return "", "", fmt.Errorf("无法识别交易对的计价资产: %s", symbol)
+}
+
+func init() {
+	RegisterExchange(paperName, func(cfg ExchangeConfig) (Exchange, error) {
+		paperConfig, err := paperConfigFromOptions(cfg.Options)
+		if err != nil {
+			return nil, err
+		}
+		return NewPaperExchange(paperConfig)
+	})
+}
+
+// paperConfigFromOptions 由任务配置的 exchange_options 生成模拟交易所配置
+//
+//	klines_file: K线记录文件，未配置时读取 data_dir 中的AHR999历史数据（默认 data）
+//	ledger_path: 账本文件，默认 data/paper/ledger.json
+//	initial_balances: 初始余额，默认 {USDT: 10000}
+//	fee_rate: 手续费率，默认 0.001
+//	slippage: 市价单滑点，默认 0.0005
+func paperConfigFromOptions(options map[string]interface{}) (PaperConfig, error) {
+	cfg := PaperConfig{
+		LedgerPath:      optionString(options, "ledger_path", "data/paper/ledger.json"),
+		InitialBalances: map[string]float64{"USDT": 10000},
+	}
+
+	var err error
+	if klinesFile := optionString(options, "klines_file", ""); klinesFile != "" {
+		cfg.Feed, err = LoadKlineFeed(klinesFile)
+	} else {
+		cfg.Feed, err = LoadAhr999Feed(optionString(options, "data_dir", "data"))
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	if cfg.FeeRate, err = optionFloat(options, "fee_rate", 0.001); err != nil {
+		return cfg, err
+	}
+	if cfg.Slippage, err = optionFloat(options, "slippage", 0.0005); err != nil {
+		return cfg, err
+	}
+	if raw, exists := options["initial_balances"]; exists {
+		balances, ok := raw.(map[string]interface{})
+		if !ok {
+			return cfg, fmt.Errorf("initial_balances 必须是资产到数量的映射")
+		}
+		cfg.InitialBalances = make(map[string]float64, len(balances))
+		for asset := range balances {
+			amount, err := optionFloat(balances, asset, 0)
+			if err != nil {
+				return cfg, err
+			}
+			cfg.InitialBalances[strings.ToUpper(asset)] = amount
+		}
+	}
+	return cfg, nil
+}
+
+// optionString 读取字符串选项
+func optionString(options map[string]interface{}, key, defaultValue string) string {
+	if value, ok := options[key].(string); ok && value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// optionFloat 读取数字选项，兼容YAML解析出的整数
+func optionFloat(options map[string]interface{}, key string, defaultValue float64) (float64, error) {
+	raw, exists := options[key]
+	if !exists {
+		return defaultValue, nil
+	}
+	switch value := raw.(type) {
+	case float64:
+		return value, nil
+	case int:
+		return float64(value), nil
+	case int64:
+		return float64(value), nil
+	default:
+		return 0, fmt.Errorf("%s 必须是数字: %v", key, raw)
+	}
+}
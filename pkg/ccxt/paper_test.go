@@ -0,0 +1,96 @@
+package ccxt
+
+import (
+	"context"
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPaperExchange(t *testing.T) {
+	ctx := context.Background()
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	feed := KlineFeed{"BTCUSDT": {
+		{OpenTime: day(1), Close: 100},
+		{OpenTime: day(2), Close: 80},
+		{OpenTime: day(3), Close: 120},
+	}}
+	now := day(1)
+	cfg := PaperConfig{
+		Feed:            feed,
+		LedgerPath:      filepath.Join(t.TempDir(), "ledger.json"),
+		InitialBalances: map[string]float64{"USDT": 1000},
+		FeeRate:         0.01,
+		Spread:          1e-9,
+		Clock:           func() time.Time { return now },
+	}
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-6 }
+
+	exchange, err := NewPaperExchange(cfg)
+	if err != nil {
+		t.Fatalf("创建模拟交易所失败: %v", err)
+	}
+
+	// 市价单按金额买入，手续费从收到的BTC中扣除
+	order, err := exchange.PlaceOrder(ctx, OrderRequest{Symbol: "BTCUSDT", Side: SideBuy, Type: OrderTypeMarket, QuoteQuantity: 200})
+	if err != nil {
+		t.Fatalf("市价单失败: %v", err)
+	}
+	if !order.Filled() || !near(order.ExecutedQty, 2) || !near(order.QuoteQty, 200) || !near(order.AvgPrice, 100) || !near(order.Fees["BTC"], 0.02) {
+		t.Errorf("市价单成交不正确: %+v", order)
+	}
+
+	// 限价买单在价格跌到限价时成交，冻结的资金在成交前不可用
+	limit, err := exchange.PlaceOrder(ctx, OrderRequest{Symbol: "BTCUSDT", Side: SideBuy, Type: OrderTypeLimit, Quantity: 5, Price: 90})
+	if err != nil || limit.Status != OrderStatusNew {
+		t.Fatalf("限价单应挂单等待: %+v %v", limit, err)
+	}
+	if _, err := exchange.PlaceOrder(ctx, OrderRequest{Symbol: "BTCUSDT", Side: SideBuy, Type: OrderTypeLimit, Quantity: 5, Price: 90}); !errors.Is(err, ErrInsufficientBalance) {
+		t.Errorf("冻结后余额不足应下单失败，实际: %v", err)
+	}
+	now = day(2)
+	if filled, _ := exchange.GetOrder(ctx, "BTCUSDT", limit.ID); filled.Status != OrderStatusFilled || !near(filled.QuoteQty, 400) {
+		t.Errorf("价格触及后限价单应以80成交: %+v", filled)
+	}
+
+	// 撤销未成交的卖单后解冻BTC
+	sell, err := exchange.PlaceOrder(ctx, OrderRequest{Symbol: "BTCUSDT", Side: SideSell, Type: OrderTypeLimit, Quantity: 1, Price: 150})
+	if err != nil {
+		t.Fatalf("限价卖单失败: %v", err)
+	}
+	if _, err := exchange.CancelOrder(ctx, "BTCUSDT", sell.ID); err != nil {
+		t.Fatalf("撤单失败: %v", err)
+	}
+
+	if _, err := exchange.GetOrder(ctx, "BTCUSDT", "999"); !errors.Is(err, ErrOrderNotFound) {
+		t.Errorf("查询不存在的订单应返回 ErrOrderNotFound，实际: %v", err)
+	}
+
+	// 重启后从账本恢复
+	now = day(3)
+	restored, err := NewPaperExchange(cfg)
+	if err != nil {
+		t.Fatalf("恢复模拟交易所失败: %v", err)
+	}
+	balances, _ := restored.GetBalances(ctx)
+	btc, usdt := FindBalance(balances, "BTC"), FindBalance(balances, "USDT")
+	if !near(btc.Free, 2*0.99+5*0.99) || btc.Locked != 0 || !near(usdt.Free, 400) {
+		t.Errorf("余额不正确: BTC %+v USDT %+v", btc, usdt)
+	}
+	if price, _ := restored.GetPrice(ctx, "BTCUSDT"); price != 120 {
+		t.Errorf("期望当前价格120，实际: %v", price)
+	}
+}
+
+func TestLoadAhr999Feed(t *testing.T) {
+	feed, err := LoadAhr999Feed("../../data")
+	if err != nil {
+		t.Fatalf("读取AHR999历史数据失败: %v", err)
+	}
+	klines, _ := feed.Klines("BTCUSDT")
+	if len(klines) < 365 || !klines[0].OpenTime.Before(klines[len(klines)-1].OpenTime) {
+		t.Errorf("K线数量或顺序不正确: %d", len(klines))
+	}
+}
@@ -0,0 +1,176 @@
+package ccxt
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultPriceMaxAge 缓存行情的默认有效期，断线超过该时间后回退到 REST 接口
+const defaultPriceMaxAge = 30 * time.Second
+
+// PriceUpdate 最新价更新
+type PriceUpdate struct {
+	Symbol string
+	Price  float64
+	Time   time.Time
+}
+
+// PriceCache 内存行情缓存，由 MarketStream 写入，Client 查询价格和盘口时优先读取
+// 超过有效期的行情视为不存在
+type PriceCache struct {
+	maxAge time.Duration
+	now    func() time.Time
+
+	mu        sync.RWMutex
+	prices    map[string]PriceUpdate
+	books     map[string]cachedBook
+	klines    map[string]cachedKline
+	listeners map[int]func(PriceUpdate)
+	nextID    int
+}
+
+// cachedBook 缓存的最优挂单
+type cachedBook struct {
+	ticker BookTicker
+	time   time.Time
+}
+
+// cachedKline 缓存的最新K线
+type cachedKline struct {
+	kline Kline
+	time  time.Time
+}
+
+// NewPriceCache 创建行情缓存，maxAge 为0时使用默认有效期30秒
+func NewPriceCache(maxAge time.Duration) *PriceCache {
+	if maxAge <= 0 {
+		maxAge = defaultPriceMaxAge
+	}
+	return &PriceCache{
+		maxAge:    maxAge,
+		now:       time.Now,
+		prices:    make(map[string]PriceUpdate),
+		books:     make(map[string]cachedBook),
+		klines:    make(map[string]cachedKline),
+		listeners: make(map[int]func(PriceUpdate)),
+	}
+}
+
+// Price 返回交易对的最新价
+func (c *PriceCache) Price(symbol string) (float64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	update, exists := c.prices[strings.ToUpper(symbol)]
+	if !exists || c.stale(update.Time) {
+		return 0, false
+	}
+	return update.Price, true
+}
+
+// BookTicker 返回交易对的最优挂单
+func (c *PriceCache) BookTicker(symbol string) (*BookTicker, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	book, exists := c.books[strings.ToUpper(symbol)]
+	if !exists || c.stale(book.time) {
+		return nil, false
+	}
+	ticker := book.ticker
+	return &ticker, true
+}
+
+// Kline 返回交易对在该周期的最新K线（可能尚未收盘）
+func (c *PriceCache) Kline(symbol, interval string) (Kline, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	cached, exists := c.klines[klineKey(symbol, interval)]
+	if !exists || c.stale(cached.time) {
+		return Kline{}, false
+	}
+	return cached.kline, true
+}
+
+// SetPrice 写入最新价并通知监听者
+func (c *PriceCache) SetPrice(symbol string, price float64) {
+	update := PriceUpdate{Symbol: strings.ToUpper(symbol), Price: price, Time: c.now()}
+	c.mu.Lock()
+	c.prices[update.Symbol] = update
+	listeners := make([]func(PriceUpdate), 0, len(c.listeners))
+	for _, listener := range c.listeners {
+		listeners = append(listeners, listener)
+	}
+	c.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(update)
+	}
+}
+
+// SetBookTicker 写入最优挂单
+func (c *PriceCache) SetBookTicker(ticker BookTicker) {
+	ticker.Symbol = strings.ToUpper(ticker.Symbol)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.books[ticker.Symbol] = cachedBook{ticker: ticker, time: c.now()}
+}
+
+// SetKline 写入最新K线
+func (c *PriceCache) SetKline(symbol, interval string, kline Kline) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.klines[klineKey(symbol, interval)] = cachedKline{kline: kline, time: c.now()}
+}
+
+// OnPrice 注册最新价监听，每次价格更新时在推送协程中调用 handler，返回取消函数
+// handler 不应阻塞，耗时操作放到单独的协程中
+func (c *PriceCache) OnPrice(handler func(PriceUpdate)) (cancel func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id := c.nextID
+	c.nextID++
+	c.listeners[id] = handler
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.listeners, id)
+	}
+}
+
+// WaitPrice 等待交易对的最新价满足条件，返回满足条件时的价格，用于价格触发的任务
+// 缓存中已有满足条件的价格时立即返回
+func (c *PriceCache) WaitPrice(ctx context.Context, symbol string, condition func(price float64) bool) (float64, error) {
+	symbol = strings.ToUpper(symbol)
+	matched := make(chan float64, 1)
+	cancel := c.OnPrice(func(update PriceUpdate) {
+		if update.Symbol != symbol || !condition(update.Price) {
+			return
+		}
+		select {
+		case matched <- update.Price:
+		default:
+		}
+	})
+	defer cancel()
+
+	if price, ok := c.Price(symbol); ok && condition(price) {
+		return price, nil
+	}
+	select {
+	case price := <-matched:
+		return price, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// stale 行情是否已过期
+func (c *PriceCache) stale(updated time.Time) bool {
+	return c.now().Sub(updated) > c.maxAge
+}
+
+// klineKey K线缓存的键
+func klineKey(symbol, interval string) string {
+	return strings.ToUpper(symbol) + "@" + interval
+}
@@ -0,0 +1,248 @@
+package ccxt
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultWeightLimit 币安现货每分钟请求权重上限
+	defaultWeightLimit = 6000
+	// defaultRateLimitWait 接近上限或被限流时默认最多等待的时间
+	defaultRateLimitWait = 10 * time.Second
+	// weightWindow 请求权重的统计窗口，币安按整分钟重置
+	weightWindow = time.Minute
+)
+
+// endpointWeights 币安现货接口的请求权重，未列出的接口按1计算
+// 不带 symbol 参数时返回全部交易对的接口权重更高，见 requestWeight
+var endpointWeights = map[string]int{
+	"GET /api/v3/ticker/price":      2,
+	"GET /api/v3/ticker/bookTicker": 2,
+	"GET /api/v3/klines":            2,
+	"GET /api/v3/exchangeInfo":      20,
+	"GET /api/v3/account":           20,
+	"GET /api/v3/order":             4,
+	"GET /api/v3/openOrders":        6,
+	"GET /api/v3/allOrders":         20,
+	"GET /api/v3/myTrades":          20,
+}
+
+// RateLimitConfig 请求权重限制配置
+type RateLimitConfig struct {
+	WeightLimit int           // 每分钟请求权重上限，默认6000
+	MaxWait     time.Duration // 接近上限或被限流时最多等待的时间，需要等待更久时直接返回 ErrRateLimited，默认10秒
+}
+
+// RateLimitUsage 当前统计窗口的请求权重使用情况
+type RateLimitUsage struct {
+	Window    time.Time      // 统计窗口开始时间
+	Used      int            // 已使用的权重（本地统计与交易所返回的较大值）
+	Limit     int            // 权重上限
+	RetryAt   time.Time      // 被限流（HTTP 429/418）时允许再次请求的时间
+	Endpoints map[string]int // 各接口在本窗口内使用的权重，键如 "GET /api/v3/account"
+}
+
+// RateLimiter 客户端请求权重限制器
+// 请求前按接口权重预占额度，接近上限时等待下一个窗口，等待过久则直接失败；
+// 响应后以 X-MBX-USED-WEIGHT-1M 校正已用权重，遇到 HTTP 429/418 时按 Retry-After 暂停所有请求
+type RateLimiter struct {
+	config RateLimitConfig
+	now    func() time.Time
+	sleep  func(ctx context.Context, d time.Duration) error
+
+	mu        sync.Mutex
+	window    time.Time
+	used      int
+	retryAt   time.Time
+	endpoints map[string]int
+}
+
+var (
+	rateLimitersMu sync.Mutex
+	rateLimiters   = make(map[string]*RateLimiter)
+)
+
+// NewRateLimiter 创建请求权重限制器
+func NewRateLimiter(cfg RateLimitConfig) *RateLimiter {
+	if cfg.WeightLimit <= 0 {
+		cfg.WeightLimit = defaultWeightLimit
+	}
+	if cfg.MaxWait <= 0 {
+		cfg.MaxWait = defaultRateLimitWait
+	}
+	return &RateLimiter{
+		config:    cfg,
+		now:       time.Now,
+		sleep:     sleepContext,
+		endpoints: make(map[string]int),
+	}
+}
+
+// SharedRateLimiter 返回同一API密钥和接口地址共用的限制器，使用同一密钥的所有任务共享请求权重
+// 首次创建时使用 cfg，之后的调用忽略 cfg
+func SharedRateLimiter(apiKey, baseURL string, cfg RateLimitConfig) *RateLimiter {
+	rateLimitersMu.Lock()
+	defer rateLimitersMu.Unlock()
+
+	key := apiKey + "@" + baseURL
+	limiter, exists := rateLimiters[key]
+	if !exists {
+		limiter = NewRateLimiter(cfg)
+		rateLimiters[key] = limiter
+	}
+	return limiter
+}
+
+// Usage 返回当前统计窗口的使用情况
+func (l *RateLimiter) Usage() RateLimitUsage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.roll(l.now())
+	endpoints := make(map[string]int, len(l.endpoints))
+	for endpoint, weight := range l.endpoints {
+		endpoints[endpoint] = weight
+	}
+	return RateLimitUsage{Window: l.window, Used: l.used, Limit: l.config.WeightLimit, RetryAt: l.retryAt, Endpoints: endpoints}
+}
+
+// Wait 为接口预占 weight 权重，额度不足或处于限流期时等待，需要等待超过 MaxWait 时返回 ErrRateLimited
+func (l *RateLimiter) Wait(ctx context.Context, endpoint string, weight int) error {
+	for {
+		l.mu.Lock()
+		now := l.now()
+		l.roll(now)
+		var wait time.Duration
+		var reason string
+		switch {
+		case now.Before(l.retryAt):
+			wait = l.retryAt.Sub(now)
+			reason = "交易所限流中"
+		case l.used+weight > l.config.WeightLimit:
+			wait = l.window.Add(weightWindow).Sub(now)
+			reason = fmt.Sprintf("本分钟请求权重已用 %d/%d", l.used, l.config.WeightLimit)
+		default:
+			l.used += weight
+			l.endpoints[endpoint] += weight
+			l.mu.Unlock()
+			return nil
+		}
+		l.mu.Unlock()
+
+		if wait > l.config.MaxWait {
+			return fmt.Errorf("%w: %s，需等待 %s", ErrRateLimited, reason, wait.Round(time.Second))
+		}
+		if err := l.sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// Observe 根据响应头校正已用权重，HTTP 429/418 时按 Retry-After 暂停请求，返回是否被限流
+func (l *RateLimiter) Observe(resp *http.Response) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.roll(now)
+	if used, err := strconv.Atoi(resp.Header.Get("X-MBX-USED-WEIGHT-1M")); err == nil && used > l.used {
+		l.used = used
+	}
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusTeapot {
+		return false
+	}
+
+	// 未返回 Retry-After 时等到下一个窗口
+	retryAt := l.window.Add(weightWindow)
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+		retryAt = now.Add(time.Duration(seconds) * time.Second)
+	}
+	if retryAt.After(l.retryAt) {
+		l.retryAt = retryAt
+	}
+	return true
+}
+
+// roll 进入新的统计窗口时清零已用权重
+func (l *RateLimiter) roll(now time.Time) {
+	window := now.Truncate(weightWindow)
+	if window.After(l.window) {
+		l.window = window
+		l.used = 0
+		l.endpoints = make(map[string]int)
+	}
+}
+
+// rateLimitedTransport 请求前后经过限制器的 HTTP 传输层
+type rateLimitedTransport struct {
+	limiter *RateLimiter
+	next    http.RoundTripper
+}
+
+// RoundTrip 发送请求，未签名的 GET 请求被限流且等待时间不超过 MaxWait 时重试一次
+// 签名请求不重试：重放会沿用原来的 timestamp 和签名，等待后可能超出 recvWindow（-1021），
+// 直接返回限流响应，由调用方按 ErrRateLimited 处理
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	endpoint := req.Method + " " + req.URL.Path
+	weight := requestWeight(req)
+	for attempt := 0; ; attempt++ {
+		if err := t.limiter.Wait(req.Context(), endpoint, weight); err != nil {
+			return nil, err
+		}
+		resp, err := t.next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		if !t.limiter.Observe(resp) || attempt > 0 || req.Method != http.MethodGet || req.URL.Query().Get("signature") != "" {
+			return resp, nil
+		}
+
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// requestWeight 返回请求的权重
+func requestWeight(req *http.Request) int {
+	endpoint := req.Method + " " + req.URL.Path
+	weight, exists := endpointWeights[endpoint]
+	if !exists {
+		return 1
+	}
+	// 不指定交易对时返回全部交易对
+	if req.URL.Query().Get("symbol") == "" {
+		switch endpoint {
+		case "GET /api/v3/ticker/price", "GET /api/v3/ticker/bookTicker":
+			return 4
+		case "GET /api/v3/openOrders":
+			return 80
+		}
+	}
+	return weight
+}
+
+// sleepContext 等待 d 或 ctx 结束
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
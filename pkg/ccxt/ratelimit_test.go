@@ -0,0 +1,100 @@
+package ccxt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"task_scheduler/pkg/ccxt/binancetest"
+)
+
+func TestRateLimiter(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 12, 0, 50, 0, time.UTC)
+	var slept []time.Duration
+	limiter := NewRateLimiter(RateLimitConfig{WeightLimit: 50, MaxWait: 15 * time.Second})
+	limiter.now = func() time.Time { return now }
+	limiter.sleep = func(ctx context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		now = now.Add(d)
+		return nil
+	}
+
+	// 额度不足时等到下一分钟
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(ctx, "GET /api/v3/account", 20); err != nil {
+			t.Fatalf("第%d次请求失败: %v", i+1, err)
+		}
+	}
+	if len(slept) != 1 || slept[0] != 10*time.Second {
+		t.Errorf("应等待到下一分钟: %v", slept)
+	}
+	if usage := limiter.Usage(); usage.Used != 20 || usage.Endpoints["GET /api/v3/account"] != 20 {
+		t.Errorf("新窗口的权重统计不正确: %+v", usage)
+	}
+
+	// 需要等待超过 MaxWait 时直接失败
+	now = now.Add(5 * time.Second)
+	limiter.Wait(ctx, "GET /api/v3/account", 20)
+	if err := limiter.Wait(ctx, "GET /api/v3/account", 20); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("应返回 ErrRateLimited: %v", err)
+	}
+
+	// 通过客户端：响应头校正已用权重，429 按 Retry-After 重试 GET 请求
+	server := binancetest.NewServer()
+	defer server.Close()
+	server.SetPrice("BTCUSDT", 100000)
+	server.SetUsedWeight(1000)
+	limiter = NewRateLimiter(RateLimitConfig{})
+	client, err := NewClientWithConfig(ClientConfig{BaseURL: server.URL, RateLimit: limiter})
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	server.FailWithHeader("GET", "/api/v3/ticker/price", http.StatusTooManyRequests, -1003, "Too many requests.", http.Header{"Retry-After": {"0"}})
+	if price, err := client.GetPrice(ctx, "BTCUSDT"); err != nil || price != 100000 {
+		t.Errorf("429 后应重试成功: %v %v", price, err)
+	}
+	if usage := client.RateLimitUsage(); usage.Used < 1000 {
+		t.Errorf("应按响应头校正已用权重: %+v", usage)
+	}
+
+	// 重试后仍被限流时同样归类为 ErrRateLimited
+	for i := 0; i < 2; i++ {
+		server.FailWithHeader("GET", "/api/v3/ticker/price", http.StatusTooManyRequests, -1003, "Too many requests.", http.Header{"Retry-After": {"0"}})
+	}
+	if _, err := client.GetPrice(ctx, "BTCUSDT"); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("读取价格被限流时应返回 ErrRateLimited: %v", err)
+	}
+	var exchangeErr *ExchangeError
+	server.Fail("GET", "/api/v3/ticker/price", http.StatusBadRequest, -1121, "Invalid symbol.")
+	if _, err := client.GetPrice(ctx, "BTCUSDT"); !errors.As(err, &exchangeErr) || exchangeErr.Code != -1121 {
+		t.Errorf("读取价格失败时应返回交易所错误码: %v", err)
+	}
+	server.FailWithHeader("GET", "/api/v3/time", http.StatusTooManyRequests, -1003, "Too many requests.", http.Header{"Retry-After": {"0"}})
+	server.FailWithHeader("GET", "/api/v3/time", http.StatusTooManyRequests, -1003, "Too many requests.", http.Header{"Retry-After": {"0"}})
+	if _, err := client.GetServerTime(ctx); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("获取服务器时间被限流时应返回 ErrRateLimited: %v", err)
+	}
+
+	// 签名请求被限流时不重放，避免沿用过期的 timestamp 和签名
+	requests := len(server.Requests())
+	server.FailWithHeader("GET", "/api/v3/account", http.StatusTooManyRequests, -1003, "Too many requests.", http.Header{"Retry-After": {"0"}})
+	if _, err := client.GetBalances(ctx); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("签名请求被限流时应返回 ErrRateLimited: %v", err)
+	}
+	if sent := len(server.Requests()) - requests; sent != 1 {
+		t.Errorf("签名请求不应重放，实际请求%d次", sent)
+	}
+
+	// Retry-After 过长时不再请求交易所
+	server.FailWithHeader("GET", "/api/v3/ticker/price", http.StatusTeapot, -1003, "Way too many requests; IP banned.", http.Header{"Retry-After": {"120"}})
+	if _, err := client.GetPrice(ctx, "BTCUSDT"); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("被封禁时应返回 ErrRateLimited: %v", err)
+	}
+	requests = len(server.Requests())
+	if _, err := client.GetPrice(ctx, "BTCUSDT"); !errors.Is(err, ErrRateLimited) || len(server.Requests()) != requests {
+		t.Errorf("限流期间应直接失败: %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package ccxt
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ExchangeConfig 创建交易所实例的配置
+type ExchangeConfig struct {
+	APIKey     string
+	SecretKey  string
+	ProxyURL   string
+	BaseURL    string                 // 接口地址，为空时使用交易所默认地址
+	HTTPClient *http.Client           // 自定义HTTP客户端，用于测试
+	Prices     *PriceCache            // 行情缓存，支持的交易所查询价格时优先读取
+	Options    map[string]interface{} // 交易所特有的选项，来自任务配置的 exchange_options
+}
+
+// ExchangeFactory 交易所构造函数
+type ExchangeFactory func(cfg ExchangeConfig) (Exchange, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]ExchangeFactory)
+)
+
+// RegisterExchange 注册交易所，名称不区分大小写
+func RegisterExchange(name string, factory ExchangeFactory) error {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	name = strings.ToLower(name)
+	if name == "" {
+		return fmt.Errorf("交易所名称不能为空")
+	}
+	if factory == nil {
+		return fmt.Errorf("交易所构造函数不能为空")
+	}
+	if _, exists := factories[name]; exists {
+		return fmt.Errorf("交易所已存在: %s", name)
+	}
+
+	factories[name] = factory
+	return nil
+}
+
+// NewExchange 按名称创建交易所实例
+func NewExchange(name string, cfg ExchangeConfig) (Exchange, error) {
+	factoriesMu.RLock()
+	factory, exists := factories[strings.ToLower(name)]
+	factoriesMu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("交易所不存在: %s（可用: %s）", name, strings.Join(Exchanges(), ", "))
+	}
+	return factory(cfg)
+}
+
+// Exchanges 列出已注册的交易所名称
+func Exchanges() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ConfigFromEnv 从环境变量读取交易所配置：{NAME}_API_KEY、{NAME}_SECRET_KEY、{NAME}_PROXY_URL、{NAME}_BASE_URL，如 BINANCE_API_KEY
+func ConfigFromEnv(name string) ExchangeConfig {
+	prefix := strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+	return ExchangeConfig{
+		APIKey:    os.Getenv(prefix + "_API_KEY"),
+		SecretKey: os.Getenv(prefix + "_SECRET_KEY"),
+		ProxyURL:  os.Getenv(prefix + "_PROXY_URL"),
+		BaseURL:   os.Getenv(prefix + "_BASE_URL"),
+	}
+}
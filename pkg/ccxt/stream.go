@@ -0,0 +1,309 @@
+package ccxt
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+)
+
+const (
+	// defaultReconnectDelay 断线后首次重连的默认等待时间，之后每次加倍
+	defaultReconnectDelay = time.Second
+	// defaultMaxReconnectDelay 重连等待时间的默认上限
+	defaultMaxReconnectDelay = time.Minute
+)
+
+// streamKind 行情推送类型，每种类型使用一个组合连接
+type streamKind string
+
+const (
+	streamTicker     streamKind = "ticker"     // 24小时行情，取最新价
+	streamBookTicker streamKind = "bookTicker" // 最优挂单
+	streamKline      streamKind = "kline"      // K线
+)
+
+// streamKinds 所有推送类型
+var streamKinds = []streamKind{streamTicker, streamBookTicker, streamKline}
+
+// StreamConfig 行情推送配置
+type StreamConfig struct {
+	Tickers           []string          // 订阅最新价的交易对
+	BookTickers       []string          // 订阅最优挂单的交易对
+	Klines            map[string]string // 订阅K线的交易对及周期，如 {"BTCUSDT": "1m"}，每个交易对一个周期
+	MaxAge            time.Duration     // 缓存行情的有效期，默认30秒
+	ReconnectDelay    time.Duration     // 断线后首次重连的等待时间，默认1秒
+	MaxReconnectDelay time.Duration     // 重连等待时间上限，默认1分钟
+}
+
+// streamServers 建立组合推送连接的函数，默认使用 go-binance 的 websocket 实现，测试时替换
+type streamServers struct {
+	tickers     func(symbols []string, handler binance.WsMarketStatHandler, errHandler binance.ErrHandler) (doneC, stopC chan struct{}, err error)
+	bookTickers func(symbols []string, handler binance.WsBookTickerHandler, errHandler binance.ErrHandler) (doneC, stopC chan struct{}, err error)
+	klines      func(pairs map[string]string, handler binance.WsKlineHandler, errHandler binance.ErrHandler) (doneC, stopC chan struct{}, err error)
+}
+
+// MarketStream 币安行情推送，维护最新价、最优挂单和K线的内存缓存
+// 每种行情使用一个组合连接，断线后按指数退避自动重连，订阅变化时重建对应连接
+type MarketStream struct {
+	cache   *PriceCache
+	config  StreamConfig
+	servers streamServers
+
+	mu            sync.Mutex
+	subscriptions map[streamKind]map[string]string // 类型 -> 交易对 -> K线周期（其他类型为空）
+	restart       map[streamKind]chan struct{}
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+}
+
+// NewMarketStream 创建行情推送，调用 Start 后开始连接
+func NewMarketStream(cfg StreamConfig) *MarketStream {
+	if cfg.ReconnectDelay <= 0 {
+		cfg.ReconnectDelay = defaultReconnectDelay
+	}
+	if cfg.MaxReconnectDelay < cfg.ReconnectDelay {
+		cfg.MaxReconnectDelay = defaultMaxReconnectDelay
+	}
+	s := &MarketStream{
+		cache:  NewPriceCache(cfg.MaxAge),
+		config: cfg,
+		servers: streamServers{
+			tickers:     binance.WsCombinedMarketStatServe,
+			bookTickers: binance.WsCombinedBookTickerServe,
+			klines:      binance.WsCombinedKlineServe,
+		},
+		subscriptions: make(map[streamKind]map[string]string),
+		restart:       make(map[streamKind]chan struct{}),
+	}
+	for _, kind := range streamKinds {
+		s.subscriptions[kind] = make(map[string]string)
+		s.restart[kind] = make(chan struct{}, 1)
+	}
+	s.SubscribeTicker(cfg.Tickers...)
+	s.SubscribeBookTicker(cfg.BookTickers...)
+	for symbol, interval := range cfg.Klines {
+		s.SubscribeKline(symbol, interval)
+	}
+	return s
+}
+
+// Cache 返回行情缓存，可以传给 ClientConfig.Prices 让客户端优先读取
+func (s *MarketStream) Cache() *PriceCache {
+	return s.cache
+}
+
+// Start 开始连接并接收行情，ctx 结束或调用 Stop 时断开
+func (s *MarketStream) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		return fmt.Errorf("行情推送已启动")
+	}
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	for _, kind := range streamKinds {
+		s.wg.Add(1)
+		go func(kind streamKind) {
+			defer s.wg.Done()
+			s.run(ctx, kind)
+		}(kind)
+	}
+	return nil
+}
+
+// Stop 断开所有连接并等待退出
+func (s *MarketStream) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.cancel = nil
+	s.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		s.wg.Wait()
+	}
+}
+
+// SubscribeTicker 订阅交易对的最新价
+func (s *MarketStream) SubscribeTicker(symbols ...string) {
+	s.subscribe(streamTicker, symbols, "")
+}
+
+// UnsubscribeTicker 取消订阅交易对的最新价
+func (s *MarketStream) UnsubscribeTicker(symbols ...string) {
+	s.unsubscribe(streamTicker, symbols)
+}
+
+// SubscribeBookTicker 订阅交易对的最优挂单
+func (s *MarketStream) SubscribeBookTicker(symbols ...string) {
+	s.subscribe(streamBookTicker, symbols, "")
+}
+
+// UnsubscribeBookTicker 取消订阅交易对的最优挂单
+func (s *MarketStream) UnsubscribeBookTicker(symbols ...string) {
+	s.unsubscribe(streamBookTicker, symbols)
+}
+
+// SubscribeKline 订阅交易对的K线，已订阅其他周期时替换为新周期
+func (s *MarketStream) SubscribeKline(symbol, interval string) {
+	s.subscribe(streamKline, []string{symbol}, interval)
+}
+
+// UnsubscribeKline 取消订阅交易对的K线
+func (s *MarketStream) UnsubscribeKline(symbol string) {
+	s.unsubscribe(streamKline, []string{symbol})
+}
+
+// subscribe 添加订阅，有变化时通知重建连接
+func (s *MarketStream) subscribe(kind streamKind, symbols []string, interval string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := false
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(symbol)
+		if current, exists := s.subscriptions[kind][symbol]; !exists || current != interval {
+			s.subscriptions[kind][symbol] = interval
+			changed = true
+		}
+	}
+	if changed {
+		s.notify(kind)
+	}
+}
+
+// unsubscribe 取消订阅，有变化时通知重建连接
+func (s *MarketStream) unsubscribe(kind streamKind, symbols []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := false
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(symbol)
+		if _, exists := s.subscriptions[kind][symbol]; exists {
+			delete(s.subscriptions[kind], symbol)
+			changed = true
+		}
+	}
+	if changed {
+		s.notify(kind)
+	}
+}
+
+// notify 通知连接按最新订阅重建，未处理的通知只保留一个
+func (s *MarketStream) notify(kind streamKind) {
+	select {
+	case s.restart[kind] <- struct{}{}:
+	default:
+	}
+}
+
+// targets 返回当前订阅的副本
+func (s *MarketStream) targets(kind streamKind) map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	targets := make(map[string]string, len(s.subscriptions[kind]))
+	for symbol, interval := range s.subscriptions[kind] {
+		targets[symbol] = interval
+	}
+	return targets
+}
+
+// run 维护一种行情的连接：没有订阅时等待，断线后退避重连，订阅变化时重建
+func (s *MarketStream) run(ctx context.Context, kind streamKind) {
+	delay := s.config.ReconnectDelay
+	for {
+		targets := s.targets(kind)
+		if len(targets) == 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.restart[kind]:
+				continue
+			}
+		}
+
+		doneC, stopC, err := s.connect(kind, targets)
+		if err != nil {
+			log.Printf("连接%s行情推送失败: %v，%s后重连", kind, err, delay)
+		} else {
+			connectedAt := time.Now()
+			select {
+			case <-ctx.Done():
+				close(stopC)
+				<-doneC
+				return
+			case <-s.restart[kind]:
+				close(stopC)
+				<-doneC
+				continue
+			case <-doneC:
+				// 连接稳定运行过一段时间后重新从最短等待开始退避
+				if time.Since(connectedAt) > s.config.MaxReconnectDelay {
+					delay = s.config.ReconnectDelay
+				}
+				log.Printf("%s行情推送已断开，%s后重连", kind, delay)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.restart[kind]:
+		case <-time.After(delay):
+		}
+		if delay *= 2; delay > s.config.MaxReconnectDelay {
+			delay = s.config.MaxReconnectDelay
+		}
+	}
+}
+
+// connect 按订阅建立一种行情的组合连接
+func (s *MarketStream) connect(kind streamKind, targets map[string]string) (doneC, stopC chan struct{}, err error) {
+	errHandler := func(err error) {
+		log.Printf("%s行情推送错误: %v", kind, err)
+	}
+	symbols := make([]string, 0, len(targets))
+	for symbol := range targets {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	switch kind {
+	case streamTicker:
+		return s.servers.tickers(symbols, func(event *binance.WsMarketStatEvent) {
+			if price := parseNumber(event.LastPrice); price > 0 {
+				s.cache.SetPrice(event.Symbol, price)
+			}
+		}, errHandler)
+	case streamBookTicker:
+		return s.servers.bookTickers(symbols, func(event *binance.WsBookTickerEvent) {
+			s.cache.SetBookTicker(BookTicker{
+				Symbol:   event.Symbol,
+				BidPrice: parseNumber(event.BestBidPrice),
+				BidQty:   parseNumber(event.BestBidQty),
+				AskPrice: parseNumber(event.BestAskPrice),
+				AskQty:   parseNumber(event.BestAskQty),
+			})
+		}, errHandler)
+	default:
+		return s.servers.klines(targets, func(event *binance.WsKlineEvent) {
+			k := event.Kline
+			s.cache.SetKline(event.Symbol, k.Interval, Kline{
+				OpenTime:  time.UnixMilli(k.StartTime),
+				Open:      parseNumber(k.Open),
+				High:      parseNumber(k.High),
+				Low:       parseNumber(k.Low),
+				Close:     parseNumber(k.Close),
+				Volume:    parseNumber(k.Volume),
+				CloseTime: time.UnixMilli(k.EndTime),
+			})
+		}, errHandler)
+	}
+}
@@ -0,0 +1,95 @@
+package ccxt
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adshao/go-binance/v2"
+
+	"task_scheduler/pkg/ccxt/binancetest"
+)
+
+// fakeStreamConn 模拟的行情推送连接
+type fakeStreamConn struct {
+	symbols []string
+	handler binance.WsMarketStatHandler
+	drop    chan struct{}
+}
+
+func TestMarketStream(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	connections := make(chan *fakeStreamConn, 10)
+	stream := NewMarketStream(StreamConfig{Tickers: []string{"BTCUSDT"}, ReconnectDelay: time.Millisecond})
+	stream.servers.tickers = func(symbols []string, handler binance.WsMarketStatHandler, errHandler binance.ErrHandler) (chan struct{}, chan struct{}, error) {
+		conn := &fakeStreamConn{symbols: symbols, handler: handler, drop: make(chan struct{})}
+		doneC, stopC := make(chan struct{}), make(chan struct{})
+		go func() {
+			select {
+			case <-stopC:
+			case <-conn.drop:
+			}
+			close(doneC)
+		}()
+		connections <- conn
+		return doneC, stopC, nil
+	}
+	next := func() *fakeStreamConn {
+		select {
+		case conn := <-connections:
+			return conn
+		case <-ctx.Done():
+			t.Fatal("等待连接超时")
+			return nil
+		}
+	}
+	if err := stream.Start(ctx); err != nil {
+		t.Fatalf("启动行情推送失败: %v", err)
+	}
+	defer stream.Stop()
+
+	// 价格推送写入缓存，等待价格条件的任务被唤醒
+	conn := next()
+	waited := make(chan float64, 1)
+	go func() {
+		price, _ := stream.Cache().WaitPrice(ctx, "BTCUSDT", func(price float64) bool { return price <= 90000 })
+		waited <- price
+	}()
+	conn.handler(&binance.WsMarketStatEvent{Symbol: "BTCUSDT", LastPrice: "95000"})
+	if price, ok := stream.Cache().Price("BTCUSDT"); !ok || price != 95000 {
+		t.Errorf("缓存价格不正确: %v %v", price, ok)
+	}
+
+	// 断线后自动重连
+	close(conn.drop)
+	conn = next()
+	conn.handler(&binance.WsMarketStatEvent{Symbol: "BTCUSDT", LastPrice: "89000"})
+	if price := <-waited; price != 89000 {
+		t.Errorf("价格触发不正确: %v", price)
+	}
+
+	// 订阅变化时按新的交易对重建连接
+	stream.SubscribeTicker("ethusdt")
+	if conn = next(); strings.Join(conn.symbols, ",") != "BTCUSDT,ETHUSDT" {
+		t.Errorf("重建连接的订阅不正确: %v", conn.symbols)
+	}
+
+	// 客户端优先读取缓存，过期后回退到 REST 接口
+	server := binancetest.NewServer()
+	defer server.Close()
+	server.SetPrice("BTCUSDT", 100000)
+	client, err := NewClientWithConfig(ClientConfig{BaseURL: server.URL, Prices: stream.Cache()})
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	if price, err := client.GetPrice(ctx, "BTCUSDT"); err != nil || price != 89000 || len(server.Requests()) != 0 {
+		t.Errorf("应读取缓存价格: %v %v %v", price, err, server.Requests())
+	}
+	stream.Cache().now = func() time.Time { return time.Now().Add(time.Hour) }
+	if price, err := client.GetPrice(ctx, "BTCUSDT"); err != nil || price != 100000 {
+		t.Errorf("缓存过期后应查询交易所: %v %v", price, err)
+	}
+}
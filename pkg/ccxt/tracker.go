@@ -0,0 +1,509 @@
+package ccxt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// TimeoutAction 订单超时未成交时的处理方式
+type TimeoutAction string
+
+const (
+	TimeoutCancel  TimeoutAction = "cancel"  // 撤单，保留已成交部分
+	TimeoutMarket  TimeoutAction = "market"  // 撤单后剩余数量以市价单成交
+	TimeoutReprice TimeoutAction = "reprice" // 撤单后按最新盘口重新挂限价单
+)
+
+const (
+	// defaultOrderTimeout 限价单等待成交的默认时间
+	defaultOrderTimeout = time.Minute
+	// defaultMaxReprices 默认最多重新挂单次数
+	defaultMaxReprices = 3
+)
+
+// TrackerConfig 订单跟踪配置
+type TrackerConfig struct {
+	PollInterval time.Duration // 查询订单状态的间隔，默认2秒
+	Timeout      time.Duration // 限价单等待成交的时间，默认1分钟
+	OnTimeout    TimeoutAction // 超时处理方式，默认撤单
+	MaxReprices  int           // 重新挂单的最大次数，用完后按撤单处理，默认3
+	StatePath    string        // 未完成订单的持久化文件，为空时不持久化
+	Journal      *OrderJournal // 订单日志，带客户端订单号的下单记录意图和结果，可为空
+}
+
+// MaxWait 返回一次下单最长等待成交的时间，重新挂单时包括每次重新挂单后的等待
+func (c TrackerConfig) MaxWait() time.Duration {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = defaultOrderTimeout
+	}
+	if c.OnTimeout != TimeoutReprice {
+		return timeout
+	}
+	reprices := c.MaxReprices
+	if reprices <= 0 {
+		reprices = defaultMaxReprices
+	}
+	return time.Duration(reprices+1) * timeout
+}
+
+// TrackedOrder 正在跟踪的订单
+type TrackedOrder struct {
+	Request  OrderRequest `json:"request"`   // 原始下单请求，用于计算剩余数量
+	Order    *Order       `json:"order"`     // 当前订单的最新状态
+	Previous []*Order     `json:"previous"`  // 超时撤销的订单，按下单顺序
+	Deadline time.Time    `json:"deadline"`  // 当前订单的超时时间
+	TimedOut bool         `json:"timed_out"` // 当前订单是否因超时被撤销
+	Reprices int          `json:"reprices"`  // 已重新挂单次数
+}
+
+// TrackResult 订单跟踪结果，汇总原始订单及超时后补下的订单
+type TrackResult struct {
+	Orders      []*Order           `json:"orders"`       // 依次下的所有订单，最后一个为最终订单
+	Status      OrderStatus        `json:"status"`       // 最终订单成交时为 FILLED，有成交但未全部成交时为 PARTIALLY_FILLED，否则为最终订单的状态
+	ExecutedQty float64            `json:"executed_qty"` // 累计成交数量
+	QuoteQty    float64            `json:"quote_qty"`    // 累计成交金额
+	AvgPrice    float64            `json:"avg_price"`    // 成交均价
+	Fees        map[string]float64 `json:"fees,omitempty"`
+	TimedOut    bool               `json:"timed_out"` // 是否发生过超时
+	Duplicate   bool               `json:"duplicate"` // 下单前交易所已有相同客户端订单号的订单，本次未重复下单
+}
+
+// Filled 是否已全部成交
+func (r *TrackResult) Filled() bool {
+	return r.Status == OrderStatusFilled
+}
+
+// Order 返回最终订单
+func (r *TrackResult) Order() *Order {
+	return r.Orders[len(r.Orders)-1]
+}
+
+// OrderTracker 跟踪订单直到成交、撤销或过期，超时后按配置撤单、市价补单或重新挂单
+// 未完成的订单写入 StatePath，重启后通过 Resume 继续跟踪
+type OrderTracker struct {
+	exchange Exchange
+	config   TrackerConfig
+	mu       sync.Mutex
+	open     map[string]*TrackedOrder // 键为首个订单的订单号
+}
+
+// NewOrderTracker 创建订单跟踪器，并加载上次未完成的订单
+func NewOrderTracker(exchange Exchange, cfg TrackerConfig) (*OrderTracker, error) {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 2 * time.Second
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultOrderTimeout
+	}
+	switch cfg.OnTimeout {
+	case "":
+		cfg.OnTimeout = TimeoutCancel
+	case TimeoutCancel, TimeoutMarket, TimeoutReprice:
+	default:
+		return nil, fmt.Errorf("不支持的超时处理方式: %s", cfg.OnTimeout)
+	}
+	if cfg.MaxReprices <= 0 {
+		cfg.MaxReprices = defaultMaxReprices
+	}
+
+	tracker := &OrderTracker{
+		exchange: exchange,
+		config:   cfg,
+		open:     make(map[string]*TrackedOrder),
+	}
+	if err := tracker.load(); err != nil {
+		return nil, err
+	}
+	return tracker, nil
+}
+
+// Place 下单并跟踪到订单结束
+// 请求带客户端订单号时幂等下单：已有相同订单号的订单则不再下单，继续跟踪已有订单
+func (t *OrderTracker) Place(ctx context.Context, req OrderRequest) (*TrackResult, error) {
+	if req.ClientOrderID == "" {
+		order, err := t.exchange.PlaceOrder(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return t.Track(ctx, req, order)
+	}
+
+	order, existing, err := PlaceOrderOnce(ctx, t.exchange, t.config.Journal, req)
+	if err != nil {
+		return nil, err
+	}
+	if !existing {
+		return t.Track(ctx, req, order)
+	}
+
+	// 已有订单仍在跟踪列表中时（如上次跟踪中断）沿用原来的订单链，否则按补单序号从交易所找回订单链
+	t.mu.Lock()
+	open, tracking := t.open[order.ID]
+	t.mu.Unlock()
+	var tracked TrackedOrder
+	if tracking {
+		tracked = *open
+	} else {
+		tracked = TrackedOrder{Request: req, Order: order, Deadline: time.Now().Add(t.config.Timeout)}
+		for i := 1; ; i++ {
+			next, err := t.exchange.GetOrderByClientID(ctx, req.Symbol, fmt.Sprintf("%s-%d", req.ClientOrderID, i))
+			if errors.Is(err, ErrOrderNotFound) {
+				break
+			}
+			if err != nil {
+				return nil, err
+			}
+			tracked.Previous = append(tracked.Previous, tracked.Order)
+			tracked.Order = next
+		}
+		if err := t.update(order.ID, &tracked); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := t.follow(ctx, order.ID, &tracked)
+	if result != nil {
+		result.Duplicate = true
+	}
+	return result, err
+}
+
+// Track 跟踪已下的订单直到结束
+// 补单失败时返回已有的成交结果和错误；查询失败或 ctx 取消时订单保留在待跟踪列表中
+func (t *OrderTracker) Track(ctx context.Context, req OrderRequest, order *Order) (*TrackResult, error) {
+	tracked := &TrackedOrder{
+		Request:  req,
+		Order:    order,
+		Deadline: time.Now().Add(t.config.Timeout),
+	}
+	key := order.ID
+	if err := t.update(key, tracked); err != nil {
+		return nil, err
+	}
+	return t.follow(ctx, key, tracked)
+}
+
+// Resume 继续跟踪上次未完成的订单，超时时间沿用下单时的设置
+func (t *OrderTracker) Resume(ctx context.Context) ([]*TrackResult, error) {
+	t.mu.Lock()
+	keys := make([]string, 0, len(t.open))
+	for key := range t.open {
+		keys = append(keys, key)
+	}
+	t.mu.Unlock()
+	sort.Strings(keys)
+
+	var results []*TrackResult
+	var errs []error
+	for _, key := range keys {
+		t.mu.Lock()
+		open, exists := t.open[key]
+		t.mu.Unlock()
+		if !exists {
+			continue
+		}
+
+		tracked := *open
+		result, err := t.follow(ctx, key, &tracked)
+		if result != nil {
+			results = append(results, result)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("订单%s: %w", key, err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+// Open 返回未完成的订单
+func (t *OrderTracker) Open() []TrackedOrder {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	orders := make([]TrackedOrder, 0, len(t.open))
+	for _, tracked := range t.open {
+		orders = append(orders, *tracked)
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].Order.CreatedAt.Before(orders[j].Order.CreatedAt) })
+	return orders
+}
+
+// follow 轮询订单状态，超时后撤单并按配置补单，直到订单链结束
+func (t *OrderTracker) follow(ctx context.Context, key string, tracked *TrackedOrder) (*TrackResult, error) {
+	for {
+		if !orderDone(tracked.Order.Status) {
+			if time.Now().Before(tracked.Deadline) {
+				if err := t.wait(ctx, tracked.Deadline); err != nil {
+					return nil, err
+				}
+				order, err := t.exchange.GetOrder(ctx, tracked.Order.Symbol, tracked.Order.ID)
+				if errors.Is(err, ErrRateLimited) {
+					log.Printf("查询订单%s被限流，稍后重试: %v", tracked.Order.ID, err)
+					continue
+				}
+				if err != nil {
+					return nil, err
+				}
+				tracked.Order = order
+				if err := t.update(key, tracked); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			// 超时撤单，撤单失败时可能已经成交，重新查询一次
+			order, err := t.exchange.CancelOrder(ctx, tracked.Order.Symbol, tracked.Order.ID)
+			if err != nil {
+				if order, err = t.exchange.GetOrder(ctx, tracked.Order.Symbol, tracked.Order.ID); err != nil {
+					return nil, fmt.Errorf("撤销超时订单%s失败: %w", tracked.Order.ID, err)
+				}
+				if !orderDone(order.Status) {
+					return nil, fmt.Errorf("撤销超时订单%s失败，订单状态为%s", order.ID, order.Status)
+				}
+			}
+			tracked.Order = order
+			tracked.TimedOut = order.Status == OrderStatusCanceled
+			if err := t.update(key, tracked); err != nil {
+				return nil, err
+			}
+		}
+
+		// 订单已结束，只有因超时撤销且仍有剩余时才补单
+		if !tracked.TimedOut {
+			return tracked.result(), t.remove(key)
+		}
+		next, err := t.replace(ctx, tracked)
+		if err != nil || next == nil {
+			if removeErr := t.remove(key); removeErr != nil && err == nil {
+				err = removeErr
+			}
+			if err != nil {
+				err = fmt.Errorf("超时后补单失败: %w", err)
+			}
+			return tracked.result(), err
+		}
+
+		tracked.Previous = append(tracked.Previous, tracked.Order)
+		tracked.Order = next
+		tracked.Deadline = time.Now().Add(t.config.Timeout)
+		tracked.TimedOut = false
+		if err := t.update(key, tracked); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// replace 按超时处理方式为剩余数量补单，无需补单时返回 nil
+func (t *OrderTracker) replace(ctx context.Context, tracked *TrackedOrder) (*Order, error) {
+	info, err := t.exchange.GetSymbolInfo(ctx, tracked.Request.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	req, ok := tracked.remaining(info)
+	if !ok {
+		return nil, nil
+	}
+
+	switch t.config.OnTimeout {
+	case TimeoutMarket:
+		req.Type = OrderTypeMarket
+		req.Price = 0
+		if !remainderValid(info, req) {
+			return nil, nil
+		}
+		return t.placeRemainder(ctx, req)
+
+	case TimeoutReprice:
+		if tracked.Reprices >= t.config.MaxReprices || req.Quantity <= 0 {
+			return nil, nil
+		}
+		ticker, err := t.exchange.GetBookTicker(ctx, req.Symbol)
+		if err != nil {
+			return nil, err
+		}
+		req.Type = OrderTypeLimit
+		req.Price = ticker.AskPrice
+		if req.Side == SideSell {
+			req.Price = ticker.BidPrice
+		}
+		if req = info.Normalize(req); !remainderValid(info, req) {
+			return nil, nil
+		}
+		order, err := t.placeRemainder(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		tracked.Reprices++
+		return order, nil
+	}
+	return nil, nil
+}
+
+// wait 等待一个轮询间隔，不超过超时时间
+func (t *OrderTracker) wait(ctx context.Context, deadline time.Time) error {
+	delay := t.config.PollInterval
+	if remaining := time.Until(deadline); remaining < delay {
+		delay = remaining
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// placeRemainder 为剩余部分下单，带客户端订单号时同样幂等
+func (t *OrderTracker) placeRemainder(ctx context.Context, req OrderRequest) (*Order, error) {
+	if req.ClientOrderID == "" {
+		return t.exchange.PlaceOrder(ctx, req)
+	}
+	order, _, err := PlaceOrderOnce(ctx, t.exchange, t.config.Journal, req)
+	return order, err
+}
+
+// remainderValid 剩余部分低于最小数量或最小下单金额时无法补单，按撤单处理
+func remainderValid(info *SymbolInfo, req OrderRequest) bool {
+	if err := info.Validate(req); err != nil {
+		log.Printf("剩余部分无法补单: %v", err)
+		return false
+	}
+	return true
+}
+
+// remaining 按交易规则舍入计算原始请求中尚未成交的部分，客户端订单号追加补单序号，如 auto-buy-20250101070000-1
+func (tracked *TrackedOrder) remaining(info *SymbolInfo) (OrderRequest, bool) {
+	executedQty, quoteQty := 0.0, 0.0
+	for _, order := range tracked.orders() {
+		executedQty += order.ExecutedQty
+		quoteQty += order.QuoteQty
+	}
+
+	req := tracked.Request
+	if req.ClientOrderID != "" {
+		req.ClientOrderID = fmt.Sprintf("%s-%d", req.ClientOrderID, len(tracked.Previous)+1)
+	}
+	if req.Quantity > 0 {
+		req.Quantity = info.RoundQuantity(req.Quantity - executedQty).InexactFloat64()
+		return req, req.Quantity > 0
+	}
+	req.QuoteQuantity = info.RoundQuoteQuantity(req.QuoteQuantity - quoteQty).InexactFloat64()
+	return req, req.QuoteQuantity > 0
+}
+
+// orders 返回订单链上的所有订单
+func (tracked *TrackedOrder) orders() []*Order {
+	orders := make([]*Order, 0, len(tracked.Previous)+1)
+	orders = append(orders, tracked.Previous...)
+	return append(orders, tracked.Order)
+}
+
+// result 汇总订单链的成交结果
+func (tracked *TrackedOrder) result() *TrackResult {
+	result := &TrackResult{Orders: tracked.orders(), TimedOut: tracked.TimedOut || len(tracked.Previous) > 0}
+	for _, order := range result.Orders {
+		result.ExecutedQty += order.ExecutedQty
+		result.QuoteQty += order.QuoteQty
+		for asset, fee := range order.Fees {
+			if result.Fees == nil {
+				result.Fees = make(map[string]float64)
+			}
+			result.Fees[asset] += fee
+		}
+	}
+	if result.ExecutedQty > 0 {
+		result.AvgPrice = result.QuoteQty / result.ExecutedQty
+	}
+
+	switch {
+	case tracked.Order.Filled():
+		result.Status = OrderStatusFilled
+	case result.ExecutedQty > 0:
+		result.Status = OrderStatusPartiallyFilled
+	default:
+		result.Status = tracked.Order.Status
+	}
+	return result
+}
+
+// orderDone 订单是否已结束（不会再有成交）
+func orderDone(status OrderStatus) bool {
+	switch status {
+	case OrderStatusFilled, OrderStatusCanceled, OrderStatusRejected, OrderStatusExpired:
+		return true
+	}
+	return false
+}
+
+// update 记录订单的最新状态并写入状态文件
+func (t *OrderTracker) update(key string, tracked *TrackedOrder) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// 保存副本，避免写文件时与跟踪中的修改竞争
+	snapshot := *tracked
+	t.open[key] = &snapshot
+	return t.save()
+}
+
+// remove 订单链结束后从待跟踪列表中移除
+func (t *OrderTracker) remove(key string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.open, key)
+	return t.save()
+}
+
+// load 读取上次未完成的订单
+func (t *OrderTracker) load() error {
+	if t.config.StatePath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(t.config.StatePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("读取订单跟踪状态失败: %w", err)
+	}
+	if err := json.Unmarshal(data, &t.open); err != nil {
+		return fmt.Errorf("解析订单跟踪状态失败: %w", err)
+	}
+	return nil
+}
+
+// save 原子写入状态文件，调用方需持有锁
+func (t *OrderTracker) save() error {
+	if t.config.StatePath == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(t.config.StatePath), 0755); err != nil {
+		return fmt.Errorf("创建订单跟踪目录失败: %w", err)
+	}
+
+	data, err := json.MarshalIndent(t.open, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化订单跟踪状态失败: %w", err)
+	}
+	tmpPath := t.config.StatePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("写入订单跟踪状态失败: %w", err)
+	}
+	if err := os.Rename(tmpPath, t.config.StatePath); err != nil {
+		return fmt.Errorf("写入订单跟踪状态失败: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package ccxt
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOrderTracker(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	now := day(1)
+	exchange, err := NewPaperExchange(PaperConfig{
+		Feed: KlineFeed{"BTCUSDT": {
+			{OpenTime: day(1), Close: 100},
+			{OpenTime: day(2), Close: 80},
+		}},
+		InitialBalances: map[string]float64{"USDT": 1000},
+		Spread:          1e-9,
+		Clock:           func() time.Time { return now },
+	})
+	if err != nil {
+		t.Fatalf("创建模拟交易所失败: %v", err)
+	}
+	statePath := filepath.Join(t.TempDir(), "orders.json")
+
+	// 超时后撤单并以市价补齐剩余数量
+	tracker, err := NewOrderTracker(exchange, TrackerConfig{PollInterval: 5 * time.Millisecond, Timeout: 20 * time.Millisecond, OnTimeout: TimeoutMarket})
+	if err != nil {
+		t.Fatalf("创建订单跟踪器失败: %v", err)
+	}
+	result, err := tracker.Place(context.Background(), OrderRequest{Symbol: "BTCUSDT", Side: SideBuy, Type: OrderTypeLimit, Quantity: 2, Price: 50})
+	if err != nil {
+		t.Fatalf("跟踪订单失败: %v", err)
+	}
+	if !result.Filled() || !result.TimedOut || len(result.Orders) != 2 || result.Orders[0].Status != OrderStatusCanceled || result.ExecutedQty != 2 {
+		t.Errorf("超时后应撤单并市价成交: %+v", result)
+	}
+
+	// 跟踪中断后订单保留在状态文件中，重启后继续跟踪到成交
+	tracker, _ = NewOrderTracker(exchange, TrackerConfig{PollInterval: 5 * time.Millisecond, Timeout: time.Hour, StatePath: statePath})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := tracker.Place(ctx, OrderRequest{Symbol: "BTCUSDT", Side: SideBuy, Type: OrderTypeLimit, Quantity: 1, Price: 90}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("期望跟踪被中断，实际: %v", err)
+	}
+
+	now = day(2)
+	restored, err := NewOrderTracker(exchange, TrackerConfig{PollInterval: 5 * time.Millisecond, StatePath: statePath})
+	if err != nil {
+		t.Fatalf("恢复订单跟踪器失败: %v", err)
+	}
+	if open := restored.Open(); len(open) != 1 {
+		t.Fatalf("期望恢复1个未完成订单，实际: %d", len(open))
+	}
+	results, err := restored.Resume(context.Background())
+	if err != nil || len(results) != 1 || !results[0].Filled() || results[0].TimedOut {
+		t.Errorf("恢复后应继续跟踪到成交: %+v %v", results, err)
+	}
+	if open := restored.Open(); len(open) != 0 {
+		t.Errorf("成交后不应再有未完成订单: %+v", open)
+	}
+}
+
+// partialExchange 在模拟交易所上叠加部分成交和固定盘口，用于覆盖超时撤单的各种情况
+type partialExchange struct {
+	*PaperExchange
+	partial map[string]float64 // 撤单时已成交的数量，键为客户端订单号，按限价成交
+	book    *BookTicker        // 不为空时作为最优挂单返回
+}
+
+func (pe *partialExchange) GetBookTicker(ctx context.Context, symbol string) (*BookTicker, error) {
+	if pe.book != nil {
+		return pe.book, nil
+	}
+	return pe.PaperExchange.GetBookTicker(ctx, symbol)
+}
+
+func (pe *partialExchange) GetOrder(ctx context.Context, symbol, orderID string) (*Order, error) {
+	return pe.withPartial(pe.PaperExchange.GetOrder(ctx, symbol, orderID))
+}
+
+func (pe *partialExchange) GetOrderByClientID(ctx context.Context, symbol, clientOrderID string) (*Order, error) {
+	return pe.withPartial(pe.PaperExchange.GetOrderByClientID(ctx, symbol, clientOrderID))
+}
+
+func (pe *partialExchange) CancelOrder(ctx context.Context, symbol, orderID string) (*Order, error) {
+	return pe.withPartial(pe.PaperExchange.CancelOrder(ctx, symbol, orderID))
+}
+
+func (pe *partialExchange) withPartial(order *Order, err error) (*Order, error) {
+	if err != nil || order.Status != OrderStatusCanceled {
+		return order, err
+	}
+	if qty := pe.partial[order.ClientOrderID]; qty > 0 {
+		order.ExecutedQty = qty
+		order.QuoteQty = qty * order.Price
+	}
+	return order.withAvgPrice(), nil
+}
+
+func newPartialExchange(t *testing.T) *partialExchange {
+	t.Helper()
+	paper, err := NewPaperExchange(PaperConfig{
+		Feed:            KlineFeed{"BTCUSDT": {{OpenTime: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Close: 100}}},
+		InitialBalances: map[string]float64{"USDT": 1000},
+		Spread:          1e-9,
+		Clock:           func() time.Time { return time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC) },
+	})
+	if err != nil {
+		t.Fatalf("创建模拟交易所失败: %v", err)
+	}
+	return &partialExchange{PaperExchange: paper, partial: make(map[string]float64)}
+}
+
+func TestOrderTrackerRepriceLimit(t *testing.T) {
+	exchange := newPartialExchange(t)
+	// 盘口低于行情价，重新挂出的限价单同样不会成交
+	exchange.book = &BookTicker{Symbol: "BTCUSDT", BidPrice: 49, AskPrice: 50}
+	tracker, err := NewOrderTracker(exchange, TrackerConfig{PollInterval: 5 * time.Millisecond, Timeout: 20 * time.Millisecond, OnTimeout: TimeoutReprice, MaxReprices: 2})
+	if err != nil {
+		t.Fatalf("创建订单跟踪器失败: %v", err)
+	}
+
+	result, err := tracker.Place(context.Background(), OrderRequest{Symbol: "BTCUSDT", Side: SideBuy, Type: OrderTypeLimit, Quantity: 1, Price: 40, ClientOrderID: "dca"})
+	if err != nil {
+		t.Fatalf("跟踪订单失败: %v", err)
+	}
+	// 原始订单加两次重新挂单后停止，最后一个订单撤销
+	if len(result.Orders) != 3 || !result.TimedOut || result.Status != OrderStatusCanceled || result.ExecutedQty != 0 {
+		t.Fatalf("重新挂单次数用完后应停止: %+v", result)
+	}
+	for i, want := range []string{"dca", "dca-1", "dca-2"} {
+		if order := result.Orders[i]; order.ClientOrderID != want || order.Status != OrderStatusCanceled {
+			t.Errorf("第%d个订单应为已撤销的 %s: %+v", i+1, want, order)
+		}
+	}
+	if result.Orders[1].Price != 50 || result.Orders[2].Price != 50 {
+		t.Errorf("重新挂单应使用最新卖一价: %v %v", result.Orders[1].Price, result.Orders[2].Price)
+	}
+	if open := tracker.Open(); len(open) != 0 {
+		t.Errorf("结束后不应再有未完成订单: %+v", open)
+	}
+}
+
+func TestOrderTrackerCancelPartialFill(t *testing.T) {
+	exchange := newPartialExchange(t)
+	tracker, err := NewOrderTracker(exchange, TrackerConfig{PollInterval: 5 * time.Millisecond, Timeout: 20 * time.Millisecond, OnTimeout: TimeoutCancel})
+	if err != nil {
+		t.Fatalf("创建订单跟踪器失败: %v", err)
+	}
+	exchange.partial["dca"] = 0.5
+
+	result, err := tracker.Place(context.Background(), OrderRequest{Symbol: "BTCUSDT", Side: SideBuy, Type: OrderTypeLimit, Quantity: 2, Price: 50, ClientOrderID: "dca"})
+	if err != nil {
+		t.Fatalf("跟踪订单失败: %v", err)
+	}
+	// 撤单保留已成交部分，不补单
+	if len(result.Orders) != 1 || !result.TimedOut || result.Status != OrderStatusPartiallyFilled {
+		t.Fatalf("超时撤单后应为部分成交: %+v", result)
+	}
+	if result.ExecutedQty != 0.5 || result.QuoteQty != 25 || result.AvgPrice != 50 || result.Filled() {
+		t.Errorf("部分成交的统计不正确: %+v", result)
+	}
+}
+
+func TestOrderTrackerRebuildsChain(t *testing.T) {
+	ctx := context.Background()
+	exchange := newPartialExchange(t)
+	req := OrderRequest{Symbol: "BTCUSDT", Side: SideBuy, Type: OrderTypeLimit, Quantity: 2, Price: 50, ClientOrderID: "dca"}
+
+	// 上次运行在补单后崩溃且没有状态文件：原始订单部分成交后撤销，第一次补单撤销，第二次补单已成交
+	exchange.partial["dca"] = 0.5
+	first, _ := exchange.PlaceOrder(ctx, req)
+	exchange.CancelOrder(ctx, req.Symbol, first.ID)
+	retry := req
+	retry.Quantity, retry.ClientOrderID = 1.5, "dca-1"
+	second, _ := exchange.PlaceOrder(ctx, retry)
+	exchange.CancelOrder(ctx, req.Symbol, second.ID)
+	retry.Price, retry.ClientOrderID = 101, "dca-2"
+	if third, err := exchange.PlaceOrder(ctx, retry); err != nil || !third.Filled() {
+		t.Fatalf("准备已成交的补单失败: %+v %v", third, err)
+	}
+
+	tracker, err := NewOrderTracker(exchange, TrackerConfig{PollInterval: 5 * time.Millisecond, Timeout: 20 * time.Millisecond, OnTimeout: TimeoutReprice})
+	if err != nil {
+		t.Fatalf("创建订单跟踪器失败: %v", err)
+	}
+	result, err := tracker.Place(ctx, req)
+	if err != nil {
+		t.Fatalf("跟踪订单失败: %v", err)
+	}
+	if !result.Duplicate || len(result.Orders) != 3 || !result.Filled() || result.ExecutedQty != 2 {
+		t.Fatalf("应从交易所找回完整的订单链: %+v", result)
+	}
+	for i, want := range []string{"dca", "dca-1", "dca-2"} {
+		if result.Orders[i].ClientOrderID != want {
+			t.Errorf("第%d个订单应为 %s: %+v", i+1, want, result.Orders[i])
+		}
+	}
+	if orders := len(exchange.ledger.Orders); orders != 3 {
+		t.Errorf("找回订单链时不应重复下单，实际共%d个订单", orders)
+	}
+}
@@ -0,0 +1,201 @@
+package pushAPI
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"task_scheduler/pkg/pushAPI/base"
+)
+
+// syncPusher 可在后台重发协程中安全使用的测试推送器
+type syncPusher struct {
+	*capturePusher
+	mu sync.Mutex
+}
+
+func (sp *syncPusher) Push(msg base.Message) error {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	return sp.capturePusher.Push(msg)
+}
+
+func (sp *syncPusher) sent() []base.Message {
+	sp.mu.Lock()
+	defer sp.mu.Unlock()
+	return append([]base.Message(nil), sp.messages...)
+}
+
+func TestEmergencyAckEscalation(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.WorkingDir = filepath.Join(tempDir, "working")
+	cfg.HistoryDir = filepath.Join(tempDir, "history")
+	cfg.Ack = AckConfig{
+		Window:     50 * time.Millisecond,
+		Escalation: []EscalationStep{{Pusher: "oncall", Receivers: []string{"boss"}}},
+	}
+
+	primary := &syncPusher{capturePusher: newCapturePusher("primary")}
+	oncall := &syncPusher{capturePusher: newCapturePusher("oncall")}
+	api := NewPushAPI()
+	if err := api.InitializeWithPusher(cfg, primary); err != nil {
+		t.Fatalf("初始化失败: %v", err)
+	}
+	defer api.(*PushAPIImpl).Stop()
+	if err := api.RegisterCustomPusher(oncall); err != nil {
+		t.Fatalf("注册升级推送器失败: %v", err)
+	}
+
+	options := DefaultPushOptions()
+	options.Retry = 0
+	if err := api.PushNow(*NewMessage("auto-buy", "定投失败", "余额不足", Emergency), options); err != nil {
+		t.Fatalf("推送失败: %v", err)
+	}
+
+	first := primary.sent()
+	if len(first) != 1 {
+		t.Fatalf("期望首次发送1条，实际%d条", len(first))
+	}
+	token, _ := first[0].Metadata["ack_token"].(string)
+	if token == "" {
+		t.Fatalf("紧急消息缺少确认令牌: %+v", first[0].Metadata)
+	}
+
+	// 超过确认窗口后升级到 oncall 推送器
+	deadline := time.Now().Add(2 * time.Second)
+	for len(oncall.sent()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(oncall.sent()) == 0 {
+		t.Fatal("未确认的紧急消息没有升级重发")
+	}
+
+	server := httptest.NewServer(api.AckHandler())
+	defer server.Close()
+	resp, err := http.Get(server.URL + "?token=" + token + "&by=tester")
+	if err != nil {
+		t.Fatalf("确认请求失败: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("确认接口返回 %d", resp.StatusCode)
+	}
+
+	resp, err = http.Get(server.URL + "?token=unknown")
+	if err != nil {
+		t.Fatalf("确认请求失败: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("未知令牌期望返回404，实际 %d", resp.StatusCode)
+	}
+
+	// 确认后不再重发
+	sent := len(oncall.sent()) + len(primary.sent())
+	time.Sleep(200 * time.Millisecond)
+	if after := len(oncall.sent()) + len(primary.sent()); after != sent {
+		t.Errorf("确认后仍在重发: %d -> %d", sent, after)
+	}
+
+	// 普通消息不附加确认令牌
+	api.PushNow(*NewNormalMessage("auto-buy", "定投成功", "已买入"), options)
+	all := primary.sent()
+	if _, exists := all[len(all)-1].Metadata["ack_token"]; exists {
+		t.Error("普通消息不应附加确认令牌")
+	}
+}
+
+func TestAcknowledgeRunningInstance(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("分配端口失败: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	tempDir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.WorkingDir = filepath.Join(tempDir, "working")
+	cfg.HistoryDir = filepath.Join(tempDir, "history")
+	cfg.Store = StoreConfig{Type: StoreBolt}
+	cfg.Ack = AckConfig{Window: time.Hour, ListenAddr: addr}
+
+	pusher := &syncPusher{capturePusher: newCapturePusher("primary")}
+	api := NewPushAPI()
+	if err := api.InitializeWithPusher(cfg, pusher); err != nil {
+		t.Fatalf("初始化失败: %v", err)
+	}
+	options := DefaultPushOptions()
+	options.Retry = 0
+	if err := api.PushNow(*NewMessage("auto-buy", "定投失败", "余额不足", Emergency), options); err != nil {
+		t.Fatalf("推送失败: %v", err)
+	}
+	token, _ := pusher.sent()[0].Metadata["ack_token"].(string)
+
+	// 确认接口在初始化时已开始监听，端口被占用时初始化失败
+	other := DefaultConfig()
+	other.WorkingDir = filepath.Join(tempDir, "other", "working")
+	other.HistoryDir = filepath.Join(tempDir, "other", "history")
+	other.Ack = AckConfig{Window: time.Hour, ListenAddr: addr}
+	if err := NewPushAPI().InitializeWithPusher(other, newCapturePusher("other")); err == nil {
+		t.Error("确认接口端口被占用时初始化应失败")
+	}
+
+	// 运行中的实例占用bolt存储，确认需经过其确认接口
+	if err := Acknowledge(cfg, token, "cli"); err != nil {
+		t.Fatalf("实例运行中确认失败: %v", err)
+	}
+	if err := Acknowledge(cfg, "unknown", "cli"); !errors.Is(err, ErrAckNotFound) {
+		t.Errorf("未知令牌期望 ErrAckNotFound，实际: %v", err)
+	}
+
+	// 没有运行中的实例时直接修改存储
+	api.(*PushAPIImpl).Stop()
+	if err := Acknowledge(cfg, token, "cli"); err != nil {
+		t.Errorf("实例停止后确认失败: %v", err)
+	}
+	if err := Acknowledge(cfg, "unknown", "cli"); !errors.Is(err, ErrAckNotFound) {
+		t.Errorf("实例停止后未知令牌期望 ErrAckNotFound，实际: %v", err)
+	}
+}
+
+func TestScheduledEmergencyRequiresAck(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.WorkingDir = filepath.Join(tempDir, "working")
+	cfg.HistoryDir = filepath.Join(tempDir, "history")
+	cfg.Ack = AckConfig{Window: time.Hour}
+
+	pusher := &syncPusher{capturePusher: newCapturePusher("primary")}
+	api := NewPushAPI()
+	if err := api.InitializeWithPusher(cfg, pusher); err != nil {
+		t.Fatalf("初始化失败: %v", err)
+	}
+	defer api.(*PushAPIImpl).Stop()
+
+	options := DefaultPushOptions()
+	options.Retry = 0
+	message := NewMessage("auto-buy", "定投失败", "余额不足", Emergency)
+	if err := api.PushAt(*message, options, time.Now().Add(50*time.Millisecond)); err != nil {
+		t.Fatalf("定时推送失败: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(pusher.sent()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	sent := pusher.sent()
+	if len(sent) != 1 {
+		t.Fatalf("期望定时消息发送1条，实际%d条", len(sent))
+	}
+	// 到期的定时消息与立即推送走同一发送路径，紧急消息同样需要确认
+	if token, _ := sent[0].Metadata["ack_token"].(string); token == "" {
+		t.Errorf("定时紧急消息缺少确认令牌: %+v", sent[0].Metadata)
+	}
+}
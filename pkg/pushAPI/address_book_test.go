@@ -0,0 +1,84 @@
+package pushAPI
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAddressBookReceivers(t *testing.T) {
+	server, requests := newBotServer(t, `{"ok":true}`)
+
+	tempDir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.WorkingDir = filepath.Join(tempDir, "working")
+	cfg.HistoryDir = filepath.Join(tempDir, "history")
+	cfg.TelegramConfig = TelegramConfig{BotToken: "TOKEN", ChatIDs: []string{"default"}, BaseURL: server.URL}
+	cfg.AddressBook = AddressBookConfig{
+		Contacts: map[string]Contact{
+			"alice": {TelegramChatID: "1001"},
+			"bob":   {TelegramChatID: "1002", Email: "bob@example.com"},
+			"carol": {Email: "carol@example.com"},
+		},
+		Groups: map[string][]string{
+			"ops":     {"alice", "bob"},
+			"finance": {"bob", "carol"},
+			"all":     {"ops", "finance"},
+		},
+	}
+
+	api := NewPushAPI()
+	if err := api.Initialize(cfg, Telegram); err != nil {
+		t.Fatalf("初始化失败: %v", err)
+	}
+	defer api.(*PushAPIImpl).Stop()
+
+	testCases := []struct {
+		name      string
+		receivers []string
+		want      []string
+		wantErr   bool
+	}{
+		{name: "分组", receivers: []string{"ops"}, want: []string{"1001", "1002"}},
+		{name: "嵌套分组去重", receivers: []string{"all", "bob"}, want: []string{"1001", "1002"}},
+		{name: "跳过无该渠道地址的联系人", receivers: []string{"finance"}, want: []string{"1002"}},
+		{name: "无该渠道地址", receivers: []string{"carol"}, wantErr: true},
+		{name: "未知接收者", receivers: []string{"ops", "nobody"}, wantErr: true},
+	}
+	for _, tc := range testCases {
+		*requests = nil
+		options := DefaultPushOptions()
+		options.Retry = 0
+		options.Receivers = tc.receivers
+
+		err := api.PushNow(*NewNormalMessage("auto-buy", tc.name, "内容"), options)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("[%s] 期望返回错误", tc.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("[%s] 推送失败: %v", tc.name, err)
+		}
+
+		var chatIDs []string
+		for _, req := range *requests {
+			chatIDs = append(chatIDs, req.body["chat_id"].(string))
+		}
+		if !reflect.DeepEqual(chatIDs, tc.want) {
+			t.Errorf("[%s] 期望发送到 %v，实际 %v", tc.name, tc.want, chatIDs)
+		}
+	}
+
+	// 未知接收者在入队和定时推送时就被拒绝
+	options := DefaultPushOptions()
+	options.Receivers = []string{"nobody"}
+	if err := api.Enqueue(*NewNormalMessage("auto-buy", "延迟", "内容"), options); err == nil {
+		t.Error("Enqueue 未拒绝未知接收者")
+	}
+	if err := api.PushAt(*NewNormalMessage("auto-buy", "定时", "内容"), options, time.Now().Add(time.Hour)); err == nil {
+		t.Error("PushAt 未拒绝未知接收者")
+	}
+}
@@ -3,6 +3,7 @@ package pushAPI
 import (
 	"fmt"
 	"log"
+	"net/http"
 	"task_scheduler/pkg/pushAPI/base"
 	"task_scheduler/pkg/pushAPI/core"
 	"task_scheduler/pkg/pushAPI/push_method"
@@ -22,12 +23,7 @@ func NewPushAPI() PushAPI {
 // Initialize 初始化（选择内置推送方式）
 func (api *PushAPIImpl) Initialize(cfg Config, method PushMethod) error {
 	// 转换配置
-	coreConfig := base.PushConfig{
-		QueueSize:     cfg.QueueSize,
-		FlushInterval: cfg.FlushInterval,
-		WorkingDir:    cfg.WorkingDir,
-		HistoryDir:    cfg.HistoryDir,
-	}
+	coreConfig := cfg.ToCore()
 	coreMethod := method.ToCore()
 
 	controller := core.NewPushController(coreConfig)
@@ -44,12 +40,7 @@ func (api *PushAPIImpl) Initialize(cfg Config, method PushMethod) error {
 // InitializeWithPusher 高级初始化（自定义推送器）
 func (api *PushAPIImpl) InitializeWithPusher(cfg Config, pusher Pusher) error {
 	// 转换配置
-	coreConfig := base.PushConfig{
-		QueueSize:     cfg.QueueSize,
-		FlushInterval: cfg.FlushInterval,
-		WorkingDir:    cfg.WorkingDir,
-		HistoryDir:    cfg.HistoryDir,
-	}
+	coreConfig := cfg.ToCore()
 
 	controller := core.NewPushController(coreConfig)
 
@@ -166,12 +157,186 @@ func (api *PushAPIImpl) PushAt(message Message, options PushOptions, scheduledAt
 	return api.controller.PushAt(coreMessage, coreOptions, scheduledAt)
 }
 
+// ListScheduled 按条件列出待发送的定时消息，按计划发送时间升序
+func (api *PushAPIImpl) ListScheduled(filter ScheduledFilter) ([]*ScheduledMessage, error) {
+	if api.controller == nil {
+		return nil, fmt.Errorf("推送API未初始化")
+	}
+
+	coreMessages, err := api.controller.ListScheduled(filter.ToCore())
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]*ScheduledMessage, 0, len(coreMessages))
+	for _, msg := range coreMessages {
+		messages = append(messages, fromCoreScheduledMessage(msg))
+	}
+	return messages, nil
+}
+
+// CancelScheduled 取消尚未发送的定时消息
+func (api *PushAPIImpl) CancelScheduled(messageID string) error {
+	if api.controller == nil {
+		return fmt.Errorf("推送API未初始化")
+	}
+
+	return api.controller.CancelScheduled(messageID)
+}
+
+// Reschedule 修改尚未发送的定时消息的计划发送时间
+func (api *PushAPIImpl) Reschedule(messageID string, scheduledAt time.Time) error {
+	if api.controller == nil {
+		return fmt.Errorf("推送API未初始化")
+	}
+
+	return api.controller.Reschedule(messageID, scheduledAt)
+}
+
+// PushEvery 按cron表达式周期推送消息，直到调用 CancelRecurring(message.ID)
+func (api *PushAPIImpl) PushEvery(message Message, options PushOptions, cronExpr string) error {
+	return api.PushRecurring(message, options, RecurringRule{Cron: cronExpr})
+}
+
+// PushRecurring 按周期规则推送消息，周期推送ID为 message.ID
+func (api *PushAPIImpl) PushRecurring(message Message, options PushOptions, rule RecurringRule) error {
+	if api.controller == nil {
+		return fmt.Errorf("推送API未初始化")
+	}
+
+	// 如果消息ID为空，自动生成
+	if message.ID == "" {
+		coreMsg := base.NewMessage(message.AppID, message.Title, message.Content, message.Level.ToCore())
+		message.ID = coreMsg.ID
+	}
+
+	return api.controller.PushRecurring(message.ToCore(), options.ToCore(), rule.ToCore())
+}
+
+// PushTemplateRecurring 按周期规则推送模板消息，每次发送前重新渲染，返回周期推送ID
+// data 会被序列化保存；需要每次使用最新数据时，用 RegisterTemplateData 注册数据提供函数
+func (api *PushAPIImpl) PushTemplateRecurring(appID, templateName string, data interface{}, options PushOptions, rule RecurringRule) (string, error) {
+	if api.controller == nil {
+		return "", fmt.Errorf("推送API未初始化")
+	}
+
+	return api.controller.PushTemplateRecurring(appID, templateName, data, options.ToCore(), rule.ToCore())
+}
+
+// RegisterTemplateData 为模板注册数据提供函数，周期推送每次渲染前调用；重启后需重新注册
+func (api *PushAPIImpl) RegisterTemplateData(templateName string, provider func() (interface{}, error)) {
+	if api.controller == nil {
+		return
+	}
+
+	api.controller.RegisterTemplateData(templateName, provider)
+}
+
+// ListRecurring 列出全部周期推送，按下一次发送时间升序
+func (api *PushAPIImpl) ListRecurring() ([]*RecurringMessage, error) {
+	if api.controller == nil {
+		return nil, fmt.Errorf("推送API未初始化")
+	}
+
+	coreMessages, err := api.controller.ListRecurring()
+	if err != nil {
+		return nil, err
+	}
+	messages := make([]*RecurringMessage, 0, len(coreMessages))
+	for _, msg := range coreMessages {
+		messages = append(messages, fromCoreRecurringMessage(msg))
+	}
+	return messages, nil
+}
+
+// CancelRecurring 取消周期推送
+func (api *PushAPIImpl) CancelRecurring(id string) error {
+	if api.controller == nil {
+		return fmt.Errorf("推送API未初始化")
+	}
+
+	return api.controller.CancelRecurring(id)
+}
+
+// PushTemplate 使用模板渲染消息后立即推送，blocks 附加在渲染结果之后
+func (api *PushAPIImpl) PushTemplate(appID, templateName string, data interface{}, options PushOptions, blocks ...ContentBlock) error {
+	if api.controller == nil {
+		return fmt.Errorf("推送API未初始化")
+	}
+
+	return api.controller.PushTemplate(appID, templateName, data, options.ToCore(), blocks...)
+}
+
+// QueryHistory 查询推送历史记录
+func (api *PushAPIImpl) QueryHistory(query HistoryQuery) (*HistoryResult, error) {
+	if api.controller == nil {
+		return nil, fmt.Errorf("推送API未初始化")
+	}
+
+	result, err := api.controller.QueryHistory(query.ToCore())
+	if err != nil {
+		return nil, err
+	}
+	return fromCoreHistoryResult(result), nil
+}
+
+// QueryHistory 查询历史记录，无需初始化推送API
+// 配置了 Ack.ListenAddr 时优先请求运行中实例的 /history，没有运行中的实例时直接读取消息存储
+// （bolt存储同一时间只能被一个进程打开）
+func QueryHistory(cfg Config, query HistoryQuery) (*HistoryResult, error) {
+	result, err := core.QueryHistory(cfg.ToCore(), query.ToCore())
+	if err != nil {
+		return nil, err
+	}
+	return fromCoreHistoryResult(result), nil
+}
+
+// Ack 确认紧急消息
+func (api *PushAPIImpl) Ack(token, by string) error {
+	if api.controller == nil {
+		return fmt.Errorf("推送API未初始化")
+	}
+	return api.controller.Ack(token, by)
+}
+
+// AckHandler 返回紧急消息确认接口（GET/POST ?token=xxx[&by=name]），可挂载到已有的HTTP服务
+func (api *PushAPIImpl) AckHandler() http.Handler {
+	if api.controller == nil {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "推送API未初始化", http.StatusServiceUnavailable)
+		})
+	}
+	return api.controller.AckHandler()
+}
+
+// Acknowledge 确认紧急消息，无需初始化推送API
+// 配置了 Ack.ListenAddr 时优先请求运行中实例的确认接口，没有运行中的实例时直接修改消息存储
+func Acknowledge(cfg Config, token, by string) error {
+	return core.Acknowledge(cfg.ToCore(), token, by)
+}
+
+// RegisterPusher 额外注册内置推送器，注册后可在 AckConfig.Escalation 中按名称引用
+func (api *PushAPIImpl) RegisterPusher(method PushMethod) error {
+	if api.controller == nil {
+		return fmt.Errorf("推送API未初始化")
+	}
+	return api.controller.RegisterPusher(method.ToCore())
+}
+
+// RegisterCustomPusher 额外注册自定义推送器
+func (api *PushAPIImpl) RegisterCustomPusher(pusher Pusher) error {
+	if api.controller == nil {
+		return fmt.Errorf("推送API未初始化")
+	}
+	return api.controller.RegisterCustomPusher(&corePusherAdapter{pusher: pusher})
+}
+
 // Stop 停止推送API
 func (api *PushAPIImpl) Stop() {
 	if api.controller != nil {
 		api.controller.Stop()
 	}
-} 
+}
+
 // GetRegisteredPushers 获取已注册的推送器列表
 func (api *PushAPIImpl) GetRegisteredPushers() []string {
 	if api.controller == nil {
@@ -200,3 +365,19 @@ func (cpa *corePusherAdapter) Validate(options base.PushOptions) error {
 func (cpa *corePusherAdapter) HealthCheck() bool {
 	return cpa.pusher.HealthCheck()
 }
+
+// Targets 外部推送器支持多目标推送时返回其推送目标，否则视为单个目标
+func (cpa *corePusherAdapter) Targets(msg base.Message) ([]string, error) {
+	if multi, ok := cpa.pusher.(push_method.MultiTargetPusher); ok {
+		return multi.Targets(msg)
+	}
+	return []string{""}, nil
+}
+
+// PushTo 推送到单个目标，外部推送器不支持多目标推送时整体推送
+func (cpa *corePusherAdapter) PushTo(msg base.Message, target string) error {
+	if multi, ok := cpa.pusher.(push_method.MultiTargetPusher); ok {
+		return multi.PushTo(msg, target)
+	}
+	return cpa.pusher.Push(msg)
+}
@@ -0,0 +1,124 @@
+package base
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// AddressBookConfig 通讯录配置，将逻辑接收者（联系人或分组）映射到各渠道的地址
+// 未配置联系人时不校验接收者，推送器使用各自配置中的默认地址
+type AddressBookConfig struct {
+	Contacts map[string]Contact  `json:"contacts"` // 联系人，键为接收者名称
+	Groups   map[string][]string `json:"groups"`   // 分组，成员为联系人或其他分组的名称
+}
+
+// Contact 联系人在各渠道的地址，为空表示该渠道不可达
+type Contact struct {
+	Email          string `json:"email"`            // 邮箱地址
+	TelegramChatID string `json:"telegram_chat_id"` // Telegram会话ID
+	ServerChanKey  string `json:"serverchan_key"`   // 方糖sendKey（微信推送）
+	Phone          string `json:"phone"`            // 手机号（短信推送）
+}
+
+// Address 获取联系人在指定渠道（推送器名称）的地址
+func (c Contact) Address(channel string) string {
+	switch channel {
+	case "email":
+		return c.Email
+	case "telegram":
+		return c.TelegramChatID
+	case "wechat":
+		return c.ServerChanKey
+	case "sms":
+		return c.Phone
+	default:
+		return ""
+	}
+}
+
+// Enabled 是否配置了联系人
+func (ab AddressBookConfig) Enabled() bool {
+	return len(ab.Contacts) > 0
+}
+
+// Check 校验接收者都是已知的联系人或分组，分组成员也会被校验
+func (ab AddressBookConfig) Check(receivers []string) error {
+	if !ab.Enabled() {
+		return nil
+	}
+	_, err := ab.Resolve(receivers)
+	return err
+}
+
+// Resolve 将接收者展开为联系人名称（去重，保持顺序），分组可以嵌套
+func (ab AddressBookConfig) Resolve(receivers []string) ([]string, error) {
+	var names []string
+	seen := make(map[string]bool)
+	var unknown []string
+
+	var expand func(name string, path map[string]bool)
+	expand = func(name string, path map[string]bool) {
+		if _, exists := ab.Contacts[name]; exists {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+			return
+		}
+		members, exists := ab.Groups[name]
+		if !exists {
+			unknown = append(unknown, name)
+			return
+		}
+		// 分组循环引用时跳过，避免无限展开
+		if path[name] {
+			return
+		}
+		path[name] = true
+		for _, member := range members {
+			expand(member, path)
+		}
+		delete(path, name)
+	}
+
+	for _, receiver := range receivers {
+		expand(receiver, make(map[string]bool))
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("未知的接收者: %s", strings.Join(unknown, ", "))
+	}
+	return names, nil
+}
+
+// Addresses 将接收者解析为指定渠道的地址（去重，保持顺序）
+// 未配置联系人或接收者为空时返回 nil，表示使用推送器的默认地址；
+// 联系人都没有该渠道地址时返回错误
+func (ab AddressBookConfig) Addresses(receivers []string, channel string) ([]string, error) {
+	if !ab.Enabled() || len(receivers) == 0 {
+		return nil, nil
+	}
+
+	names, err := ab.Resolve(receivers)
+	if err != nil {
+		return nil, err
+	}
+
+	var addresses []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		address := ab.Contacts[name].Address(channel)
+		if address == "" || seen[address] {
+			continue
+		}
+		seen[address] = true
+		addresses = append(addresses, address)
+	}
+
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("接收者 %s 均未配置%s地址", strings.Join(receivers, ", "), channel)
+	}
+	return addresses, nil
+}
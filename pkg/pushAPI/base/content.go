@@ -0,0 +1,111 @@
+package base
+
+import (
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+)
+
+// BlockType 内容块类型
+type BlockType string
+
+const (
+	BlockMarkdown BlockType = "markdown" // Markdown文本
+	BlockTable    BlockType = "table"    // 键值表格
+	BlockLink     BlockType = "link"     // 链接
+	BlockImage    BlockType = "image"    // 图片（网络地址、文件路径或内容）
+	BlockFile     BlockType = "file"     // 附件（文件路径或内容）
+)
+
+// ContentBlock 富内容块，附加在消息正文之后
+// 各推送器尽量按平台能力渲染：邮件发送真实附件，机器人渲染表格和链接，
+// 不支持的类型退化为文本（如附件只显示文件名）
+type ContentBlock struct {
+	Type     BlockType  `json:"type"`
+	Text     string     `json:"text,omitempty"`      // Markdown正文，或表格标题、链接/图片的显示文字
+	Rows     []KeyValue `json:"rows,omitempty"`      // 表格行
+	URL      string     `json:"url,omitempty"`       // 链接地址或网络图片地址
+	Path     string     `json:"path,omitempty"`      // 附件或图片的文件路径（发送时读取）
+	Data     []byte     `json:"data,omitempty"`      // 附件或图片的内容，与 Path 二选一
+	Name     string     `json:"name,omitempty"`      // 附件文件名，默认取 Path 的文件名
+	MIMEType string     `json:"mime_type,omitempty"` // 附件类型，默认按文件扩展名推断
+}
+
+// KeyValue 表格中的一行
+type KeyValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// MarkdownBlock 创建Markdown内容块
+func MarkdownBlock(text string) ContentBlock {
+	return ContentBlock{Type: BlockMarkdown, Text: text}
+}
+
+// TableBlock 创建键值表格，title 可为空
+func TableBlock(title string, rows ...KeyValue) ContentBlock {
+	return ContentBlock{Type: BlockTable, Text: title, Rows: rows}
+}
+
+// LinkBlock 创建链接
+func LinkBlock(text, url string) ContentBlock {
+	return ContentBlock{Type: BlockLink, Text: text, URL: url}
+}
+
+// ImageBlock 创建网络图片
+func ImageBlock(text, url string) ContentBlock {
+	return ContentBlock{Type: BlockImage, Text: text, URL: url}
+}
+
+// FileBlock 创建文件附件，发送时读取文件内容
+func FileBlock(path string) ContentBlock {
+	return ContentBlock{Type: BlockFile, Path: path}
+}
+
+// FileDataBlock 创建内存中的附件
+func FileDataBlock(name string, data []byte) ContentBlock {
+	return ContentBlock{Type: BlockFile, Name: name, Data: data}
+}
+
+// HasPayload 是否携带文件内容（附件，或来自文件/内存的图片）
+func (b ContentBlock) HasPayload() bool {
+	return b.Type == BlockFile || (b.Type == BlockImage && b.URL == "" && (b.Path != "" || len(b.Data) > 0))
+}
+
+// FileName 附件文件名
+func (b ContentBlock) FileName() string {
+	if b.Name != "" {
+		return b.Name
+	}
+	if b.Path != "" {
+		return filepath.Base(b.Path)
+	}
+	if b.Text != "" {
+		return b.Text
+	}
+	return "attachment"
+}
+
+// ContentType 附件的MIME类型
+func (b ContentBlock) ContentType() string {
+	if b.MIMEType != "" {
+		return b.MIMEType
+	}
+	if contentType := mime.TypeByExtension(filepath.Ext(b.FileName())); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+// Load 读取附件内容，优先使用 Data
+func (b ContentBlock) Load() ([]byte, error) {
+	if len(b.Data) > 0 || b.Path == "" {
+		return b.Data, nil
+	}
+	data, err := os.ReadFile(b.Path)
+	if err != nil {
+		return nil, fmt.Errorf("读取附件失败 %s: %w", b.Path, err)
+	}
+	return data, nil
+}
@@ -1,6 +1,7 @@
 package base
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"sync"
@@ -11,10 +12,15 @@ import (
 type PushMethod int
 
 const (
-	WeChat PushMethod = iota // 微信推送
-	Email                    // 邮件推送
-	SMS                      // 短信推送
-	Logger                   // 日志推送
+	WeChat   PushMethod = iota // 微信推送
+	Email                      // 邮件推送
+	SMS                        // 短信推送
+	Logger                     // 日志推送
+	Webhook                    // 通用Webhook推送
+	Telegram                   // Telegram机器人推送
+	Slack                      // Slack Incoming Webhook推送
+	DingTalk                   // 钉钉机器人推送
+	Feishu                     // 飞书/Lark机器人推送
 )
 
 // String 返回推送方式的字符串表示
@@ -28,6 +34,16 @@ func (pm PushMethod) String() string {
 		return "sms"
 	case Logger:
 		return "logger"
+	case Webhook:
+		return "webhook"
+	case Telegram:
+		return "telegram"
+	case Slack:
+		return "slack"
+	case DingTalk:
+		return "dingtalk"
+	case Feishu:
+		return "feishu"
 	default:
 		return "unknown"
 	}
@@ -109,16 +125,18 @@ func ParseSendStatus(status string) SendStatus {
 
 // Message 消息体定义
 type Message struct {
-	ID         string                 `json:"id"`          // 消息唯一标识，自动生成格式：{app_id}_YYMMDD_{gen_id}
-	AppID      string                 `json:"app_id"`      // 发送方ID，标志消息来源
-	Title      string                 `json:"title"`       // 消息标题
-	Content    string                 `json:"content"`     // 消息内容
-	Level      MessageLevel           `json:"level"`       // 紧急程度
-	Metadata   map[string]interface{} `json:"metadata"`    // 扩展元数据
-	CreatedAt  time.Time              `json:"created_at"`  // 创建时间
-	SentAt     time.Time              `json:"sent_at"`     // 最终成功发送时间
-	SendStatus SendStatus             `json:"send_status"` // 发送状态
-	mu         sync.RWMutex           `json:"-"`           // 用于metadata操作的互斥锁
+	ID         string                 `json:"id"`                  // 消息唯一标识，自动生成格式：{app_id}_YYMMDD_{gen_id}
+	AppID      string                 `json:"app_id"`              // 发送方ID，标志消息来源
+	Title      string                 `json:"title"`               // 消息标题
+	Content    string                 `json:"content"`             // 消息内容
+	Blocks     []ContentBlock         `json:"blocks,omitempty"`    // 富内容块（表格、链接、图片、附件），附加在内容之后
+	Level      MessageLevel           `json:"level"`               // 紧急程度
+	Metadata   map[string]interface{} `json:"metadata"`            // 扩展元数据
+	CreatedAt  time.Time              `json:"created_at"`          // 创建时间
+	SentAt     time.Time              `json:"sent_at"`             // 最终成功发送时间
+	SendStatus SendStatus             `json:"send_status"`         // 发送状态
+	Receivers  []string               `json:"receivers,omitempty"` // 接收者（发送时由推送选项填入，推送器通过通讯录解析）
+	mu         sync.RWMutex           `json:"-"`                   // 用于metadata操作的互斥锁
 }
 
 // NewMessage 创建新消息
@@ -161,6 +179,11 @@ func (m *Message) GetMetadata(key string) (interface{}, bool) {
 	return value, exists
 }
 
+// AddBlocks 追加富内容块
+func (m *Message) AddBlocks(blocks ...ContentBlock) {
+	m.Blocks = append(m.Blocks, blocks...)
+}
+
 // SetSentAt 设置发送时间
 func (m *Message) SetSentAt(sentAt time.Time) {
 	m.SentAt = sentAt
@@ -183,17 +206,30 @@ func generateMessageID(appID string) string {
 // PushOptions 推送选项
 type PushOptions struct {
 	Receivers []string `json:"receivers"` // 接收者列表
-	Priority  int      `json:"priority"`  // 优先级
+	Priority  int      `json:"priority"`  // 优先级（0-10，越大越先发送）
 	Retry     int      `json:"retry"`     // 重试次数
 }
 
 // PushConfig 推送配置
 type PushConfig struct {
-	QueueSize     int           `json:"queue_size"`     // 队列大小
-	FlushInterval time.Duration `json:"flush_interval"` // 刷新间隔
-	WorkingDir    string        `json:"working_dir"`    // 工作目录（存放延迟和定时消息）
-	HistoryDir    string        `json:"history_dir"`    // 历史消息记录目录
-	WeChatConfig  WeChatConfig  `json:"wechat_config"`  // 微信推送配置
+	QueueSize      int               `json:"queue_size"`      // 发送队列容量，0 表示不限制
+	FlushInterval  time.Duration     `json:"flush_interval"`  // 发送队列刷新间隔，0 表示入队后立即发送
+	Queue          QueueConfig       `json:"queue"`           // 发送队列配置
+	WorkingDir     string            `json:"working_dir"`     // 工作目录（存放延迟和定时消息）
+	HistoryDir     string            `json:"history_dir"`     // 历史消息记录目录
+	TemplateDir    string            `json:"template_dir"`    // 消息模板目录
+	WeChatConfig   WeChatConfig      `json:"wechat_config"`   // 微信推送配置
+	EmailConfig    EmailConfig       `json:"email_config"`    // SMTP邮件推送配置
+	WebhookConfig  WebhookConfig     `json:"webhook_config"`  // Webhook推送配置
+	TelegramConfig TelegramConfig    `json:"telegram_config"` // Telegram推送配置
+	SlackConfig    SlackConfig       `json:"slack_config"`    // Slack推送配置
+	DingTalkConfig DingTalkConfig    `json:"dingtalk_config"` // 钉钉推送配置
+	FeishuConfig   FeishuConfig      `json:"feishu_config"`   // 飞书推送配置
+	RateLimit      RateLimitConfig   `json:"rate_limit"`      // 限流与去重配置
+	Digest         DigestConfig      `json:"digest"`          // 延迟消息摘要发送配置
+	Store          StoreConfig       `json:"store"`           // 消息存储配置
+	Ack            AckConfig         `json:"ack"`             // 紧急消息确认与升级配置
+	AddressBook    AddressBookConfig `json:"address_book"`    // 通讯录（接收者与分组）
 }
 
 // WeChatConfig 微信推送配置
@@ -201,6 +237,132 @@ type WeChatConfig struct {
 	SendKey string `json:"send_key"` // 方糖气球sendKey
 }
 
+// EmailConfig SMTP邮件推送配置，未配置 Host 时邮件推送器只记录日志
+type EmailConfig struct {
+	Host     string        `json:"host"`     // SMTP服务器地址
+	Port     int           `json:"port"`     // 端口，默认587（STARTTLS），465使用SSL直连
+	Username string        `json:"username"` // 登录用户名，为空则不认证
+	Password string        `json:"password"` // 登录密码或授权码
+	From     string        `json:"from"`     // 发件人地址，默认使用 Username
+	To       []string      `json:"to"`       // 默认收件人（未通过通讯录指定接收者时使用）
+	Timeout  time.Duration `json:"timeout"`  // 连接与发送超时时间，默认10秒
+}
+
+// WebhookConfig 通用Webhook推送配置
+type WebhookConfig struct {
+	URLs            []string          `json:"urls"`             // 推送地址列表
+	Headers         map[string]string `json:"headers"`          // 自定义请求头
+	Secret          string            `json:"secret"`           // HMAC-SHA256签名密钥，对 X-Webhook-Timestamp + "." + 请求体签名，为空则不签名
+	SignatureHeader string            `json:"signature_header"` // 签名请求头名称，默认 X-Signature-256
+	BodyTemplate    string            `json:"body_template"`    // 请求体模板（text/template），为空则发送JSON
+	ContentType     string            `json:"content_type"`     // 请求体类型，默认 application/json
+	Timeout         time.Duration     `json:"timeout"`          // 请求超时时间
+}
+
+// RateLimitConfig 限流与去重配置
+// 超出配额的普通消息转入延迟合并发送，紧急消息不受限制
+type RateLimitConfig struct {
+	PusherLimit int           `json:"pusher_limit"` // 每个推送器在 Interval 内允许发送的消息数，0 表示不限制
+	AppLimit    int           `json:"app_limit"`    // 每个AppID在 Interval 内允许发送的消息数，0 表示不限制
+	Interval    time.Duration `json:"interval"`     // 限流周期，默认1分钟
+	DedupWindow time.Duration `json:"dedup_window"` // 去重窗口，窗口内相同消息只发送一次，0 表示不去重
+}
+
+const (
+	// StoreFile JSON文件存储（默认）
+	StoreFile = "file"
+	// StoreBolt bbolt嵌入式数据库存储
+	StoreBolt = "bolt"
+)
+
+// StoreConfig 消息存储配置（延迟消息、定时消息、历史记录）
+type StoreConfig struct {
+	Type string `json:"type"` // 存储类型：file（默认）或 bolt；bolt 同一时间只能被一个进程打开
+	Path string `json:"path"` // bolt数据库文件路径，默认 {working_dir}/messages.db
+}
+
+const (
+	// QueueFullBlock 队列已满时等待空位，超时返回错误（默认）
+	QueueFullBlock = "block"
+	// QueueFullReject 队列已满时直接返回错误
+	QueueFullReject = "reject"
+	// QueueFullDropLowest 队列已满时挤出优先级最低的消息，新消息优先级不高于它时返回错误
+	QueueFullDropLowest = "drop_lowest"
+)
+
+// QueueConfig 发送队列配置
+// Enqueue 的消息先进入发送队列，每隔 FlushInterval 按优先级从高到低交给 Workers 个发送协程
+type QueueConfig struct {
+	Workers        int           `json:"workers"`         // 发送协程数，默认1
+	FullPolicy     string        `json:"full_policy"`     // 队列已满时的处理方式：block（默认）、reject 或 drop_lowest
+	EnqueueTimeout time.Duration `json:"enqueue_timeout"` // block 方式的最长等待时间，默认5秒
+}
+
+// AckConfig 紧急消息确认与升级配置
+// Window 大于0时启用：紧急消息附带确认令牌，超过 Window 未确认则重发，
+// 第N次重发使用 Escalation 的第N步（超出后一直使用最后一步），直到确认或达到 MaxAttempts
+type AckConfig struct {
+	Window      time.Duration    `json:"window"`       // 确认等待时间，0 表示不启用
+	MaxAttempts int              `json:"max_attempts"` // 最多重发次数，0 表示直到确认为止
+	Escalation  []EscalationStep `json:"escalation"`   // 升级步骤
+	ListenAddr  string           `json:"listen_addr"`  // 确认接口（/ack、本机 /history）监听地址（如 ":8089"），为空则不启动
+	PublicURL   string           `json:"public_url"`   // 消息中展示的确认地址（如 "https://example.com/ack"），为空则提示使用命令行确认
+}
+
+// EscalationStep 升级步骤
+type EscalationStep struct {
+	Pusher    string   `json:"pusher"`    // 推送器名称（需已注册），为空则使用当前推送器
+	Receivers []string `json:"receivers"` // 接收者，为空则沿用原消息的接收者
+}
+
+// DigestConfig 延迟消息摘要发送配置
+// Times 非空时按每天固定时刻发送，否则按 Cron 表达式发送，两者都为空时每4小时整点发送
+type DigestConfig struct {
+	Cron       string           `json:"cron"`        // cron表达式，秒字段可选，如 "0 9,18 * * *"
+	Times      []string         `json:"times"`       // 每天发送时刻列表，格式 HH:MM，如 ["09:00", "18:00"]
+	QuietHours QuietHoursConfig `json:"quiet_hours"` // 免打扰时段
+}
+
+// QuietHoursConfig 免打扰时段配置，格式 HH:MM，支持跨零点（如 22:00-07:00）
+// 免打扰时段内普通消息留到下一次摘要发送，紧急消息仍立即发送
+type QuietHoursConfig struct {
+	Start string `json:"start"` // 开始时刻
+	End   string `json:"end"`   // 结束时刻
+}
+
+// TelegramConfig Telegram机器人推送配置
+type TelegramConfig struct {
+	BotToken string        `json:"bot_token"` // 机器人Token
+	ChatIDs  []string      `json:"chat_ids"`  // 接收消息的会话ID列表
+	BaseURL  string        `json:"base_url"`  // Bot API地址，默认 https://api.telegram.org
+	Timeout  time.Duration `json:"timeout"`   // 请求超时时间
+}
+
+// SlackConfig Slack Incoming Webhook推送配置
+type SlackConfig struct {
+	WebhookURL string        `json:"webhook_url"` // Incoming Webhook地址
+	Channel    string        `json:"channel"`     // 覆盖默认频道（可选）
+	Username   string        `json:"username"`    // 覆盖默认显示名（可选）
+	Timeout    time.Duration `json:"timeout"`     // 请求超时时间
+}
+
+// DingTalkConfig 钉钉自定义机器人推送配置
+type DingTalkConfig struct {
+	AccessToken string        `json:"access_token"` // 机器人access_token
+	Secret      string        `json:"secret"`       // 加签密钥（可选）
+	AtMobiles   []string      `json:"at_mobiles"`   // 需要@的手机号
+	BaseURL     string        `json:"base_url"`     // 开放平台地址，默认 https://oapi.dingtalk.com
+	Timeout     time.Duration `json:"timeout"`      // 请求超时时间
+}
+
+// FeishuConfig 飞书/Lark自定义机器人推送配置
+type FeishuConfig struct {
+	HookToken string        `json:"hook_token"` // webhook地址中的token部分
+	Secret    string        `json:"secret"`     // 签名校验密钥（可选）
+	BaseURL   string        `json:"base_url"`   // 开放平台地址，默认 https://open.feishu.cn
+	Timeout   time.Duration `json:"timeout"`    // 请求超时时间
+}
+
 // DefaultConfig 返回默认配置
 func DefaultConfig() PushConfig {
 	return PushConfig{
@@ -208,6 +370,7 @@ func DefaultConfig() PushConfig {
 		FlushInterval: 30 * time.Second,
 		WorkingDir:    "./working",
 		HistoryDir:    "./history",
+		TemplateDir:   "./templates",
 		WeChatConfig: WeChatConfig{
 			SendKey: "SCT7671TOKWWHhBntijf0DfzgF5luGPa", // 默认sendKey
 		},
@@ -216,9 +379,17 @@ func DefaultConfig() PushConfig {
 
 // DelayMessage 延迟消息结构
 type DelayMessage struct {
-	Message   Message     `json:"message"`
-	Options   PushOptions `json:"options"`
-	CreatedAt time.Time   `json:"created_at"` // 创建时间
+	Message     Message     `json:"message"`
+	Options     PushOptions `json:"options"`
+	CreatedAt   time.Time   `json:"created_at"`             // 创建时间
+	RateLimited bool        `json:"rate_limited,omitempty"` // 因超出限流配额转入，只随摘要发送
+}
+
+// QueuedMessage 发送队列中的消息
+type QueuedMessage struct {
+	Message    Message     `json:"message"`
+	Options    PushOptions `json:"options"`
+	EnqueuedAt time.Time   `json:"enqueued_at"` // 入队时间
 }
 
 // ScheduledMessage 定时消息结构
@@ -228,6 +399,31 @@ type ScheduledMessage struct {
 	ScheduledAt time.Time   `json:"scheduled_at"` // 计划发送时间
 }
 
+// ScheduledFilter 定时消息过滤条件，零值字段表示不过滤
+type ScheduledFilter struct {
+	AppID string    `json:"app_id"` // 发送方
+	Level string    `json:"level"`  // 消息级别：normal 或 emergency
+	Since time.Time `json:"since"`  // 计划发送时间起始（含）
+	Until time.Time `json:"until"`  // 计划发送时间截止（不含）
+}
+
+// Match 判断定时消息是否满足过滤条件
+func (f ScheduledFilter) Match(msg *ScheduledMessage) bool {
+	if f.AppID != "" && msg.Message.AppID != f.AppID {
+		return false
+	}
+	if f.Level != "" && !strings.EqualFold(msg.Message.Level.String(), f.Level) {
+		return false
+	}
+	if !f.Since.IsZero() && msg.ScheduledAt.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !msg.ScheduledAt.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
 // HistoryRecord 历史记录结构
 type HistoryRecord struct {
 	Timestamp   time.Time `json:"timestamp"`    // 时间
@@ -237,6 +433,7 @@ type HistoryRecord struct {
 	Content     string    `json:"content"`      // 发送内容
 	MessageID   string    `json:"message_id"`   // 消息ID
 	Level       string    `json:"level"`        // 消息级别
+	Status      string    `json:"status"`       // 发送结果：success 或 failed
 	Receivers   []string  `json:"receivers"`    // 接收者
 	Priority    int       `json:"priority"`     // 优先级
 	RetryCount  int       `json:"retry_count"`  // 重试次数
@@ -253,6 +450,7 @@ func NewSuccessHistoryRecord(msg Message, pusherName string, options PushOptions
 		Content:    msg.Content,
 		MessageID:  msg.ID,
 		Level:      msg.Level.String(),
+		Status:     StatusSuccess.String(),
 		Receivers:  options.Receivers,
 		Priority:   options.Priority,
 		RetryCount: options.Retry,
@@ -269,9 +467,67 @@ func NewFailedHistoryRecord(msg Message, pusherName string, options PushOptions,
 		Content:     msg.Content,
 		MessageID:   msg.ID,
 		Level:       msg.Level.String(),
+		Status:      StatusFailed.String(),
 		Receivers:   options.Receivers,
 		Priority:    options.Priority,
 		RetryCount:  options.Retry,
 		ErrorReason: errorReason,
 	}
 }
+
+// AckRecord 紧急消息确认记录
+type AckRecord struct {
+	Token     string      `json:"token"`      // 确认令牌
+	Message   Message     `json:"message"`    // 原始消息（不含确认提示）
+	Options   PushOptions `json:"options"`    // 原始推送选项
+	Attempts  int         `json:"attempts"`   // 已重发次数
+	CreatedAt time.Time   `json:"created_at"` // 首次发送时间
+	NextAt    time.Time   `json:"next_at"`    // 下一次重发时间
+	AckedAt   time.Time   `json:"acked_at"`   // 确认时间，零值表示未确认
+	AckedBy   string      `json:"acked_by"`   // 确认人
+}
+
+// Acked 是否已确认
+func (ar *AckRecord) Acked() bool {
+	return !ar.AckedAt.IsZero()
+}
+
+// RecurringRule 周期推送规则
+type RecurringRule struct {
+	Cron     string    `json:"cron"`      // cron表达式，秒字段可选，支持 @daily、@weekly 等描述符
+	EndAt    time.Time `json:"end_at"`    // 截止时间，零值表示不限
+	MaxCount int       `json:"max_count"` // 最多发送次数，0 表示不限
+}
+
+// RecurringMessage 周期推送消息
+type RecurringMessage struct {
+	ID           string          `json:"id"`                      // 周期推送ID
+	Message      Message         `json:"message"`                 // 消息内容，每次发送时生成新的消息ID（使用模板时只使用AppID）
+	Options      PushOptions     `json:"options"`                 // 推送选项
+	Rule         RecurringRule   `json:"rule"`                    // 周期规则
+	Template     string          `json:"template,omitempty"`      // 模板名称，非空时每次发送前重新渲染
+	TemplateData json.RawMessage `json:"template_data,omitempty"` // 模板数据
+	FiredCount   int             `json:"fired_count"`             // 已发送次数
+	NextAt       time.Time       `json:"next_at"`                 // 下一次发送时间
+	CreatedAt    time.Time       `json:"created_at"`              // 创建时间
+}
+
+// HistoryQuery 历史记录查询条件，零值字段表示不过滤
+type HistoryQuery struct {
+	AppID      string    `json:"app_id"`      // 发送方
+	PusherName string    `json:"pusher_name"` // 发送途径
+	Level      string    `json:"level"`       // 消息级别：normal 或 emergency
+	Status     string    `json:"status"`      // 发送结果：success 或 failed
+	MessageID  string    `json:"message_id"`  // 消息ID
+	Since      time.Time `json:"since"`       // 起始时间（含）
+	Until      time.Time `json:"until"`       // 截止时间（不含）
+	Keyword    string    `json:"keyword"`     // 标题或内容包含的关键字（不区分大小写）
+	Offset     int       `json:"offset"`      // 跳过的记录数
+	Limit      int       `json:"limit"`       // 返回的最大记录数，0 表示不限制
+}
+
+// HistoryResult 历史记录查询结果，按时间倒序
+type HistoryResult struct {
+	Records []*HistoryRecord `json:"records"` // 当前页记录
+	Total   int              `json:"total"`   // 满足条件的记录总数
+}
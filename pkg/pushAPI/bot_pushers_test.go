@@ -0,0 +1,145 @@
+package pushAPI
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"task_scheduler/pkg/pushAPI/base"
+	"task_scheduler/pkg/pushAPI/push_method"
+)
+
+// botRequest 本地模拟服务器收到的请求
+type botRequest struct {
+	path  string
+	query string
+	body  map[string]interface{}
+}
+
+// newBotServer 创建模拟机器人平台的HTTP服务器，返回固定响应
+func newBotServer(t *testing.T, response string) (*httptest.Server, *[]botRequest) {
+	var requests []botRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		var body map[string]interface{}
+		if err := json.Unmarshal(data, &body); err != nil {
+			t.Errorf("请求体不是合法JSON: %s", string(data))
+		}
+		requests = append(requests, botRequest{path: r.URL.Path, query: r.URL.RawQuery, body: body})
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestBotPushers(t *testing.T) {
+	message := NewMessage("auto-buy", "定投失败", "余额不足", Emergency)
+	message.SetMetadata("symbol", "BTCUSDT")
+	coreMsg := message.ToCore()
+
+	testCases := []struct {
+		name     string
+		response string
+		create   func(baseURL string) (push_method.IPusher, error)
+		path     string
+		check    func(t *testing.T, req botRequest)
+	}{
+		{
+			name:     "telegram",
+			response: `{"ok":true}`,
+			create: func(baseURL string) (push_method.IPusher, error) {
+				return push_method.NewTelegramPusher(base.TelegramConfig{BotToken: "TOKEN", ChatIDs: []string{"42"}, BaseURL: baseURL})
+			},
+			path: "/botTOKEN/sendMessage",
+			check: func(t *testing.T, req botRequest) {
+				text, _ := req.body["text"].(string)
+				if req.body["chat_id"] != "42" || !strings.Contains(text, "<b>symbol</b>") || !strings.Contains(text, "紧急") {
+					t.Errorf("telegram消息格式不正确: %v", req.body)
+				}
+			},
+		},
+		{
+			name:     "slack",
+			response: "ok",
+			create: func(baseURL string) (push_method.IPusher, error) {
+				return push_method.NewSlackPusher(base.SlackConfig{WebhookURL: baseURL + "/services/T000/B000/XXX"})
+			},
+			path: "/services/T000/B000/XXX",
+			check: func(t *testing.T, req botRequest) {
+				attachments, _ := req.body["attachments"].([]interface{})
+				if len(attachments) != 1 || attachments[0].(map[string]interface{})["color"] != "#d00000" {
+					t.Errorf("slack消息格式不正确: %v", req.body)
+				}
+			},
+		},
+		{
+			name:     "dingtalk",
+			response: `{"errcode":0,"errmsg":"ok"}`,
+			create: func(baseURL string) (push_method.IPusher, error) {
+				return push_method.NewDingTalkPusher(base.DingTalkConfig{AccessToken: "TOKEN", Secret: "SEC", BaseURL: baseURL})
+			},
+			path: "/robot/send",
+			check: func(t *testing.T, req botRequest) {
+				if !strings.Contains(req.query, "access_token=TOKEN") || !strings.Contains(req.query, "sign=") {
+					t.Errorf("钉钉请求缺少token或签名: %s", req.query)
+				}
+				at, _ := req.body["at"].(map[string]interface{})
+				if req.body["msgtype"] != "markdown" || at["isAtAll"] != true {
+					t.Errorf("钉钉消息格式不正确: %v", req.body)
+				}
+			},
+		},
+		{
+			name:     "feishu",
+			response: `{"code":0,"msg":"success"}`,
+			create: func(baseURL string) (push_method.IPusher, error) {
+				return push_method.NewFeishuPusher(base.FeishuConfig{HookToken: "HOOK", Secret: "SEC", BaseURL: baseURL})
+			},
+			path: "/open-apis/bot/v2/hook/HOOK",
+			check: func(t *testing.T, req botRequest) {
+				card, _ := req.body["card"].(map[string]interface{})
+				header, _ := card["header"].(map[string]interface{})
+				if req.body["sign"] == nil || header["template"] != "red" {
+					t.Errorf("飞书消息格式不正确: %v", req.body)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server, requests := newBotServer(t, tc.response)
+			pusher, err := tc.create(server.URL)
+			if err != nil {
+				t.Fatalf("创建推送器失败: %v", err)
+			}
+			if err := pusher.Push(coreMsg); err != nil {
+				t.Fatalf("推送失败: %v", err)
+			}
+			if len(*requests) != 1 {
+				t.Fatalf("期望收到1个请求，实际%d个", len(*requests))
+			}
+			req := (*requests)[0]
+			if req.path != tc.path {
+				t.Errorf("期望请求路径%s，实际%s", tc.path, req.path)
+			}
+			tc.check(t, req)
+		})
+	}
+}
+
+func TestBotPusherPlatformError(t *testing.T) {
+	server, _ := newBotServer(t, `{"errcode":310000,"errmsg":"sign not match"}`)
+	pusher, err := push_method.NewDingTalkPusher(base.DingTalkConfig{AccessToken: "TOKEN", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("创建推送器失败: %v", err)
+	}
+
+	err = pusher.Push(NewNormalMessage("app1", "标题", "内容").ToCore())
+	if err == nil || push_method.IsRetryable(err) {
+		t.Errorf("平台业务错误应返回不可重试的错误，实际为%v", err)
+	}
+}
@@ -0,0 +1,186 @@
+package pushAPI
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"task_scheduler/pkg/pushAPI/base"
+	"task_scheduler/pkg/pushAPI/push_method"
+)
+
+// newSMTPServer 启动只支持基本命令的本地SMTP服务器，返回端口和收到的邮件
+func newSMTPServer(t *testing.T) (int, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("启动SMTP服务器失败: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	mails := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+		reply("220 localhost ESMTP")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch command := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 localhost")
+			case command == "DATA":
+				reply("354 end with .")
+				var data strings.Builder
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil || line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				mails <- data.String()
+				reply("250 OK")
+			case command == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr).Port, mails
+}
+
+func TestEmailAttachments(t *testing.T) {
+	port, mails := newSMTPServer(t)
+	csvPath := filepath.Join(t.TempDir(), "daily.csv")
+	if err := os.WriteFile(csvPath, []byte("time,result\n2026-10-18,ok\n"), 0644); err != nil {
+		t.Fatalf("写入附件失败: %v", err)
+	}
+
+	pusher, err := push_method.NewEmailPusherWithConfig(base.EmailConfig{
+		Host: "127.0.0.1",
+		Port: port,
+		From: "bot@example.com",
+		To:   []string{"me@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("创建邮件推送器失败: %v", err)
+	}
+
+	message := NewNormalMessage("auto-buy", "定投成功", "定投完成")
+	message.AddBlocks(
+		TableBlock("订单", KeyValue{Key: "订单号", Value: "123"}),
+		FileDataBlock("order.json", []byte(`{"orderId":123}`)),
+		FileBlock(csvPath),
+	)
+	if err := pusher.Push(message.ToCore()); err != nil {
+		t.Fatalf("发送邮件失败: %v", err)
+	}
+
+	parsed, err := mail.ReadMessage(strings.NewReader(<-mails))
+	if err != nil {
+		t.Fatalf("解析邮件失败: %v", err)
+	}
+	_, params, _ := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+
+	var body string
+	attachments := make(map[string]string)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("解析邮件分段失败: %v", err)
+		}
+		data, _ := io.ReadAll(base64Reader(part))
+		if part.FileName() == "" {
+			body = string(data)
+		} else {
+			attachments[part.FileName()] = string(data)
+		}
+	}
+
+	if !strings.Contains(body, "<th align=\"left\">订单号</th><td>123</td>") {
+		t.Errorf("邮件正文缺少表格: %s", body)
+	}
+	if attachments["order.json"] != `{"orderId":123}` || !strings.Contains(attachments["daily.csv"], "2026-10-18,ok") {
+		t.Errorf("邮件附件不正确: %v", attachments)
+	}
+}
+
+func TestTelegramContentBlocks(t *testing.T) {
+	var texts []string
+	var documents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/sendDocument") {
+			file, header, err := r.FormFile("document")
+			if err != nil {
+				t.Errorf("附件请求格式不正确: %v", err)
+			} else {
+				data, _ := io.ReadAll(file)
+				documents = append(documents, header.Filename+":"+string(data))
+			}
+		} else {
+			var payload struct {
+				Text string `json:"text"`
+			}
+			json.NewDecoder(r.Body).Decode(&payload)
+			texts = append(texts, payload.Text)
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	pusher, err := push_method.NewTelegramPusher(base.TelegramConfig{BotToken: "TOKEN", ChatIDs: []string{"42"}, BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("创建telegram推送器失败: %v", err)
+	}
+
+	message := NewNormalMessage("auto-buy", "定投成功", "定投完成")
+	message.AddBlocks(
+		TableBlock("", KeyValue{Key: "价格", Value: "65000"}, KeyValue{Key: "AHR999", Value: "0.8"}),
+		LinkBlock("订单", "https://example.com/order/1"),
+		FileDataBlock("order.json", []byte("{}")),
+	)
+	if err := pusher.Push(message.ToCore()); err != nil {
+		t.Fatalf("推送失败: %v", err)
+	}
+
+	if len(texts) != 1 || !strings.Contains(texts[0], "<pre>价格      65000\nAHR999  0.8</pre>") ||
+		!strings.Contains(texts[0], `<a href="https://example.com/order/1">订单</a>`) {
+		t.Errorf("telegram消息格式不正确: %v", texts)
+	}
+	if len(documents) != 1 || documents[0] != "order.json:{}" {
+		t.Errorf("附件应以文件发送: %v", documents)
+	}
+}
+
+// base64Reader 解码 Content-Transfer-Encoding: base64 的邮件分段
+func base64Reader(part *multipart.Part) io.Reader {
+	if strings.EqualFold(part.Header.Get("Content-Transfer-Encoding"), "base64") {
+		return base64.NewDecoder(base64.StdEncoding, part)
+	}
+	return part
+}
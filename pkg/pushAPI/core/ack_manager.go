@@ -0,0 +1,391 @@
+package core
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"task_scheduler/pkg/pushAPI/base"
+	"task_scheduler/pkg/pushAPI/push_method"
+	"time"
+)
+
+const (
+	// ackMetadataKey 消息元数据中的确认令牌字段
+	ackMetadataKey = "ack_token"
+	// ackRetention 已确认记录的保留时间
+	ackRetention = 24 * time.Hour
+)
+
+// AckManager 紧急消息确认与升级管理器
+// 紧急消息发送时生成确认令牌并保存确认记录，超过确认窗口未确认则按升级步骤重发，
+// 直到确认或达到最大重发次数。确认记录保存在消息存储中，命令行确认优先通过运行中实例的确认接口修改。
+type AckManager struct {
+	config         base.AckConfig
+	store          MessageStore
+	registry       PusherRegistry
+	defaultPusher  push_method.IPusher
+	historyHandler *HistoryHandler
+	server         *http.Server
+	stopChan       chan struct{}
+	wg             sync.WaitGroup
+	mu             sync.Mutex // 串行化重发与确认，避免互相覆盖记录
+}
+
+// NewAckManager 创建紧急消息确认管理器
+func NewAckManager(cfg base.AckConfig, store MessageStore, registry PusherRegistry, defaultPusher push_method.IPusher, historyHandler *HistoryHandler) *AckManager {
+	return &AckManager{
+		config:         cfg,
+		store:          store,
+		registry:       registry,
+		defaultPusher:  defaultPusher,
+		historyHandler: historyHandler,
+		stopChan:       make(chan struct{}),
+	}
+}
+
+// Enabled 是否启用确认机制
+func (am *AckManager) Enabled() bool {
+	return am.config.Window > 0
+}
+
+// Start 启动重发检查循环和确认接口，确认接口监听失败时返回错误
+// 确认接口同时提供 /history，供命令行在调度器运行期间查询历史记录，未启用确认机制时同样启动
+func (am *AckManager) Start() error {
+	if am.config.ListenAddr != "" {
+		// 同步监听，端口被占用时直接失败，避免紧急消息中的确认链接失效
+		listener, err := net.Listen("tcp", am.config.ListenAddr)
+		if err != nil {
+			return fmt.Errorf("监听确认接口失败: %w", err)
+		}
+		mux := http.NewServeMux()
+		mux.Handle("/ack", am.Handler())
+		if am.historyHandler != nil {
+			mux.Handle("/history", am.historyHandler.Handler())
+		}
+		am.server = &http.Server{Handler: mux}
+
+		am.wg.Add(1)
+		go func() {
+			defer am.wg.Done()
+			if err := am.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("确认接口服务异常退出: %v", err)
+			}
+		}()
+		log.Printf("确认接口已启动: %s/ack", listener.Addr())
+	}
+
+	if !am.Enabled() {
+		return nil
+	}
+
+	am.wg.Add(1)
+	go am.checkLoop()
+	return nil
+}
+
+// Stop 停止确认管理器
+func (am *AckManager) Stop() {
+	close(am.stopChan)
+	if am.server != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		am.server.Shutdown(ctx)
+	}
+	am.wg.Wait()
+}
+
+// Track 保存紧急消息的确认记录，返回确认令牌
+func (am *AckManager) Track(msg base.Message, options base.PushOptions) (string, error) {
+	token, err := newAckToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	record := &base.AckRecord{
+		Token:     token,
+		Message:   msg,
+		Options:   options,
+		CreatedAt: now,
+		NextAt:    now.Add(am.config.Window),
+	}
+	if err := am.store.PutAck(record); err != nil {
+		return "", fmt.Errorf("保存确认记录失败: %w", err)
+	}
+	return token, nil
+}
+
+// Decorate 在消息中附加确认提示，attempt>0 时在标题中标注第几次提醒
+func (am *AckManager) Decorate(msg base.Message, token string, attempt int) base.Message {
+	decorated := msg
+	decorated.Metadata = make(map[string]interface{}, len(msg.Metadata)+1)
+	for k, v := range msg.Metadata {
+		decorated.Metadata[k] = v
+	}
+	decorated.Metadata[ackMetadataKey] = token
+
+	if attempt > 0 {
+		decorated.Title = fmt.Sprintf("[第%d次提醒] %s", attempt, msg.Title)
+	}
+	if am.config.PublicURL != "" {
+		decorated.Content = fmt.Sprintf("%s\n\n确认收到: %s?token=%s", msg.Content, am.config.PublicURL, token)
+	} else {
+		decorated.Content = fmt.Sprintf("%s\n\n确认收到请执行: task_scheduler ack %s", msg.Content, token)
+	}
+	return decorated
+}
+
+// Ack 确认紧急消息，重复确认不报错
+func (am *AckManager) Ack(token, by string) error {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	return acknowledge(am.store, token, by)
+}
+
+// Handler 返回确认接口，GET/POST ?token=xxx[&by=name]
+func (am *AckManager) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodPost {
+			http.Error(w, "不支持的请求方法", http.StatusMethodNotAllowed)
+			return
+		}
+
+		token := r.FormValue("token")
+		by := r.FormValue("by")
+		if by == "" {
+			by = r.RemoteAddr
+		}
+
+		if err := am.Ack(token, by); err != nil {
+			if errors.Is(err, ErrAckNotFound) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprintln(w, "已确认")
+	})
+}
+
+// checkLoop 定期检查超时未确认的紧急消息
+func (am *AckManager) checkLoop() {
+	defer am.wg.Done()
+
+	interval := am.config.Window / 10
+	if interval > 30*time.Second {
+		interval = 30 * time.Second
+	}
+	if interval < 10*time.Millisecond {
+		interval = 10 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-am.stopChan:
+			return
+		case <-ticker.C:
+			if err := am.checkPending(); err != nil {
+				log.Printf("检查待确认消息失败: %v", err)
+			}
+		}
+	}
+}
+
+// checkPending 重发超时未确认的消息，清理过期的已确认记录
+// 持有锁时只更新确认记录，发送（含重试等待）时不持有锁，不阻塞确认
+func (am *AckManager) checkPending() error {
+	due, err := am.claimPending(time.Now())
+	if err != nil {
+		return err
+	}
+	for _, record := range due {
+		am.resend(record)
+	}
+	return nil
+}
+
+// claimPending 取出需要重发的记录，先增加重发次数并写回下次提醒时间，再在锁外发送
+func (am *AckManager) claimPending(now time.Time) ([]*base.AckRecord, error) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	records, err := am.store.ListAcks()
+	if err != nil {
+		return nil, err
+	}
+
+	var due []*base.AckRecord
+	for _, record := range records {
+		if record.Acked() {
+			if now.Sub(record.AckedAt) > ackRetention {
+				am.store.DeleteAck(record.Token)
+			}
+			continue
+		}
+		if now.Before(record.NextAt) {
+			continue
+		}
+
+		if am.config.MaxAttempts > 0 && record.Attempts >= am.config.MaxAttempts {
+			log.Printf("紧急消息 %s 重发%d次仍未确认，停止提醒", record.Message.ID, record.Attempts)
+			if am.historyHandler != nil {
+				am.historyHandler.RecordFailure(record.Message, am.defaultPusher.GetName(), record.Options, fmt.Sprintf("重发%d次仍未确认", record.Attempts))
+			}
+			am.store.DeleteAck(record.Token)
+			continue
+		}
+
+		record.Attempts++
+		record.NextAt = now.Add(am.config.Window)
+		if err := am.store.PutAck(record); err != nil {
+			log.Printf("更新确认记录失败: %v", err)
+			continue
+		}
+		due = append(due, record)
+	}
+	return due, nil
+}
+
+// resend 按升级步骤重发未确认的消息
+func (am *AckManager) resend(record *base.AckRecord) {
+	pusher := am.defaultPusher
+	options := record.Options
+	if len(am.config.Escalation) > 0 {
+		stepIndex := record.Attempts - 1
+		if stepIndex >= len(am.config.Escalation) {
+			stepIndex = len(am.config.Escalation) - 1
+		}
+		step := am.config.Escalation[stepIndex]
+		if step.Pusher != "" {
+			if stepPusher, err := am.registry.Get(step.Pusher); err == nil {
+				pusher = stepPusher
+			} else {
+				log.Printf("升级推送器不可用，使用默认推送器: %v", err)
+			}
+		}
+		if len(step.Receivers) > 0 {
+			options.Receivers = step.Receivers
+		}
+	}
+
+	msg := am.Decorate(record.Message, record.Token, record.Attempts)
+	msg.SetSentAt(time.Now())
+	msg.SetSendStatus(base.StatusSuccess)
+	if err := pushWithRetry(pusher, msg, options); err != nil {
+		msg.SetSendStatus(base.StatusFailed)
+		if am.historyHandler != nil {
+			am.historyHandler.RecordFailure(msg, pusher.GetName(), options, fmt.Sprintf("紧急消息重发失败: %v", err))
+		}
+		log.Printf("紧急消息重发失败: %s, %v", record.Message.ID, err)
+		return
+	}
+	if am.historyHandler != nil {
+		am.historyHandler.RecordSuccess(msg, pusher.GetName(), options)
+	}
+	log.Printf("紧急消息未确认，第%d次提醒已发送: %s -> %s", record.Attempts, record.Message.ID, pusher.GetName())
+}
+
+// Acknowledge 确认紧急消息，无需初始化推送器（用于命令行等场景）
+// 配置了确认接口时优先请求运行中实例的 /ack（运行中的实例持有存储的文件锁），
+// 接口无法连接（没有运行中的实例）时直接打开配置中的消息存储确认
+func Acknowledge(cfg base.PushConfig, token, by string) error {
+	if ackURL := localInstanceURL(cfg.Ack.ListenAddr, "/ack"); ackURL != "" {
+		err := acknowledgeRemote(ackURL, token, by)
+		if !errors.Is(err, errInstanceUnreachable) {
+			return err
+		}
+		log.Printf("确认接口无法连接，直接修改消息存储: %v", err)
+	}
+
+	store, err := NewMessageStore(cfg)
+	if err != nil {
+		return fmt.Errorf("打开消息存储失败（调度器运行中时需配置 ack.listen_addr）: %w", err)
+	}
+	defer store.Close()
+
+	return acknowledge(store, token, by)
+}
+
+// errInstanceUnreachable 运行中实例的接口无法连接
+var errInstanceUnreachable = errors.New("运行中实例的接口无法连接")
+
+// localInstanceURL 根据确认接口监听地址生成本机访问 path 的地址，未配置时返回空
+func localInstanceURL(listenAddr, path string) string {
+	if listenAddr == "" {
+		return ""
+	}
+	host, port, err := net.SplitHostPort(listenAddr)
+	if err != nil {
+		return ""
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+	return "http://" + net.JoinHostPort(host, port) + path
+}
+
+// acknowledgeRemote 通过运行中实例的确认接口确认紧急消息，连接失败时返回 errInstanceUnreachable
+func acknowledgeRemote(ackURL, token, by string) error {
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.PostForm(ackURL, url.Values{"token": {token}, "by": {by}})
+	if err != nil {
+		return fmt.Errorf("%w: %v", errInstanceUnreachable, err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return ErrAckNotFound
+	default:
+		return fmt.Errorf("确认接口返回状态码 %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+}
+
+// acknowledge 将确认记录标记为已确认
+func acknowledge(store MessageStore, token, by string) error {
+	if token == "" {
+		return ErrAckNotFound
+	}
+
+	record, err := store.GetAck(token)
+	if err != nil {
+		return err
+	}
+	if record.Acked() {
+		return nil
+	}
+
+	record.AckedAt = time.Now()
+	record.AckedBy = by
+	if err := store.PutAck(record); err != nil {
+		return fmt.Errorf("保存确认记录失败: %w", err)
+	}
+	log.Printf("紧急消息已确认: %s (%s)", record.Message.ID, by)
+	return nil
+}
+
+// newAckToken 生成随机确认令牌
+func newAckToken() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("生成确认令牌失败: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,418 @@
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"task_scheduler/pkg/pushAPI/base"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// boltLockTimeout 等待数据库文件锁的时间
+const boltLockTimeout = time.Second
+
+var (
+	bucketDelay        = []byte("delay")
+	bucketScheduled    = []byte("scheduled")
+	bucketHistoryIndex = []byte("history_index")
+	bucketHistoryMonth = []byte("history_month")
+	bucketAck          = []byte("ack")
+	bucketRecurring    = []byte("recurring")
+	bucketQueue        = []byte("queue")
+)
+
+// BoltStore 基于bbolt嵌入式数据库的消息存储
+// 每次写入都在单个事务中完成，进程崩溃不会损坏已有数据；追加历史记录不需要重写整月数据，
+// 并维护消息ID索引用于按ID查找。
+// 数据库文件同一时间只能被一个进程打开，调度器运行期间其他进程打开时返回 ErrStoreLocked。
+//
+// 桶结构：
+//   - delay：键为 摘要时间(YYYYMMDD_HHMM)+序号
+//   - scheduled：键为消息ID
+//   - success_send / failed_send：键为 月份(YYYYMM)+序号
+//   - history_index：键为 消息ID\x00记录类型\x00记录键
+//   - history_month：键为月份
+//   - ack：键为确认令牌
+//   - recurring：键为周期推送ID
+//   - queue：键为消息ID
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore 打开（或创建）bbolt消息存储，文件被其他进程占用超过 boltLockTimeout 时返回 ErrStoreLocked
+func NewBoltStore(path string) (*BoltStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("创建存储目录失败: %w", err)
+	}
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: boltLockTimeout})
+	if errors.Is(err, bolt.ErrTimeout) {
+		return nil, fmt.Errorf("%w: %s", ErrStoreLocked, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("打开消息数据库失败: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{bucketDelay, bucketScheduled, bucketHistoryIndex, bucketHistoryMonth, bucketAck, bucketRecurring, bucketQueue, []byte(historySuccess), []byte(historyFailed)} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("初始化消息数据库失败: %w", err)
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+// AppendDelay 追加延迟消息
+func (bs *BoltStore) AppendDelay(digestAt time.Time, msg *base.DelayMessage) error {
+	return bs.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketDelay)
+		return putJSON(bucket, sequenceKey(bucket, []byte(digestAt.Format("20060102_1504"))), msg)
+	})
+}
+
+// ListDelay 获取全部延迟消息
+func (bs *BoltStore) ListDelay() ([]*base.DelayMessage, error) {
+	var messages []*base.DelayMessage
+	err := bs.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketDelay).ForEach(func(k, v []byte) error {
+			var msg base.DelayMessage
+			if err := json.Unmarshal(v, &msg); err != nil {
+				return fmt.Errorf("解析延迟消息失败: %w", err)
+			}
+			messages = append(messages, &msg)
+			return nil
+		})
+	})
+	return messages, err
+}
+
+// ClearDelay 清空全部延迟消息
+func (bs *BoltStore) ClearDelay() error {
+	return bs.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket(bucketDelay); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucket(bucketDelay)
+		return err
+	})
+}
+
+// AppendScheduled 追加定时消息（同ID的定时消息会被覆盖）
+func (bs *BoltStore) AppendScheduled(msg *base.ScheduledMessage) error {
+	return bs.db.Update(func(tx *bolt.Tx) error {
+		return putJSON(tx.Bucket(bucketScheduled), []byte(msg.Message.ID), msg)
+	})
+}
+
+// ListScheduled 获取全部定时消息
+func (bs *BoltStore) ListScheduled() ([]*base.ScheduledMessage, error) {
+	var messages []*base.ScheduledMessage
+	err := bs.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketScheduled).ForEach(func(k, v []byte) error {
+			var msg base.ScheduledMessage
+			if err := json.Unmarshal(v, &msg); err != nil {
+				return fmt.Errorf("解析定时消息失败: %w", err)
+			}
+			messages = append(messages, &msg)
+			return nil
+		})
+	})
+	return messages, err
+}
+
+// RemoveScheduled 按消息ID删除定时消息
+func (bs *BoltStore) RemoveScheduled(messageIDs ...string) error {
+	return bs.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketScheduled)
+		for _, id := range messageIDs {
+			if err := bucket.Delete([]byte(id)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// AppendHistory 追加历史记录并更新消息ID索引
+func (bs *BoltStore) AppendHistory(recordType string, record *base.HistoryRecord) error {
+	return bs.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(recordType))
+		if bucket == nil {
+			return fmt.Errorf("未知的历史记录类型: %s", recordType)
+		}
+
+		month := record.Timestamp.Format("200601")
+		key := sequenceKey(bucket, []byte(month))
+		if err := putJSON(bucket, key, record); err != nil {
+			return err
+		}
+		if err := tx.Bucket(bucketHistoryIndex).Put(historyIndexKey(record.MessageID, recordType, key), nil); err != nil {
+			return err
+		}
+		return tx.Bucket(bucketHistoryMonth).Put([]byte(month), nil)
+	})
+}
+
+// ListHistory 获取指定类型和月份的历史记录
+func (bs *BoltStore) ListHistory(recordType, yearMonth string) ([]*base.HistoryRecord, error) {
+	records := []*base.HistoryRecord{}
+	err := bs.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(recordType))
+		if bucket == nil {
+			return fmt.Errorf("未知的历史记录类型: %s", recordType)
+		}
+
+		prefix := []byte(yearMonth)
+		c := bucket.Cursor()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			var record base.HistoryRecord
+			if err := json.Unmarshal(v, &record); err != nil {
+				return fmt.Errorf("解析历史记录失败: %w", err)
+			}
+			fillHistoryStatus(recordType, &record)
+			records = append(records, &record)
+		}
+		return nil
+	})
+	return records, err
+}
+
+// FindHistory 通过消息ID索引查找历史记录
+func (bs *BoltStore) FindHistory(messageID string) ([]*base.HistoryRecord, error) {
+	var records []*base.HistoryRecord
+	err := bs.db.View(func(tx *bolt.Tx) error {
+		prefix := append([]byte(messageID), 0)
+		c := tx.Bucket(bucketHistoryIndex).Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			parts := bytes.SplitN(k[len(prefix):], []byte{0}, 2)
+			if len(parts) != 2 {
+				continue
+			}
+			bucket := tx.Bucket(parts[0])
+			if bucket == nil {
+				continue
+			}
+			v := bucket.Get(parts[1])
+			if v == nil {
+				continue
+			}
+			var record base.HistoryRecord
+			if err := json.Unmarshal(v, &record); err != nil {
+				return fmt.Errorf("解析历史记录失败: %w", err)
+			}
+			fillHistoryStatus(string(parts[0]), &record)
+			records = append(records, &record)
+		}
+		return nil
+	})
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Timestamp.Before(records[j].Timestamp)
+	})
+	return records, err
+}
+
+// HistoryMonths 获取有历史记录的月份
+func (bs *BoltStore) HistoryMonths() ([]string, error) {
+	months := []string{}
+	err := bs.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketHistoryMonth).ForEach(func(k, v []byte) error {
+			months = append(months, string(k))
+			return nil
+		})
+	})
+	return months, err
+}
+
+// DeleteHistoryBefore 删除早于指定月份的历史记录及其索引
+func (bs *BoltStore) DeleteHistoryBefore(yearMonth string) error {
+	cutoff := []byte(yearMonth)
+	return bs.db.Update(func(tx *bolt.Tx) error {
+		index := tx.Bucket(bucketHistoryIndex)
+		for _, recordType := range []string{historySuccess, historyFailed} {
+			c := tx.Bucket([]byte(recordType)).Cursor()
+			for k, v := c.First(); k != nil && bytes.Compare(k, cutoff) < 0; k, v = c.First() {
+				var record base.HistoryRecord
+				if err := json.Unmarshal(v, &record); err == nil {
+					if err := index.Delete(historyIndexKey(record.MessageID, recordType, k)); err != nil {
+						return err
+					}
+				}
+				if err := c.Delete(); err != nil {
+					return err
+				}
+			}
+		}
+
+		c := tx.Bucket(bucketHistoryMonth).Cursor()
+		for k, _ := c.First(); k != nil && bytes.Compare(k, cutoff) < 0; k, _ = c.First() {
+			if err := c.Delete(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// PutAck 保存确认记录
+func (bs *BoltStore) PutAck(record *base.AckRecord) error {
+	return bs.db.Update(func(tx *bolt.Tx) error {
+		return putJSON(tx.Bucket(bucketAck), []byte(record.Token), record)
+	})
+}
+
+// GetAck 按令牌获取确认记录
+func (bs *BoltStore) GetAck(token string) (*base.AckRecord, error) {
+	var record *base.AckRecord
+	err := bs.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(bucketAck).Get([]byte(token))
+		if v == nil {
+			return ErrAckNotFound
+		}
+		record = &base.AckRecord{}
+		if err := json.Unmarshal(v, record); err != nil {
+			return fmt.Errorf("解析确认记录失败: %w", err)
+		}
+		return nil
+	})
+	return record, err
+}
+
+// ListAcks 获取全部确认记录
+func (bs *BoltStore) ListAcks() ([]*base.AckRecord, error) {
+	var records []*base.AckRecord
+	err := bs.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketAck).ForEach(func(k, v []byte) error {
+			var record base.AckRecord
+			if err := json.Unmarshal(v, &record); err != nil {
+				return fmt.Errorf("解析确认记录失败: %w", err)
+			}
+			records = append(records, &record)
+			return nil
+		})
+	})
+	return records, err
+}
+
+// DeleteAck 删除确认记录
+func (bs *BoltStore) DeleteAck(token string) error {
+	return bs.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketAck).Delete([]byte(token))
+	})
+}
+
+// PutRecurring 保存周期推送
+func (bs *BoltStore) PutRecurring(msg *base.RecurringMessage) error {
+	return bs.db.Update(func(tx *bolt.Tx) error {
+		return putJSON(tx.Bucket(bucketRecurring), []byte(msg.ID), msg)
+	})
+}
+
+// ListRecurring 获取全部周期推送
+func (bs *BoltStore) ListRecurring() ([]*base.RecurringMessage, error) {
+	var messages []*base.RecurringMessage
+	err := bs.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketRecurring).ForEach(func(k, v []byte) error {
+			var msg base.RecurringMessage
+			if err := json.Unmarshal(v, &msg); err != nil {
+				return fmt.Errorf("解析周期推送失败: %w", err)
+			}
+			messages = append(messages, &msg)
+			return nil
+		})
+	})
+	return messages, err
+}
+
+// DeleteRecurring 删除周期推送
+func (bs *BoltStore) DeleteRecurring(id string) error {
+	return bs.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketRecurring)
+		if bucket.Get([]byte(id)) == nil {
+			return ErrRecurringNotFound
+		}
+		return bucket.Delete([]byte(id))
+	})
+}
+
+// PutQueued 保存发送队列中的消息
+func (bs *BoltStore) PutQueued(msg *base.QueuedMessage) error {
+	return bs.db.Update(func(tx *bolt.Tx) error {
+		return putJSON(tx.Bucket(bucketQueue), []byte(msg.Message.ID), msg)
+	})
+}
+
+// ListQueued 获取发送队列中的全部消息
+func (bs *BoltStore) ListQueued() ([]*base.QueuedMessage, error) {
+	var messages []*base.QueuedMessage
+	err := bs.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketQueue).ForEach(func(k, v []byte) error {
+			var msg base.QueuedMessage
+			if err := json.Unmarshal(v, &msg); err != nil {
+				return fmt.Errorf("解析队列消息失败: %w", err)
+			}
+			messages = append(messages, &msg)
+			return nil
+		})
+	})
+	return messages, err
+}
+
+// RemoveQueued 按消息ID删除发送队列中的消息
+func (bs *BoltStore) RemoveQueued(messageIDs ...string) error {
+	return bs.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketQueue)
+		for _, id := range messageIDs {
+			if err := bucket.Delete([]byte(id)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Close 关闭数据库
+func (bs *BoltStore) Close() error {
+	return bs.db.Close()
+}
+
+// sequenceKey 生成 前缀+自增序号 形式的键，保证同一前缀下按写入顺序排列
+func sequenceKey(bucket *bolt.Bucket, prefix []byte) []byte {
+	seq, _ := bucket.NextSequence()
+	key := make([]byte, len(prefix)+8)
+	copy(key, prefix)
+	binary.BigEndian.PutUint64(key[len(prefix):], seq)
+	return key
+}
+
+// historyIndexKey 生成消息ID索引键
+func historyIndexKey(messageID, recordType string, recordKey []byte) []byte {
+	key := make([]byte, 0, len(messageID)+len(recordType)+len(recordKey)+2)
+	key = append(key, messageID...)
+	key = append(key, 0)
+	key = append(key, recordType...)
+	key = append(key, 0)
+	return append(key, recordKey...)
+}
+
+// putJSON 序列化并写入桶
+func putJSON(bucket *bolt.Bucket, key []byte, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("序列化失败: %w", err)
+	}
+	return bucket.Put(key, data)
+}
@@ -1,8 +1,10 @@
 package core
 
 import (
+	"encoding/json"
 	"fmt"
 	"log"
+	"net/http"
 	"sync"
 	"task_scheduler/pkg/pushAPI/base"
 	"task_scheduler/pkg/pushAPI/push_method"
@@ -14,6 +16,12 @@ type PushController struct {
 	currentPusher  push_method.IPusher // 当前激活的推送器
 	workingManager *WorkingManager     // 工作目录管理器
 	historyHandler *HistoryHandler     // 历史记录处理器
+	store          MessageStore        // 消息存储
+	ackManager     *AckManager         // 紧急消息确认管理器
+	recurring      *RecurringManager   // 周期推送管理器
+	queue          *SendQueue          // 按优先级发送的消息队列
+	templates      *TemplateManager    // 消息模板管理器
+	rateLimiter    *RateLimiter        // 限流与去重
 	pushRegistry   PusherRegistry      // 推送器注册表
 	config         base.PushConfig
 	stopChan       chan struct{}
@@ -26,10 +34,11 @@ func NewPushController(cfg base.PushConfig) *PushController {
 	registry := NewPusherRegistry()
 
 	return &PushController{
-		pushRegistry:   registry,
-		historyHandler: NewHistoryHandler(cfg.HistoryDir),
-		config:         cfg,
-		stopChan:       make(chan struct{}),
+		pushRegistry: registry,
+		templates:    NewTemplateManager(cfg.TemplateDir),
+		rateLimiter:  NewRateLimiter(cfg.RateLimit),
+		config:       cfg,
+		stopChan:     make(chan struct{}),
 	}
 }
 
@@ -39,6 +48,21 @@ func (pc *PushController) Initialize(cfg base.PushConfig, method base.PushMethod
 	defer pc.mu.Unlock()
 
 	// 根据推送方式创建内置推送器
+	pusher, err := newBuiltinPusher(cfg, method)
+	if err != nil {
+		return err
+	}
+
+	// 注册推送器
+	if err := pc.pushRegistry.Register(method.String(), pusher); err != nil {
+		return fmt.Errorf("注册推送器失败: %w", err)
+	}
+
+	return pc.start(cfg, pusher)
+}
+
+// newBuiltinPusher 根据推送方式创建内置推送器
+func newBuiltinPusher(cfg base.PushConfig, method base.PushMethod) (push_method.IPusher, error) {
 	var pusher push_method.IPusher
 	switch method {
 	case base.WeChat:
@@ -50,32 +74,54 @@ func (pc *PushController) Initialize(cfg base.PushConfig, method base.PushMethod
 		}
 
 	case base.Email:
-		pusher = push_method.NewEmailPusher()
+		// 配置了SMTP服务器时真实发送，否则只记录日志
+		if cfg.EmailConfig.Host != "" {
+			emailPusher, err := push_method.NewEmailPusherWithConfig(cfg.EmailConfig)
+			if err != nil {
+				return nil, fmt.Errorf("创建邮件推送器失败: %w", err)
+			}
+			pusher = emailPusher
+		} else {
+			pusher = push_method.NewEmailPusher()
+		}
 	case base.SMS:
 		pusher = push_method.NewSMSPusher()
 	case base.Logger:
 		pusher = push_method.NewLogPusher()
+	case base.Webhook:
+		webhookPusher, err := push_method.NewWebhookPusher(cfg.WebhookConfig)
+		if err != nil {
+			return nil, fmt.Errorf("创建webhook推送器失败: %w", err)
+		}
+		pusher = webhookPusher
+	case base.Telegram:
+		telegramPusher, err := push_method.NewTelegramPusher(cfg.TelegramConfig)
+		if err != nil {
+			return nil, fmt.Errorf("创建telegram推送器失败: %w", err)
+		}
+		pusher = telegramPusher
+	case base.Slack:
+		slackPusher, err := push_method.NewSlackPusher(cfg.SlackConfig)
+		if err != nil {
+			return nil, fmt.Errorf("创建slack推送器失败: %w", err)
+		}
+		pusher = slackPusher
+	case base.DingTalk:
+		dingTalkPusher, err := push_method.NewDingTalkPusher(cfg.DingTalkConfig)
+		if err != nil {
+			return nil, fmt.Errorf("创建钉钉推送器失败: %w", err)
+		}
+		pusher = dingTalkPusher
+	case base.Feishu:
+		feishuPusher, err := push_method.NewFeishuPusher(cfg.FeishuConfig)
+		if err != nil {
+			return nil, fmt.Errorf("创建飞书推送器失败: %w", err)
+		}
+		pusher = feishuPusher
 	default:
-		return fmt.Errorf("不支持的推送方式: %s", method.String())
+		return nil, fmt.Errorf("不支持的推送方式: %s", method.String())
 	}
-
-	// 注册推送器
-	if err := pc.pushRegistry.Register(method.String(), pusher); err != nil {
-		return fmt.Errorf("注册推送器失败: %w", err)
-	}
-
-	pc.currentPusher = pusher
-	pc.config = cfg
-
-	// 创建延迟处理器
-	pc.workingManager = NewWorkingManager(cfg.WorkingDir, pusher, pc.historyHandler)
-
-	// 启动延迟处理器
-	if err := pc.workingManager.Start(); err != nil {
-		return fmt.Errorf("启动延迟处理器失败: %w", err)
-	}
-
-	return nil
+	return pusher, nil
 }
 
 // InitializeWithPusher 高级初始化（自定义推送器）
@@ -92,97 +138,250 @@ func (pc *PushController) InitializeWithPusher(cfg base.PushConfig, pusher push_
 		return fmt.Errorf("注册推送器失败: %w", err)
 	}
 
-	pc.currentPusher = pusher
-	pc.config = cfg
+	return pc.start(cfg, pusher)
+}
+
+// start 打开消息存储并启动延迟处理器
+func (pc *PushController) start(cfg base.PushConfig, pusher push_method.IPusher) error {
+	// 升级步骤中的接收者必须在通讯录中
+	for i, step := range cfg.Ack.Escalation {
+		if err := cfg.AddressBook.Check(step.Receivers); err != nil {
+			return fmt.Errorf("第%d个升级步骤配置错误: %w", i+1, err)
+		}
+	}
+
+	store, err := NewMessageStore(cfg)
+	if err != nil {
+		return fmt.Errorf("打开消息存储失败: %w", err)
+	}
+
+	// 创建发送队列（发送协程经过 deliver，在其他组件就绪后启动）
+	historyHandler := NewHistoryHandler(store)
+	queue, err := NewSendQueue(cfg, store, pc.sendQueued, func(msg *base.QueuedMessage) {
+		historyHandler.RecordFailure(msg.Message, pusher.GetName(), msg.Options, "发送队列已满，被更高优先级的消息挤出")
+	})
+	if err != nil {
+		store.Close()
+		return fmt.Errorf("发送队列配置错误: %w", err)
+	}
 
 	// 创建延迟处理器
-	pc.workingManager = NewWorkingManager(cfg.WorkingDir, pusher, pc.historyHandler)
+	// 到期的定时消息经过 deliver 发送，与立即推送一样受限流、免打扰和确认升级约束
+	workingManager := NewWorkingManager(store, pusher, historyHandler, cfg.Digest, pc.deliver)
+
+	// 加载延迟处理器（发送循环经过 deliver，在其他组件就绪后启动）
+	if err := workingManager.Load(); err != nil {
+		store.Close()
+		return fmt.Errorf("加载延迟处理器失败: %w", err)
+	}
+
+	// 启动紧急消息确认管理器
+	ackManager := NewAckManager(cfg.Ack, store, pc.pushRegistry, pusher, historyHandler)
+	if err := ackManager.Start(); err != nil {
+		workingManager.Stop()
+		store.Close()
+		return fmt.Errorf("启动确认管理器失败: %w", err)
+	}
 
-	// 启动延迟处理器
-	if err := pc.workingManager.Start(); err != nil {
-		return fmt.Errorf("启动延迟处理器失败: %w", err)
+	pc.applyLayout(pusher)
+	pc.applyAddressBook(pusher, cfg.AddressBook)
+	pc.currentPusher = pusher
+	pc.config = cfg
+	pc.store = store
+	pc.historyHandler = historyHandler
+	pc.workingManager = workingManager
+	pc.ackManager = ackManager
+
+	// 启动发送队列，恢复上次未发送的消息
+	if err := queue.Start(); err != nil {
+		ackManager.Stop()
+		workingManager.Stop()
+		store.Close()
+		return fmt.Errorf("启动发送队列失败: %w", err)
 	}
+	pc.queue = queue
 
+	// 启动延迟处理器和周期推送管理器（发送时经过 deliver 和 PushNow，需在其他组件就绪后启动）
+	workingManager.Start()
+	pc.recurring = NewRecurringManager(store, pc.fireRecurring)
+	pc.recurring.Start()
 	return nil
 }
 
 // PushNow 立即推送
 func (pc *PushController) PushNow(message base.Message, options base.PushOptions) error {
 	pc.mu.RLock()
-	defer pc.mu.RUnlock()
-
 	if pc.currentPusher == nil {
+		pc.mu.RUnlock()
 		return fmt.Errorf("推送器未初始化")
 	}
 
 	// 验证推送选项
-	if err := pc.currentPusher.Validate(options); err != nil {
+	if err := pc.validate(options); err != nil {
 		// 记录验证失败
 		if pc.historyHandler != nil {
 			pc.historyHandler.RecordFailure(message, pc.currentPusher.GetName(), options, fmt.Sprintf("验证失败: %v", err))
 		}
+		pc.mu.RUnlock()
 		return fmt.Errorf("推送选项验证失败: %w", err)
 	}
+	pc.mu.RUnlock()
+
+	// 去重：去重窗口内重复的消息直接丢弃
+	if pc.rateLimiter.IsDuplicate(message) {
+		log.Printf("重复消息已丢弃: %s", message.ID)
+		return nil
+	}
+
+	// 发送（含重试等待）时不持有锁，避免阻塞注册推送器、停止等操作
+	if err := pc.deliver(message, options); err != nil {
+		pc.rateLimiter.Forget(message)
+		return err
+	}
+	return nil
+}
+
+// deliver 经过限流、免打扰和确认处理后发送消息，调用方需已完成校验和去重且不持有锁
+func (pc *PushController) deliver(message base.Message, options base.PushOptions) error {
+	pc.mu.RLock()
+	pusher := pc.currentPusher
+	workingManager := pc.workingManager
+	ackManager := pc.ackManager
+	historyHandler := pc.historyHandler
+	pc.mu.RUnlock()
+
+	// 限流：紧急消息不受限制，超出配额的普通消息转入延迟合并发送
+	if message.Level == base.Emergency {
+		pc.rateLimiter.Consume(pusher.GetName(), message.AppID)
+	} else if !pc.rateLimiter.Allow(pusher.GetName(), message.AppID) {
+		if err := workingManager.AddRateLimitedMessage(message, options); err != nil {
+			return fmt.Errorf("限流消息写入延迟文件失败: %w", err)
+		}
+		log.Printf("消息超出限流配额，已转入延迟消息: %s", message.ID)
+		return nil
+	}
+
+	// 免打扰：普通消息留到下一次摘要发送
+	quiet := workingManager.InQuietHours(time.Now())
+	if quiet && message.Level != base.Emergency {
+		if err := workingManager.AddDelayMessage(message, options); err != nil {
+			return fmt.Errorf("免打扰消息写入延迟文件失败: %w", err)
+		}
+		log.Printf("免打扰时段，消息已转入延迟消息: %s", message.ID)
+		return nil
+	}
+
+	// 紧急消息附带确认令牌，超时未确认时由确认管理器重发升级
+	if message.Level == base.Emergency && ackManager.Enabled() {
+		if token, err := ackManager.Track(message, options); err != nil {
+			log.Printf("创建确认记录失败: %v", err)
+		} else {
+			message = ackManager.Decorate(message, token, 0)
+		}
+	}
 
 	// 设置发送时间
 	sentTime := time.Now()
 	message.SetSentAt(sentTime)
 	message.SetSendStatus(base.StatusSuccess)
 
-	// 推送消息
-	if err := pc.currentPusher.Push(message); err != nil {
+	// 推送消息（按选项重试）
+	if err := pushWithRetry(pusher, message, options); err != nil {
 		// 设置失败状态
 		message.SetSendStatus(base.StatusFailed)
 		// 记录推送失败
-		if pc.historyHandler != nil {
-			pc.historyHandler.RecordFailure(message, pc.currentPusher.GetName(), options, fmt.Sprintf("推送失败: %v", err))
+		if historyHandler != nil {
+			historyHandler.RecordFailure(message, pusher.GetName(), options, fmt.Sprintf("推送失败: %v", err))
 		}
 		return fmt.Errorf("推送消息失败: %w", err)
 	}
 
 	// 记录推送成功
-	if pc.historyHandler != nil {
-		pc.historyHandler.RecordSuccess(message, pc.currentPusher.GetName(), options)
+	if historyHandler != nil {
+		historyHandler.RecordSuccess(message, pusher.GetName(), options)
 	}
 
 	log.Printf("消息推送成功: %s", message.ID)
 
-	// 立即推送后，同时发送所有延迟消息
-	if err := pc.workingManager.SendAllDelayMessages(); err != nil {
-		log.Printf("发送延迟消息失败: %v", err)
+	// 立即推送后，同时发送延迟消息（免打扰时段内只发送紧急消息本身）
+	// 被限流的消息不在此发送，留到摘要发送，否则每次放行的推送都会多消耗一次配额
+	if !quiet {
+		if err := workingManager.SendDelayMessagesAfterPush(); err != nil {
+			log.Printf("发送延迟消息失败: %v", err)
+		}
 	}
 
 	return nil
 }
 
-// Enqueue 入队消息（现在使用延迟文件处理）
+// Enqueue 消息进入发送队列，按优先级发送；队列已满时按 Queue.FullPolicy 处理
 func (pc *PushController) Enqueue(message base.Message, options base.PushOptions) error {
 	pc.mu.RLock()
-	defer pc.mu.RUnlock()
+	queue := pc.queue
+	if queue == nil {
+		pc.mu.RUnlock()
+		return fmt.Errorf("发送队列未初始化")
+	}
 
-	if pc.workingManager == nil {
-		return fmt.Errorf("延迟处理器未初始化")
+	// 验证推送选项
+	if err := pc.validate(options); err != nil {
+		pc.mu.RUnlock()
+		return fmt.Errorf("推送选项验证失败: %w", err)
 	}
 
-	// 将消息写入延迟文件
-	if err := pc.workingManager.AddDelayMessage(message, options); err != nil {
-		return fmt.Errorf("写入延迟文件失败: %w", err)
+	// 去重：去重窗口内重复的消息直接丢弃
+	if pc.rateLimiter.IsDuplicate(message) {
+		pc.mu.RUnlock()
+		log.Printf("重复消息已丢弃: %s", message.ID)
+		return nil
 	}
+	pc.mu.RUnlock()
 
-	log.Printf("消息已写入延迟文件: %s", message.ID)
+	// 入队可能等待空位，不持有锁，避免阻塞发送协程
+	if err := queue.Enqueue(&base.QueuedMessage{Message: message, Options: options}); err != nil {
+		pc.rateLimiter.Forget(message)
+		return fmt.Errorf("消息入队失败: %w", err)
+	}
+
+	log.Printf("消息已入队: %s (优先级%d)", message.ID, options.Priority)
 	return nil
 }
 
-// FlushQueue 刷新队列（现在处理延迟文件）
+// FlushQueue 立即按优先级发送队列中的全部消息，并合并发送延迟消息
 func (pc *PushController) FlushQueue() error {
 	pc.mu.RLock()
-	defer pc.mu.RUnlock()
+	queue := pc.queue
+	pc.mu.RUnlock()
 
-	if pc.workingManager == nil {
-		return fmt.Errorf("延迟处理器未初始化")
+	if queue == nil {
+		return fmt.Errorf("发送队列未初始化")
+	}
+
+	// 等待发送完成时不持有锁，发送协程需要获取读锁
+	if err := queue.Flush(); err != nil {
+		return fmt.Errorf("刷新发送队列失败: %w", err)
 	}
 
-	return pc.workingManager.SendAllDelayMessages()
+	pc.mu.RLock()
+	workingManager := pc.workingManager
+	pc.mu.RUnlock()
+
+	return workingManager.SendAllDelayMessages()
+}
+
+// sendQueued 发送队列中的消息（由发送协程调用）
+func (pc *PushController) sendQueued(queued *base.QueuedMessage) {
+	pc.mu.RLock()
+	pusher := pc.currentPusher
+	pc.mu.RUnlock()
+
+	if pusher == nil {
+		return
+	}
+	if err := pc.deliver(queued.Message, queued.Options); err != nil {
+		pc.rateLimiter.Forget(queued.Message)
+		log.Printf("队列消息发送失败: %s, %v", queued.Message.ID, err)
+	}
 }
 
 // PushAt 定时推送
@@ -195,7 +394,7 @@ func (pc *PushController) PushAt(message base.Message, options base.PushOptions,
 	}
 
 	// 验证推送选项
-	if err := pc.currentPusher.Validate(options); err != nil {
+	if err := pc.validate(options); err != nil {
 		return fmt.Errorf("推送选项验证失败: %w", err)
 	}
 
@@ -208,16 +407,346 @@ func (pc *PushController) PushAt(message base.Message, options base.PushOptions,
 	return nil
 }
 
+// ListScheduled 按条件列出待发送的定时消息
+func (pc *PushController) ListScheduled(filter base.ScheduledFilter) ([]*base.ScheduledMessage, error) {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+
+	if pc.workingManager == nil {
+		return nil, fmt.Errorf("延迟处理器未初始化")
+	}
+
+	return pc.workingManager.ListScheduledMessages(filter)
+}
+
+// CancelScheduled 取消定时消息
+func (pc *PushController) CancelScheduled(messageID string) error {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+
+	if pc.workingManager == nil {
+		return fmt.Errorf("延迟处理器未初始化")
+	}
+
+	return pc.workingManager.CancelScheduledMessage(messageID)
+}
+
+// Reschedule 修改定时消息的计划发送时间
+func (pc *PushController) Reschedule(messageID string, scheduledAt time.Time) error {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+
+	if pc.workingManager == nil {
+		return fmt.Errorf("延迟处理器未初始化")
+	}
+
+	return pc.workingManager.RescheduleMessage(messageID, scheduledAt)
+}
+
+// PushTemplate 使用模板渲染消息后立即推送，blocks 附加在渲染结果之后
+func (pc *PushController) PushTemplate(appID, templateName string, data interface{}, options base.PushOptions, blocks ...base.ContentBlock) error {
+	pc.mu.RLock()
+	pusher := pc.currentPusher
+	pc.mu.RUnlock()
+
+	if pusher == nil {
+		return fmt.Errorf("推送器未初始化")
+	}
+
+	message, err := pc.renderTemplate(pusher, appID, templateName, data)
+	if err != nil {
+		return err
+	}
+	message.AddBlocks(blocks...)
+
+	return pc.PushNow(*message, options)
+}
+
+// renderTemplate 按推送器名称选择渠道模板渲染消息
+func (pc *PushController) renderTemplate(pusher push_method.IPusher, appID, templateName string, data interface{}) (*base.Message, error) {
+	rendered, err := pc.templates.Render(templateName, pusher.GetName(), data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTemplate, err)
+	}
+
+	message := base.NewMessage(appID, rendered.Title, rendered.Content, rendered.Level)
+	message.SetMetadata("template", templateName)
+	return message, nil
+}
+
+// PushRecurring 按cron规则周期推送消息
+func (pc *PushController) PushRecurring(message base.Message, options base.PushOptions, rule base.RecurringRule) error {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+
+	if pc.recurring == nil {
+		return fmt.Errorf("周期推送管理器未初始化")
+	}
+	if err := pc.validate(options); err != nil {
+		return fmt.Errorf("推送选项验证失败: %w", err)
+	}
+
+	return pc.recurring.Add(&base.RecurringMessage{
+		ID:      message.ID,
+		Message: message,
+		Options: options,
+		Rule:    rule,
+	})
+}
+
+// PushTemplateRecurring 按cron规则周期推送模板消息，每次发送前重新渲染模板，返回周期推送ID
+// 模板注册了数据提供函数时使用其返回的最新数据，否则使用 data
+func (pc *PushController) PushTemplateRecurring(appID, templateName string, data interface{}, options base.PushOptions, rule base.RecurringRule) (string, error) {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+
+	if pc.recurring == nil {
+		return "", fmt.Errorf("周期推送管理器未初始化")
+	}
+	if err := pc.validate(options); err != nil {
+		return "", fmt.Errorf("推送选项验证失败: %w", err)
+	}
+
+	// 先渲染一次，模板有误时立即报错
+	if _, err := pc.renderTemplate(pc.currentPusher, appID, templateName, pc.templateData(templateName, data)); err != nil {
+		return "", err
+	}
+
+	templateData, err := json.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("序列化模板数据失败: %w", err)
+	}
+
+	message := base.NewMessage(appID, templateName, "", base.Normal)
+	recurring := &base.RecurringMessage{
+		ID:           message.ID,
+		Message:      *message,
+		Options:      options,
+		Rule:         rule,
+		Template:     templateName,
+		TemplateData: templateData,
+	}
+	if err := pc.recurring.Add(recurring); err != nil {
+		return "", err
+	}
+	return recurring.ID, nil
+}
+
+// RegisterTemplateData 为模板注册数据提供函数，周期推送每次渲染前调用以获取最新数据
+func (pc *PushController) RegisterTemplateData(templateName string, provider TemplateDataProvider) {
+	pc.templates.SetDataProvider(templateName, provider)
+}
+
+// ListRecurring 列出全部周期推送
+func (pc *PushController) ListRecurring() ([]*base.RecurringMessage, error) {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+
+	if pc.recurring == nil {
+		return nil, fmt.Errorf("周期推送管理器未初始化")
+	}
+
+	return pc.recurring.List()
+}
+
+// CancelRecurring 取消周期推送
+func (pc *PushController) CancelRecurring(id string) error {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+
+	if pc.recurring == nil {
+		return fmt.Errorf("周期推送管理器未初始化")
+	}
+
+	return pc.recurring.Cancel(id)
+}
+
+// templateData 获取模板数据：优先调用注册的数据提供函数，出错时回退到 fallback
+func (pc *PushController) templateData(templateName string, fallback interface{}) interface{} {
+	provider, exists := pc.templates.DataProvider(templateName)
+	if !exists {
+		return fallback
+	}
+	data, err := provider()
+	if err != nil {
+		log.Printf("获取模板数据失败，使用保存的数据: %s, %v", templateName, err)
+		return fallback
+	}
+	return data
+}
+
+// fireRecurring 发送一次周期推送，经过 PushNow 的限流、免打扰、确认等处理
+func (pc *PushController) fireRecurring(recurring *base.RecurringMessage) error {
+	var message *base.Message
+	if recurring.Template != "" {
+		var saved interface{}
+		if len(recurring.TemplateData) > 0 {
+			if err := json.Unmarshal(recurring.TemplateData, &saved); err != nil {
+				return fmt.Errorf("解析模板数据失败: %w", err)
+			}
+		}
+
+		pc.mu.RLock()
+		pusher := pc.currentPusher
+		pc.mu.RUnlock()
+
+		rendered, err := pc.renderTemplate(pusher, recurring.Message.AppID, recurring.Template, pc.templateData(recurring.Template, saved))
+		if err != nil {
+			return err
+		}
+		message = rendered
+	} else {
+		// 每次发送生成新的消息ID，避免历史记录和去重混淆
+		message = base.NewMessage(recurring.Message.AppID, recurring.Message.Title, recurring.Message.Content, recurring.Message.Level)
+		for k, v := range recurring.Message.Metadata {
+			message.SetMetadata(k, v)
+		}
+	}
+	message.SetMetadata("recurring_id", recurring.ID)
+
+	return pc.PushNow(*message, recurring.Options)
+}
+
+// Ack 确认紧急消息
+func (pc *PushController) Ack(token, by string) error {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+
+	if pc.ackManager == nil {
+		return fmt.Errorf("确认管理器未初始化")
+	}
+
+	return pc.ackManager.Ack(token, by)
+}
+
+// AckHandler 返回紧急消息确认接口，可挂载到已有的HTTP服务
+func (pc *PushController) AckHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pc.mu.RLock()
+		ackManager := pc.ackManager
+		pc.mu.RUnlock()
+
+		if ackManager == nil {
+			http.Error(w, "确认管理器未初始化", http.StatusServiceUnavailable)
+			return
+		}
+		ackManager.Handler().ServeHTTP(w, r)
+	})
+}
+
+// RegisterPusher 额外注册内置推送器（用于紧急消息升级等），使用初始化时的配置
+func (pc *PushController) RegisterPusher(method base.PushMethod) error {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	pusher, err := newBuiltinPusher(pc.config, method)
+	if err != nil {
+		return err
+	}
+	pc.applyLayout(pusher)
+	pc.applyAddressBook(pusher, pc.config.AddressBook)
+
+	if err := pc.pushRegistry.Register(method.String(), pusher); err != nil {
+		return fmt.Errorf("注册推送器失败: %w", err)
+	}
+	return nil
+}
+
+// RegisterCustomPusher 额外注册自定义推送器
+func (pc *PushController) RegisterCustomPusher(pusher push_method.IPusher) error {
+	if pusher == nil {
+		return fmt.Errorf("推送器不能为空")
+	}
+
+	pc.mu.RLock()
+	pc.applyAddressBook(pusher, pc.config.AddressBook)
+	pc.mu.RUnlock()
+
+	if err := pc.pushRegistry.Register(pusher.GetName(), pusher); err != nil {
+		return fmt.Errorf("注册推送器失败: %w", err)
+	}
+	return nil
+}
+
+// QueryHistory 查询推送历史记录
+func (pc *PushController) QueryHistory(query base.HistoryQuery) (*base.HistoryResult, error) {
+	pc.mu.RLock()
+	defer pc.mu.RUnlock()
+
+	if pc.historyHandler == nil {
+		return nil, fmt.Errorf("历史记录处理器未初始化")
+	}
+
+	return pc.historyHandler.Query(query)
+}
+
+// validate 验证推送选项：推送器自身的校验，以及接收者必须是通讯录中的联系人或分组
+func (pc *PushController) validate(options base.PushOptions) error {
+	if err := pc.currentPusher.Validate(options); err != nil {
+		return err
+	}
+	return pc.config.AddressBook.Check(options.Receivers)
+}
+
+// applyAddressBook 为支持通讯录的推送器设置通讯录
+func (pc *PushController) applyAddressBook(pusher push_method.IPusher, book base.AddressBookConfig) {
+	if setter, ok := pusher.(push_method.AddressBookSetter); ok {
+		setter.SetAddressBook(book)
+	}
+}
+
+// applyLayout 如果模板目录中存在推送器的排版模板，应用到推送器
+func (pc *PushController) applyLayout(pusher push_method.IPusher) {
+	setter, ok := pusher.(push_method.LayoutSetter)
+	if !ok {
+		return
+	}
+
+	layout, exists := pc.templates.Layout(pusher.GetName())
+	if !exists {
+		return
+	}
+
+	if err := setter.SetLayoutTemplate(layout); err != nil {
+		log.Printf("应用排版模板失败，使用默认排版: %v", err)
+		return
+	}
+	log.Printf("已应用排版模板: %s", pusher.GetName())
+}
+
 // Stop 停止推送控制器
 func (pc *PushController) Stop() {
+	// 周期推送、发送队列和定时消息发送时会获取读锁，需在加写锁之前停止
+	pc.mu.RLock()
+	recurring := pc.recurring
+	queue := pc.queue
+	workingManager := pc.workingManager
+	pc.mu.RUnlock()
+	if recurring != nil {
+		recurring.Stop()
+	}
+	if queue != nil {
+		queue.Stop()
+	}
+	if workingManager != nil {
+		workingManager.Stop()
+	}
+
 	pc.mu.Lock()
 	defer pc.mu.Unlock()
 
 	close(pc.stopChan)
 
-	// 停止延迟处理器
-	if pc.workingManager != nil {
-		pc.workingManager.Stop()
+	// 停止确认管理器
+	if pc.ackManager != nil {
+		pc.ackManager.Stop()
+	}
+
+	// 关闭消息存储
+	if pc.store != nil {
+		if err := pc.store.Close(); err != nil {
+			log.Printf("关闭消息存储失败: %v", err)
+		}
 	}
 
 	pc.wg.Wait()
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"task_scheduler/pkg/pushAPI/base"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// defaultDigestCron 默认摘要发送周期：每4小时整点
+const defaultDigestCron = "0 0 */4 * * *"
+
+// digestCronParser 摘要cron表达式解析器（秒字段可选）
+var digestCronParser = cron.NewParser(
+	cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+// clockTime 一天中的时刻
+type clockTime struct {
+	hour   int
+	minute int
+}
+
+// minutes 转换为当天的分钟数
+func (c clockTime) minutes() int {
+	return c.hour*60 + c.minute
+}
+
+// parseClockTime 解析 HH:MM 格式时刻
+func parseClockTime(s string) (clockTime, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return clockTime{}, fmt.Errorf("无效的时刻 %q，格式应为HH:MM", s)
+	}
+	return clockTime{hour: t.Hour(), minute: t.Minute()}, nil
+}
+
+// timesSchedule 每天固定时刻触发的调度
+type timesSchedule []clockTime
+
+// Next 返回 t 之后的下一个触发时刻
+func (s timesSchedule) Next(t time.Time) time.Time {
+	for dayOffset := 0; dayOffset <= 1; dayOffset++ {
+		day := t.AddDate(0, 0, dayOffset)
+		for _, c := range s {
+			candidate := time.Date(day.Year(), day.Month(), day.Day(), c.hour, c.minute, 0, 0, t.Location())
+			if candidate.After(t) {
+				return candidate
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// parseDigestSchedule 解析摘要发送调度：优先使用固定时刻列表，其次cron表达式，默认每4小时
+func parseDigestSchedule(cfg base.DigestConfig) (cron.Schedule, error) {
+	if len(cfg.Times) > 0 {
+		schedule := make(timesSchedule, 0, len(cfg.Times))
+		for _, s := range cfg.Times {
+			c, err := parseClockTime(s)
+			if err != nil {
+				return nil, fmt.Errorf("解析摘要发送时刻失败: %w", err)
+			}
+			schedule = append(schedule, c)
+		}
+		sort.Slice(schedule, func(i, j int) bool {
+			return schedule[i].minutes() < schedule[j].minutes()
+		})
+		return schedule, nil
+	}
+
+	spec := cfg.Cron
+	if spec == "" {
+		spec = defaultDigestCron
+	}
+	schedule, err := digestCronParser.Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("解析摘要cron表达式失败: %w", err)
+	}
+	return schedule, nil
+}
+
+// quietHours 免打扰时段，支持跨零点（如 22:00-07:00）
+type quietHours struct {
+	start clockTime
+	end   clockTime
+}
+
+// parseQuietHours 解析免打扰时段，未配置时返回nil
+func parseQuietHours(cfg base.QuietHoursConfig) (*quietHours, error) {
+	if cfg.Start == "" && cfg.End == "" {
+		return nil, nil
+	}
+	start, err := parseClockTime(cfg.Start)
+	if err != nil {
+		return nil, fmt.Errorf("解析免打扰开始时间失败: %w", err)
+	}
+	end, err := parseClockTime(cfg.End)
+	if err != nil {
+		return nil, fmt.Errorf("解析免打扰结束时间失败: %w", err)
+	}
+	return &quietHours{start: start, end: end}, nil
+}
+
+// contains 判断时间是否处于免打扰时段
+func (q *quietHours) contains(t time.Time) bool {
+	if q == nil {
+		return false
+	}
+	now := t.Hour()*60 + t.Minute()
+	start, end := q.start.minutes(), q.end.minutes()
+	if start <= end {
+		return now >= start && now < end
+	}
+	// 跨零点
+	return now >= start || now < end
+}
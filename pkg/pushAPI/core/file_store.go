@@ -0,0 +1,621 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"task_scheduler/pkg/pushAPI/base"
+	"time"
+)
+
+// FileStore 基于JSON文件的消息存储
+// 文件布局：
+//   - {workingDir}/delay_YYYYMMDD_HHMM.json   按摘要时间分组的延迟消息
+//   - {workingDir}/scheduled_YYYYMMDD_HH.json 按4小时分段的定时消息
+//   - {workingDir}/acks.json                  紧急消息确认记录
+//   - {workingDir}/recurring.json             周期推送
+//   - {workingDir}/queue.json                 发送队列
+//   - {historyDir}/{success_send|failed_send}_YYYYMM.json 按月份组织的历史记录
+//
+// 每次写入都会重写整个文件（先写临时文件再重命名，保证不会写出半个文件），
+// 数据量较大时建议使用 BoltStore。
+type FileStore struct {
+	workingDir string
+	historyDir string
+	mu         sync.Mutex
+}
+
+// NewFileStore 创建文件消息存储
+func NewFileStore(workingDir, historyDir string) *FileStore {
+	return &FileStore{
+		workingDir: workingDir,
+		historyDir: historyDir,
+	}
+}
+
+// AppendDelay 追加延迟消息
+func (fs *FileStore) AppendDelay(digestAt time.Time, msg *base.DelayMessage) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	filePath := filepath.Join(fs.workingDir, fmt.Sprintf("delay_%s.json", digestAt.Format("20060102_1504")))
+	var messages []*base.DelayMessage
+	if err := readJSONFile(filePath, &messages); err != nil {
+		return fmt.Errorf("读取延迟消息失败: %w", err)
+	}
+
+	messages = append(messages, msg)
+	if err := writeJSONFile(filePath, messages); err != nil {
+		return fmt.Errorf("写入延迟消息失败: %w", err)
+	}
+	return nil
+}
+
+// ListDelay 获取全部延迟消息
+func (fs *FileStore) ListDelay() ([]*base.DelayMessage, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	files, err := filepath.Glob(filepath.Join(fs.workingDir, "delay_*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("查找延迟消息文件失败: %w", err)
+	}
+
+	var all []*base.DelayMessage
+	for _, file := range files {
+		var messages []*base.DelayMessage
+		if err := readJSONFile(file, &messages); err != nil {
+			return nil, fmt.Errorf("读取延迟消息文件失败 %s: %w", file, err)
+		}
+		all = append(all, messages...)
+	}
+	return all, nil
+}
+
+// ClearDelay 删除全部延迟消息文件
+func (fs *FileStore) ClearDelay() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	files, err := filepath.Glob(filepath.Join(fs.workingDir, "delay_*.json"))
+	if err != nil {
+		return fmt.Errorf("查找延迟消息文件失败: %w", err)
+	}
+	for _, file := range files {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("删除延迟消息文件失败 %s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// AppendScheduled 追加定时消息
+func (fs *FileStore) AppendScheduled(msg *base.ScheduledMessage) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	filePath := fs.scheduledFileName(msg.ScheduledAt)
+	var messages []*base.ScheduledMessage
+	if err := readJSONFile(filePath, &messages); err != nil {
+		return fmt.Errorf("读取定时消息失败: %w", err)
+	}
+
+	messages = append(messages, msg)
+	if err := writeJSONFile(filePath, messages); err != nil {
+		return fmt.Errorf("写入定时消息失败: %w", err)
+	}
+	return nil
+}
+
+// ListScheduled 获取全部定时消息
+func (fs *FileStore) ListScheduled() ([]*base.ScheduledMessage, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	files, err := filepath.Glob(filepath.Join(fs.workingDir, "scheduled_*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("查找定时消息文件失败: %w", err)
+	}
+
+	var all []*base.ScheduledMessage
+	for _, file := range files {
+		var messages []*base.ScheduledMessage
+		if err := readJSONFile(file, &messages); err != nil {
+			return nil, fmt.Errorf("读取定时消息文件失败 %s: %w", file, err)
+		}
+		all = append(all, messages...)
+	}
+	return all, nil
+}
+
+// RemoveScheduled 按消息ID删除定时消息
+func (fs *FileStore) RemoveScheduled(messageIDs ...string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	ids := make(map[string]bool, len(messageIDs))
+	for _, id := range messageIDs {
+		ids[id] = true
+	}
+
+	files, err := filepath.Glob(filepath.Join(fs.workingDir, "scheduled_*.json"))
+	if err != nil {
+		return fmt.Errorf("查找定时消息文件失败: %w", err)
+	}
+
+	for _, file := range files {
+		var messages []*base.ScheduledMessage
+		if err := readJSONFile(file, &messages); err != nil {
+			return fmt.Errorf("读取定时消息文件失败 %s: %w", file, err)
+		}
+
+		remaining := make([]*base.ScheduledMessage, 0, len(messages))
+		for _, msg := range messages {
+			if !ids[msg.Message.ID] {
+				remaining = append(remaining, msg)
+			}
+		}
+		if len(remaining) == len(messages) {
+			continue
+		}
+		if err := writeJSONFile(file, remaining); err != nil {
+			return fmt.Errorf("更新定时消息文件失败 %s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// AppendHistory 追加历史记录
+func (fs *FileStore) AppendHistory(recordType string, record *base.HistoryRecord) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	filePath := fs.historyFileName(recordType, record.Timestamp.Format("200601"))
+	var records []*base.HistoryRecord
+	if err := readJSONFile(filePath, &records); err != nil {
+		return fmt.Errorf("读取历史记录失败: %w", err)
+	}
+
+	records = append(records, record)
+	if err := writeJSONFile(filePath, records); err != nil {
+		return fmt.Errorf("写入历史记录失败: %w", err)
+	}
+	return nil
+}
+
+// ListHistory 获取指定类型和月份的历史记录
+func (fs *FileStore) ListHistory(recordType, yearMonth string) ([]*base.HistoryRecord, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	records := []*base.HistoryRecord{}
+	if err := readJSONFile(fs.historyFileName(recordType, yearMonth), &records); err != nil {
+		return nil, fmt.Errorf("读取历史记录失败: %w", err)
+	}
+	fillHistoryStatus(recordType, records...)
+	return records, nil
+}
+
+// FindHistory 按消息ID查找历史记录（需扫描全部历史文件）
+func (fs *FileStore) FindHistory(messageID string) ([]*base.HistoryRecord, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	files, err := fs.historyFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	var found []*base.HistoryRecord
+	for _, file := range files {
+		var records []*base.HistoryRecord
+		if err := readJSONFile(filepath.Join(fs.historyDir, file.name), &records); err != nil {
+			return nil, fmt.Errorf("读取历史记录文件失败 %s: %w", file.name, err)
+		}
+		fillHistoryStatus(file.recordType, records...)
+		for _, record := range records {
+			if record.MessageID == messageID {
+				found = append(found, record)
+			}
+		}
+	}
+	return found, nil
+}
+
+// HistoryMonths 获取有历史记录的月份
+func (fs *FileStore) HistoryMonths() ([]string, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	files, err := fs.historyFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	months := []string{}
+	for _, file := range files {
+		if !seen[file.month] {
+			seen[file.month] = true
+			months = append(months, file.month)
+		}
+	}
+	sort.Strings(months)
+	return months, nil
+}
+
+// DeleteHistoryBefore 删除早于指定月份的历史记录文件
+func (fs *FileStore) DeleteHistoryBefore(yearMonth string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	files, err := fs.historyFiles()
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if file.month < yearMonth {
+			if err := os.Remove(filepath.Join(fs.historyDir, file.name)); err != nil {
+				return fmt.Errorf("删除旧历史记录文件失败: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// PutAck 保存确认记录
+func (fs *FileStore) PutAck(record *base.AckRecord) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	records, err := fs.readAcks()
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+	for i, existing := range records {
+		if existing.Token == record.Token {
+			records[i] = record
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		records = append(records, record)
+	}
+	return fs.writeAcks(records)
+}
+
+// GetAck 按令牌获取确认记录
+func (fs *FileStore) GetAck(token string) (*base.AckRecord, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	records, err := fs.readAcks()
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if record.Token == token {
+			return record, nil
+		}
+	}
+	return nil, ErrAckNotFound
+}
+
+// ListAcks 获取全部确认记录
+func (fs *FileStore) ListAcks() ([]*base.AckRecord, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	return fs.readAcks()
+}
+
+// DeleteAck 删除确认记录
+func (fs *FileStore) DeleteAck(token string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	records, err := fs.readAcks()
+	if err != nil {
+		return err
+	}
+
+	remaining := make([]*base.AckRecord, 0, len(records))
+	for _, record := range records {
+		if record.Token != token {
+			remaining = append(remaining, record)
+		}
+	}
+	if len(remaining) == len(records) {
+		return nil
+	}
+	return fs.writeAcks(remaining)
+}
+
+// readAcks 读取确认记录文件
+func (fs *FileStore) readAcks() ([]*base.AckRecord, error) {
+	var records []*base.AckRecord
+	if err := readJSONFile(filepath.Join(fs.workingDir, "acks.json"), &records); err != nil {
+		return nil, fmt.Errorf("读取确认记录失败: %w", err)
+	}
+	return records, nil
+}
+
+// writeAcks 写入确认记录文件
+func (fs *FileStore) writeAcks(records []*base.AckRecord) error {
+	if err := writeJSONFile(filepath.Join(fs.workingDir, "acks.json"), records); err != nil {
+		return fmt.Errorf("写入确认记录失败: %w", err)
+	}
+	return nil
+}
+
+// PutRecurring 保存周期推送
+func (fs *FileStore) PutRecurring(msg *base.RecurringMessage) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	messages, err := fs.readRecurring()
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+	for i, existing := range messages {
+		if existing.ID == msg.ID {
+			messages[i] = msg
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		messages = append(messages, msg)
+	}
+	return fs.writeRecurring(messages)
+}
+
+// ListRecurring 获取全部周期推送
+func (fs *FileStore) ListRecurring() ([]*base.RecurringMessage, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	return fs.readRecurring()
+}
+
+// DeleteRecurring 删除周期推送
+func (fs *FileStore) DeleteRecurring(id string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	messages, err := fs.readRecurring()
+	if err != nil {
+		return err
+	}
+
+	remaining := make([]*base.RecurringMessage, 0, len(messages))
+	for _, msg := range messages {
+		if msg.ID != id {
+			remaining = append(remaining, msg)
+		}
+	}
+	if len(remaining) == len(messages) {
+		return ErrRecurringNotFound
+	}
+	return fs.writeRecurring(remaining)
+}
+
+// readRecurring 读取周期推送文件
+func (fs *FileStore) readRecurring() ([]*base.RecurringMessage, error) {
+	var messages []*base.RecurringMessage
+	if err := readJSONFile(filepath.Join(fs.workingDir, "recurring.json"), &messages); err != nil {
+		return nil, fmt.Errorf("读取周期推送失败: %w", err)
+	}
+	return messages, nil
+}
+
+// writeRecurring 写入周期推送文件
+func (fs *FileStore) writeRecurring(messages []*base.RecurringMessage) error {
+	if err := writeJSONFile(filepath.Join(fs.workingDir, "recurring.json"), messages); err != nil {
+		return fmt.Errorf("写入周期推送失败: %w", err)
+	}
+	return nil
+}
+
+// PutQueued 保存发送队列中的消息
+func (fs *FileStore) PutQueued(msg *base.QueuedMessage) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	messages, err := fs.readQueued()
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+	for i, existing := range messages {
+		if existing.Message.ID == msg.Message.ID {
+			messages[i] = msg
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		messages = append(messages, msg)
+	}
+	return fs.writeQueued(messages)
+}
+
+// ListQueued 获取发送队列中的全部消息
+func (fs *FileStore) ListQueued() ([]*base.QueuedMessage, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	return fs.readQueued()
+}
+
+// RemoveQueued 按消息ID删除发送队列中的消息
+func (fs *FileStore) RemoveQueued(messageIDs ...string) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	messages, err := fs.readQueued()
+	if err != nil {
+		return err
+	}
+
+	removeSet := make(map[string]bool, len(messageIDs))
+	for _, id := range messageIDs {
+		removeSet[id] = true
+	}
+	remaining := make([]*base.QueuedMessage, 0, len(messages))
+	for _, msg := range messages {
+		if !removeSet[msg.Message.ID] {
+			remaining = append(remaining, msg)
+		}
+	}
+	if len(remaining) == len(messages) {
+		return nil
+	}
+	return fs.writeQueued(remaining)
+}
+
+// readQueued 读取发送队列文件
+func (fs *FileStore) readQueued() ([]*base.QueuedMessage, error) {
+	var messages []*base.QueuedMessage
+	if err := readJSONFile(filepath.Join(fs.workingDir, "queue.json"), &messages); err != nil {
+		return nil, fmt.Errorf("读取发送队列失败: %w", err)
+	}
+	return messages, nil
+}
+
+// writeQueued 写入发送队列文件
+func (fs *FileStore) writeQueued(messages []*base.QueuedMessage) error {
+	if err := writeJSONFile(filepath.Join(fs.workingDir, "queue.json"), messages); err != nil {
+		return fmt.Errorf("写入发送队列失败: %w", err)
+	}
+	return nil
+}
+
+// Close 文件存储无需关闭
+func (fs *FileStore) Close() error {
+	return nil
+}
+
+// scheduledFileName 获取定时消息文件名（按4小时分段）
+func (fs *FileStore) scheduledFileName(t time.Time) string {
+	hour := t.Hour()
+	slotStart := hour - (hour % 4)
+	dateStr := t.Format("20060102")
+	timeStr := fmt.Sprintf("%02d", slotStart)
+	return filepath.Join(fs.workingDir, fmt.Sprintf("scheduled_%s_%s.json", dateStr, timeStr))
+}
+
+// historyFileName 获取历史记录文件名
+func (fs *FileStore) historyFileName(recordType, yearMonth string) string {
+	return filepath.Join(fs.historyDir, fmt.Sprintf("%s_%s.json", recordType, yearMonth))
+}
+
+// historyFile 历史记录文件
+type historyFile struct {
+	name       string
+	recordType string
+	month      string
+}
+
+// historyFiles 列出历史记录目录中的全部历史文件
+// 文件名格式：success_send_202401.json 或 failed_send_202401.json
+func (fs *FileStore) historyFiles() ([]historyFile, error) {
+	entries, err := os.ReadDir(fs.historyDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取历史记录目录失败: %w", err)
+	}
+
+	var files []historyFile
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := entry.Name()
+		for _, recordType := range []string{historySuccess, historyFailed} {
+			month, ok := strings.CutPrefix(name, recordType+"_")
+			if !ok {
+				continue
+			}
+			month, ok = strings.CutSuffix(month, ".json")
+			if !ok {
+				continue
+			}
+			if _, err := time.Parse("200601", month); err != nil {
+				continue
+			}
+			files = append(files, historyFile{name: name, recordType: recordType, month: month})
+		}
+	}
+	return files, nil
+}
+
+// readJSONFile 读取JSON文件，文件不存在时保持v不变
+func readJSONFile(filePath string, v interface{}) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("读取文件失败: %w", err)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("解析文件失败: %w", err)
+	}
+	return nil
+}
+
+// writeJSONFile 原子写入JSON文件：先写入同目录临时文件并落盘，再重命名覆盖
+func writeJSONFile(filePath string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化失败: %w", err)
+	}
+
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(filePath)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("写入临时文件失败: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("同步临时文件失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("关闭临时文件失败: %w", err)
+	}
+	if err := os.Chmod(tmpPath, 0644); err != nil {
+		return fmt.Errorf("设置文件权限失败: %w", err)
+	}
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		return fmt.Errorf("重命名文件失败: %w", err)
+	}
+	return nil
+}
@@ -1,197 +1,256 @@
 package core
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
-	"sync"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
 	"task_scheduler/pkg/pushAPI/base"
 	"time"
 )
 
 // HistoryHandler 历史消息记录处理器
 type HistoryHandler struct {
-	historyDir string
-	mu         sync.RWMutex
+	store MessageStore
 }
 
 // NewHistoryHandler 创建历史消息记录处理器
-func NewHistoryHandler(historyDir string) *HistoryHandler {
+func NewHistoryHandler(store MessageStore) *HistoryHandler {
 	return &HistoryHandler{
-		historyDir: historyDir,
+		store: store,
 	}
 }
 
 // RecordSuccess 记录成功发送的消息
 func (h *HistoryHandler) RecordSuccess(msg base.Message, pusherName string, options base.PushOptions) error {
 	record := base.NewSuccessHistoryRecord(msg, pusherName, options)
-	return h.writeRecord(record, "success_send")
+	return h.store.AppendHistory(historySuccess, record)
 }
 
 // RecordFailure 记录发送失败的消息
 func (h *HistoryHandler) RecordFailure(msg base.Message, pusherName string, options base.PushOptions, errorReason string) error {
 	record := base.NewFailedHistoryRecord(msg, pusherName, options, errorReason)
-	return h.writeRecord(record, "failed_send")
-}
-
-// writeRecord 写入历史记录到文件
-func (h *HistoryHandler) writeRecord(record *base.HistoryRecord, recordType string) error {
-	h.mu.Lock()
-	defer h.mu.Unlock()
-
-	// 创建历史记录目录
-	if err := os.MkdirAll(h.historyDir, 0755); err != nil {
-		return fmt.Errorf("创建历史记录目录失败: %w", err)
-	}
-
-	// 生成文件名：按月份组织
-	monthStr := record.Timestamp.Format("200601") // YYYYMM格式
-	filename := fmt.Sprintf("%s_%s.json", recordType, monthStr)
-	filePath := filepath.Join(h.historyDir, filename)
-
-	// 读取现有记录
-	var records []*base.HistoryRecord
-	if data, err := os.ReadFile(filePath); err == nil {
-		if err := json.Unmarshal(data, &records); err != nil {
-			// 如果解析失败，创建新的记录数组
-			records = []*base.HistoryRecord{}
-		}
-	}
-
-	// 添加新记录
-	records = append(records, record)
-
-	// 写入文件
-	data, err := json.MarshalIndent(records, "", "  ")
-	if err != nil {
-		return fmt.Errorf("序列化历史记录失败: %w", err)
-	}
-
-	if err := os.WriteFile(filePath, data, 0644); err != nil {
-		return fmt.Errorf("写入历史记录文件失败: %w", err)
-	}
-
-	return nil
+	return h.store.AppendHistory(historyFailed, record)
 }
 
 // GetSuccessRecords 获取成功发送记录
 func (h *HistoryHandler) GetSuccessRecords(yearMonth string) ([]*base.HistoryRecord, error) {
-	return h.getRecords("success_send", yearMonth)
+	return h.store.ListHistory(historySuccess, yearMonth)
 }
 
 // GetFailedRecords 获取失败发送记录
 func (h *HistoryHandler) GetFailedRecords(yearMonth string) ([]*base.HistoryRecord, error) {
-	return h.getRecords("failed_send", yearMonth)
+	return h.store.ListHistory(historyFailed, yearMonth)
 }
 
-// getRecords 获取指定类型和月份的历史记录
-func (h *HistoryHandler) getRecords(recordType, yearMonth string) ([]*base.HistoryRecord, error) {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
-
-	filename := fmt.Sprintf("%s_%s.json", recordType, yearMonth)
-	filePath := filepath.Join(h.historyDir, filename)
+// FindByMessageID 按消息ID查找历史记录
+func (h *HistoryHandler) FindByMessageID(messageID string) ([]*base.HistoryRecord, error) {
+	return h.store.FindHistory(messageID)
+}
 
-	data, err := os.ReadFile(filePath)
+// Query 按条件查询历史记录，结果按时间倒序并分页
+func (h *HistoryHandler) Query(query base.HistoryQuery) (*base.HistoryResult, error) {
+	candidates, err := h.candidates(query)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return []*base.HistoryRecord{}, nil
-		}
-		return nil, fmt.Errorf("读取历史记录文件失败: %w", err)
+		return nil, err
 	}
 
-	var records []*base.HistoryRecord
-	if err := json.Unmarshal(data, &records); err != nil {
-		return nil, fmt.Errorf("解析历史记录失败: %w", err)
+	keyword := strings.ToLower(query.Keyword)
+	var matched []*base.HistoryRecord
+	for _, record := range candidates {
+		if matchHistory(record, query, keyword) {
+			matched = append(matched, record)
+		}
 	}
 
-	return records, nil
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].Timestamp.After(matched[j].Timestamp)
+	})
+
+	result := &base.HistoryResult{Records: []*base.HistoryRecord{}, Total: len(matched)}
+	if query.Offset >= len(matched) {
+		return result, nil
+	}
+	end := len(matched)
+	if query.Limit > 0 && query.Offset+query.Limit < end {
+		end = query.Offset + query.Limit
+	}
+	result.Records = matched[query.Offset:end]
+	return result, nil
 }
 
-// GetAvailableMonths 获取可用的历史记录月份
-func (h *HistoryHandler) GetAvailableMonths() ([]string, error) {
-	h.mu.RLock()
-	defer h.mu.RUnlock()
+// candidates 加载可能满足条件的记录：指定消息ID时走索引，否则只读取时间范围内的月份
+func (h *HistoryHandler) candidates(query base.HistoryQuery) ([]*base.HistoryRecord, error) {
+	if query.MessageID != "" {
+		records, err := h.store.FindHistory(query.MessageID)
+		if err != nil {
+			return nil, fmt.Errorf("按消息ID查询历史记录失败: %w", err)
+		}
+		return records, nil
+	}
 
-	entries, err := os.ReadDir(h.historyDir)
+	months, err := h.store.HistoryMonths()
 	if err != nil {
-		if os.IsNotExist(err) {
-			return []string{}, nil
-		}
-		return nil, fmt.Errorf("读取历史记录目录失败: %w", err)
+		return nil, fmt.Errorf("获取历史记录月份失败: %w", err)
+	}
+
+	recordTypes := []string{historySuccess, historyFailed}
+	switch strings.ToLower(query.Status) {
+	case base.StatusSuccess.String():
+		recordTypes = []string{historySuccess}
+	case base.StatusFailed.String():
+		recordTypes = []string{historyFailed}
 	}
 
-	months := make(map[string]bool)
-	for _, entry := range entries {
-		if entry.IsDir() {
+	var records []*base.HistoryRecord
+	for _, month := range months {
+		if !query.Since.IsZero() && month < query.Since.Local().Format("200601") {
 			continue
 		}
-
-		// 解析文件名获取月份
-		// 格式：success_send_202401.json 或 failed_send_202401.json
-		name := entry.Name()
-		if len(name) >= 15 {
-			var month string
-			if name[:12] == "success_send_" {
-				month = name[12:18] // success_send_ 后面取6位
-			} else if name[:11] == "failed_send_" {
-				month = name[11:17] // failed_send_ 后面取6位
-			}
-			if month != "" {
-				months[month] = true
+		if !query.Until.IsZero() && month > query.Until.Local().Format("200601") {
+			continue
+		}
+		for _, recordType := range recordTypes {
+			monthRecords, err := h.store.ListHistory(recordType, month)
+			if err != nil {
+				return nil, fmt.Errorf("读取%s历史记录失败: %w", month, err)
 			}
+			records = append(records, monthRecords...)
 		}
 	}
+	return records, nil
+}
 
-	// 转换为切片
-	result := make([]string, 0, len(months))
-	for month := range months {
-		result = append(result, month)
+// matchHistory 判断记录是否满足查询条件，keyword 需已转为小写
+func matchHistory(record *base.HistoryRecord, query base.HistoryQuery, keyword string) bool {
+	if query.AppID != "" && record.AppID != query.AppID {
+		return false
 	}
+	if query.PusherName != "" && record.PusherName != query.PusherName {
+		return false
+	}
+	if query.Level != "" && !strings.EqualFold(record.Level, query.Level) {
+		return false
+	}
+	if query.Status != "" && !strings.EqualFold(record.Status, query.Status) {
+		return false
+	}
+	if query.MessageID != "" && record.MessageID != query.MessageID {
+		return false
+	}
+	if !query.Since.IsZero() && record.Timestamp.Before(query.Since) {
+		return false
+	}
+	if !query.Until.IsZero() && !record.Timestamp.Before(query.Until) {
+		return false
+	}
+	if keyword != "" &&
+		!strings.Contains(strings.ToLower(record.Title), keyword) &&
+		!strings.Contains(strings.ToLower(record.Content), keyword) {
+		return false
+	}
+	return true
+}
 
-	return result, nil
+// GetAvailableMonths 获取可用的历史记录月份
+func (h *HistoryHandler) GetAvailableMonths() ([]string, error) {
+	return h.store.HistoryMonths()
 }
 
-// CleanupOldRecords 清理旧的历史记录（保留指定月数）
-func (h *HistoryHandler) CleanupOldRecords(keepMonths int) error {
-	h.mu.Lock()
-	defer h.mu.Unlock()
+// QueryHistory 查询历史记录，无需初始化推送器（用于命令行等场景）
+// 配置了确认接口时优先请求运行中实例的 /history（运行中的实例持有存储的文件锁），
+// 接口无法连接（没有运行中的实例）时直接打开配置中的消息存储查询
+func QueryHistory(cfg base.PushConfig, query base.HistoryQuery) (*base.HistoryResult, error) {
+	if historyURL := localInstanceURL(cfg.Ack.ListenAddr, "/history"); historyURL != "" {
+		result, err := queryHistoryRemote(historyURL, query)
+		if !errors.Is(err, errInstanceUnreachable) {
+			return result, err
+		}
+		log.Printf("历史记录接口无法连接，直接读取消息存储: %v", err)
+	}
 
-	entries, err := os.ReadDir(h.historyDir)
+	store, err := NewMessageStore(cfg)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil
-		}
-		return fmt.Errorf("读取历史记录目录失败: %w", err)
+		return nil, fmt.Errorf("打开消息存储失败（调度器运行中时需配置 ack.listen_addr）: %w", err)
 	}
+	defer store.Close()
 
-	cutoffTime := time.Now().AddDate(0, -keepMonths, 0)
-	cutoffMonth := cutoffTime.Format("200601")
+	return NewHistoryHandler(store).Query(query)
+}
 
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
+// Handler 返回历史记录查询接口，POST 请求体为 JSON 格式的 HistoryQuery，返回 HistoryResult
+// 历史记录可能包含敏感内容，只接受本机直接发起的请求，经反向代理转发的请求一律拒绝
+func (h *HistoryHandler) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "不支持的请求方法", http.StatusMethodNotAllowed)
+			return
+		}
+		if !localRequest(r) {
+			http.Error(w, "只允许本机查询历史记录", http.StatusForbidden)
+			return
 		}
 
-		name := entry.Name()
-		if len(name) >= 15 {
-			var month string
-			if name[:12] == "success_send_" {
-				month = name[12:18] // success_send_ 后面取6位
-			} else if name[:11] == "failed_send_" {
-				month = name[11:17] // failed_send_ 后面取6位
-			}
-
-			// 如果月份早于截止时间，删除文件
-			if month != "" && month < cutoffMonth {
-				filePath := filepath.Join(h.historyDir, name)
-				if err := os.Remove(filePath); err != nil {
-					return fmt.Errorf("删除旧历史记录文件失败: %w", err)
-				}
-			}
+		var query base.HistoryQuery
+		if err := json.NewDecoder(io.LimitReader(r.Body, 64<<10)).Decode(&query); err != nil {
+			http.Error(w, "请求格式错误", http.StatusBadRequest)
+			return
+		}
+		result, err := h.Query(query)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
 		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(w).Encode(result)
+	})
+}
+
+// localRequest 请求是否由本机直接发起（来源为回环地址且没有经过代理转发）
+func localRequest(r *http.Request) bool {
+	if r.Header.Get("X-Forwarded-For") != "" || r.Header.Get("Forwarded") != "" {
+		return false
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// queryHistoryRemote 通过运行中实例的 /history 查询历史记录，连接失败时返回 errInstanceUnreachable
+func queryHistoryRemote(historyURL string, query base.HistoryQuery) (*base.HistoryResult, error) {
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, fmt.Errorf("序列化查询条件失败: %w", err)
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Post(historyURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errInstanceUnreachable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("历史记录接口返回状态码 %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
 	}
+	var result base.HistoryResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("解析历史记录失败: %w", err)
+	}
+	return &result, nil
+}
 
-	return nil
+// CleanupOldRecords 清理旧的历史记录（保留指定月数）
+func (h *HistoryHandler) CleanupOldRecords(keepMonths int) error {
+	cutoffMonth := time.Now().AddDate(0, -keepMonths, 0).Format("200601")
+	return h.store.DeleteHistoryBefore(cutoffMonth)
 }
@@ -0,0 +1,163 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"task_scheduler/pkg/pushAPI/base"
+	"time"
+)
+
+// dedupMetadataKey 显式指定去重键的元数据字段
+const dedupMetadataKey = "dedup_key"
+
+// tokenBucket 令牌桶
+type tokenBucket struct {
+	capacity float64   // 桶容量
+	rate     float64   // 每秒补充的令牌数
+	tokens   float64   // 当前令牌数
+	last     time.Time // 上次补充时间
+}
+
+// newTokenBucket 创建令牌桶，interval 内最多允许 limit 次
+func newTokenBucket(limit int, interval time.Duration, now time.Time) *tokenBucket {
+	return &tokenBucket{
+		capacity: float64(limit),
+		rate:     float64(limit) / interval.Seconds(),
+		tokens:   float64(limit),
+		last:     now,
+	}
+}
+
+// refill 按经过的时间补充令牌
+func (b *tokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed > 0 {
+		b.tokens += elapsed * b.rate
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+		b.last = now
+	}
+}
+
+// RateLimiter 推送限流与去重
+type RateLimiter struct {
+	config        base.RateLimitConfig
+	pusherBuckets map[string]*tokenBucket
+	appBuckets    map[string]*tokenBucket
+	seen          map[string]time.Time // 去重键 -> 首次出现时间
+	mu            sync.Mutex
+}
+
+// NewRateLimiter 创建推送限流器
+func NewRateLimiter(cfg base.RateLimitConfig) *RateLimiter {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+	return &RateLimiter{
+		config:        cfg,
+		pusherBuckets: make(map[string]*tokenBucket),
+		appBuckets:    make(map[string]*tokenBucket),
+		seen:          make(map[string]time.Time),
+	}
+}
+
+// IsDuplicate 判断消息是否在去重窗口内重复出现，首次出现时记录（发送前占位，避免并发重复发送）
+// 发送失败时需调用 Forget 取消记录，否则重发会被当作重复消息丢弃
+func (rl *RateLimiter) IsDuplicate(msg base.Message) bool {
+	if rl.config.DedupWindow <= 0 {
+		return false
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	for key, seenAt := range rl.seen {
+		if now.Sub(seenAt) >= rl.config.DedupWindow {
+			delete(rl.seen, key)
+		}
+	}
+
+	key := dedupKey(msg)
+	if _, exists := rl.seen[key]; exists {
+		return true
+	}
+	rl.seen[key] = now
+	return false
+}
+
+// Forget 取消消息的去重记录，用于发送失败后允许重发
+func (rl *RateLimiter) Forget(msg base.Message) {
+	if rl.config.DedupWindow <= 0 {
+		return
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	delete(rl.seen, dedupKey(msg))
+}
+
+// Allow 判断推送器和AppID是否都还有配额，有配额时同时扣除
+func (rl *RateLimiter) Allow(pusherName, appID string) bool {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	pusherBucket := rl.bucket(rl.pusherBuckets, pusherName, rl.config.PusherLimit, now)
+	appBucket := rl.bucket(rl.appBuckets, appID, rl.config.AppLimit, now)
+
+	// 先检查两个桶，避免只扣除其中一个
+	if (pusherBucket != nil && pusherBucket.tokens < 1) || (appBucket != nil && appBucket.tokens < 1) {
+		return false
+	}
+	if pusherBucket != nil {
+		pusherBucket.tokens--
+	}
+	if appBucket != nil {
+		appBucket.tokens--
+	}
+	return true
+}
+
+// Consume 不检查配额直接扣除（用于不受限流约束的紧急消息）
+func (rl *RateLimiter) Consume(pusherName, appID string) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := time.Now()
+	for _, bucket := range []*tokenBucket{
+		rl.bucket(rl.pusherBuckets, pusherName, rl.config.PusherLimit, now),
+		rl.bucket(rl.appBuckets, appID, rl.config.AppLimit, now),
+	} {
+		if bucket != nil && bucket.tokens >= 1 {
+			bucket.tokens--
+		}
+	}
+}
+
+// bucket 获取或创建令牌桶并补充令牌，limit<=0 表示不限制，返回nil
+func (rl *RateLimiter) bucket(buckets map[string]*tokenBucket, key string, limit int, now time.Time) *tokenBucket {
+	if limit <= 0 {
+		return nil
+	}
+	b, exists := buckets[key]
+	if !exists {
+		b = newTokenBucket(limit, rl.config.Interval, now)
+		buckets[key] = b
+	}
+	b.refill(now)
+	return b
+}
+
+// dedupKey 计算消息去重键：优先使用元数据中的 dedup_key，否则使用内容哈希
+func dedupKey(msg base.Message) string {
+	if value, exists := msg.Metadata[dedupMetadataKey]; exists {
+		return fmt.Sprintf("%s|key|%v", msg.AppID, value)
+	}
+
+	sum := sha256.Sum256([]byte(msg.AppID + "\x00" + msg.Title + "\x00" + msg.Content))
+	return msg.AppID + "|hash|" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,216 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"task_scheduler/pkg/pushAPI/base"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// recurringMaxWait 周期推送检查的最长等待时间，兜底处理存储被外部修改的情况
+const recurringMaxWait = time.Minute
+
+// RecurringManager 周期推送管理器
+// 周期推送保存在消息存储中，重启后继续按原计划发送；停机期间错过的发送时间在启动后补发一次。
+type RecurringManager struct {
+	store    MessageStore
+	fire     func(msg *base.RecurringMessage) error // 发送一次周期推送
+	wake     chan struct{}
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+	mu       sync.Mutex // 串行化发送与增删，避免已取消的周期推送被写回
+}
+
+// NewRecurringManager 创建周期推送管理器
+func NewRecurringManager(store MessageStore, fire func(msg *base.RecurringMessage) error) *RecurringManager {
+	return &RecurringManager{
+		store:    store,
+		fire:     fire,
+		wake:     make(chan struct{}, 1),
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Start 启动周期推送循环
+func (rm *RecurringManager) Start() {
+	rm.wg.Add(1)
+	go rm.loop()
+}
+
+// Stop 停止周期推送循环
+func (rm *RecurringManager) Stop() {
+	close(rm.stopChan)
+	rm.wg.Wait()
+}
+
+// Add 添加周期推送，计算首次发送时间后保存
+func (rm *RecurringManager) Add(msg *base.RecurringMessage) error {
+	schedule, err := parseRecurringRule(msg.Rule)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	msg.CreatedAt = now
+	msg.NextAt = schedule.Next(now)
+	if msg.NextAt.IsZero() || (!msg.Rule.EndAt.IsZero() && msg.NextAt.After(msg.Rule.EndAt)) {
+		return fmt.Errorf("截止时间 %s 之前没有可发送的时间", msg.Rule.EndAt.Format("2006-01-02 15:04:05"))
+	}
+
+	rm.mu.Lock()
+	err = rm.store.PutRecurring(msg)
+	rm.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("保存周期推送失败: %w", err)
+	}
+
+	log.Printf("周期推送已添加: %s (%s)，首次发送 %s", msg.ID, msg.Rule.Cron, msg.NextAt.Format("2006-01-02 15:04:05"))
+	rm.notify()
+	return nil
+}
+
+// List 列出全部周期推送，按下一次发送时间升序
+func (rm *RecurringManager) List() ([]*base.RecurringMessage, error) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	messages, err := rm.store.ListRecurring()
+	if err != nil {
+		return nil, fmt.Errorf("读取周期推送失败: %w", err)
+	}
+	if messages == nil {
+		messages = []*base.RecurringMessage{}
+	}
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].NextAt.Before(messages[j].NextAt)
+	})
+	return messages, nil
+}
+
+// Cancel 取消周期推送
+func (rm *RecurringManager) Cancel(id string) error {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	if err := rm.store.DeleteRecurring(id); err != nil {
+		if errors.Is(err, ErrRecurringNotFound) {
+			return fmt.Errorf("%w: %s", ErrRecurringNotFound, id)
+		}
+		return fmt.Errorf("删除周期推送失败: %w", err)
+	}
+
+	log.Printf("周期推送已取消: %s", id)
+	return nil
+}
+
+// notify 唤醒循环重新计算等待时间
+func (rm *RecurringManager) notify() {
+	select {
+	case rm.wake <- struct{}{}:
+	default:
+	}
+}
+
+// loop 等待到最近一次发送时间，发送到期的周期推送
+func (rm *RecurringManager) loop() {
+	defer rm.wg.Done()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-rm.stopChan:
+			return
+		case <-rm.wake:
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+		case <-timer.C:
+		}
+
+		next, err := rm.processDue(time.Now())
+		if err != nil {
+			log.Printf("处理周期推送失败: %v", err)
+		}
+
+		wait := recurringMaxWait
+		if !next.IsZero() && time.Until(next) < wait {
+			wait = time.Until(next)
+		}
+		timer.Reset(wait)
+	}
+}
+
+// processDue 发送到期的周期推送，返回最近的下一次发送时间（没有周期推送时为零值）
+func (rm *RecurringManager) processDue(now time.Time) (time.Time, error) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+
+	messages, err := rm.store.ListRecurring()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var earliest time.Time
+	for _, msg := range messages {
+		if now.Before(msg.NextAt) {
+			if earliest.IsZero() || msg.NextAt.Before(earliest) {
+				earliest = msg.NextAt
+			}
+			continue
+		}
+
+		schedule, err := parseRecurringRule(msg.Rule)
+		if err != nil {
+			log.Printf("周期推送 %s 规则无效，已删除: %v", msg.ID, err)
+			rm.store.DeleteRecurring(msg.ID)
+			continue
+		}
+
+		if err := rm.fire(msg); err != nil {
+			log.Printf("周期推送发送失败: %s, %v", msg.ID, err)
+		}
+		msg.FiredCount++
+
+		// 从当前时间计算下一次，停机期间错过的多次发送只补发一次
+		msg.NextAt = schedule.Next(now)
+		if (msg.Rule.MaxCount > 0 && msg.FiredCount >= msg.Rule.MaxCount) ||
+			msg.NextAt.IsZero() ||
+			(!msg.Rule.EndAt.IsZero() && msg.NextAt.After(msg.Rule.EndAt)) {
+			log.Printf("周期推送已结束: %s，共发送%d次", msg.ID, msg.FiredCount)
+			if err := rm.store.DeleteRecurring(msg.ID); err != nil {
+				log.Printf("删除周期推送失败: %v", err)
+			}
+			continue
+		}
+
+		if err := rm.store.PutRecurring(msg); err != nil {
+			log.Printf("更新周期推送失败: %v", err)
+		}
+		if earliest.IsZero() || msg.NextAt.Before(earliest) {
+			earliest = msg.NextAt
+		}
+	}
+	return earliest, nil
+}
+
+// parseRecurringRule 校验周期规则并解析cron表达式
+func parseRecurringRule(rule base.RecurringRule) (cron.Schedule, error) {
+	if rule.MaxCount < 0 {
+		return nil, fmt.Errorf("最多发送次数不能为负数: %d", rule.MaxCount)
+	}
+	schedule, err := digestCronParser.Parse(rule.Cron)
+	if err != nil {
+		return nil, fmt.Errorf("cron表达式格式错误 %q: %w", rule.Cron, err)
+	}
+	return schedule, nil
+}
@@ -0,0 +1,61 @@
+package core
+
+import (
+	"errors"
+	"log"
+	"task_scheduler/pkg/pushAPI/base"
+	"task_scheduler/pkg/pushAPI/push_method"
+	"time"
+)
+
+// retryBackoff 重试间隔基数，第n次重试等待 n*retryBackoff
+var retryBackoff = 2 * time.Second
+
+// pushWithRetry 推送消息，失败时按 options.Retry 次数重试
+// 推送前将选项中的接收者填入消息，供推送器通过通讯录解析；不可重试的错误（如webhook返回4xx）不再重试
+// 多目标推送器按目标分别判断和重试，只向失败的目标重发
+func pushWithRetry(pusher push_method.IPusher, msg base.Message, options base.PushOptions) error {
+	msg.Receivers = options.Receivers
+
+	multi, ok := pusher.(push_method.MultiTargetPusher)
+	if !ok {
+		return retryTargets(msg.ID, []string{""}, options.Retry, func(string) error {
+			return pusher.Push(msg)
+		})
+	}
+
+	targets, err := multi.Targets(msg)
+	if err != nil {
+		return err
+	}
+	return retryTargets(msg.ID, targets, options.Retry, func(target string) error {
+		return multi.PushTo(msg, target)
+	})
+}
+
+// retryTargets 向每个目标发送，可重试的失败目标在下一轮重发，返回最终仍失败的目标的错误
+func retryTargets(msgID string, targets []string, retry int, send func(target string) error) error {
+	var errs []error
+	pending := targets
+	for attempt := 0; len(pending) > 0; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * retryBackoff)
+			log.Printf("重试推送消息: %s, 第%d次, %d个目标", msgID, attempt, len(pending))
+		}
+
+		var failed []string
+		for _, target := range pending {
+			err := send(target)
+			if err == nil {
+				continue
+			}
+			if attempt < retry && push_method.IsRetryable(err) {
+				failed = append(failed, target)
+			} else {
+				errs = append(errs, err)
+			}
+		}
+		pending = failed
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,128 @@
+package core
+
+import (
+	"container/heap"
+	"sort"
+	"task_scheduler/pkg/pushAPI/base"
+	"time"
+)
+
+// scheduledQueue 按计划发送时间排序的定时消息队列（最小堆）
+// 队列只在内存中维护，启动时从消息存储重建；调用方负责加锁并保证与存储一致。
+type scheduledQueue struct {
+	items scheduledHeap
+	index map[string]*scheduledItem // 消息ID -> 堆元素
+}
+
+// scheduledItem 堆元素
+type scheduledItem struct {
+	msg *base.ScheduledMessage
+	pos int // 在堆中的位置
+}
+
+// newScheduledQueue 创建定时消息队列
+func newScheduledQueue(messages []*base.ScheduledMessage) *scheduledQueue {
+	q := &scheduledQueue{index: make(map[string]*scheduledItem, len(messages))}
+	for _, msg := range messages {
+		q.push(msg)
+	}
+	return q
+}
+
+// push 加入定时消息，同ID的消息会被替换
+func (q *scheduledQueue) push(msg *base.ScheduledMessage) {
+	if item, exists := q.index[msg.Message.ID]; exists {
+		item.msg = msg
+		heap.Fix(&q.items, item.pos)
+		return
+	}
+	item := &scheduledItem{msg: msg}
+	heap.Push(&q.items, item)
+	q.index[msg.Message.ID] = item
+}
+
+// remove 按消息ID移除，返回被移除的消息
+func (q *scheduledQueue) remove(messageID string) (*base.ScheduledMessage, bool) {
+	item, exists := q.index[messageID]
+	if !exists {
+		return nil, false
+	}
+	heap.Remove(&q.items, item.pos)
+	delete(q.index, messageID)
+	return item.msg, true
+}
+
+// get 按消息ID查找
+func (q *scheduledQueue) get(messageID string) (*base.ScheduledMessage, bool) {
+	item, exists := q.index[messageID]
+	if !exists {
+		return nil, false
+	}
+	return item.msg, true
+}
+
+// next 返回最早的计划发送时间，队列为空时返回零值
+func (q *scheduledQueue) next() time.Time {
+	if len(q.items) == 0 {
+		return time.Time{}
+	}
+	return q.items[0].msg.ScheduledAt
+}
+
+// popDue 取出所有计划发送时间不晚于 now 的消息，按计划时间升序
+func (q *scheduledQueue) popDue(now time.Time) []*base.ScheduledMessage {
+	var due []*base.ScheduledMessage
+	for len(q.items) > 0 && !q.items[0].msg.ScheduledAt.After(now) {
+		item := heap.Pop(&q.items).(*scheduledItem)
+		delete(q.index, item.msg.Message.ID)
+		due = append(due, item.msg)
+	}
+	return due
+}
+
+// list 返回全部消息，按计划时间升序
+func (q *scheduledQueue) list() []*base.ScheduledMessage {
+	messages := make([]*base.ScheduledMessage, 0, len(q.items))
+	for _, item := range q.items {
+		messages = append(messages, item.msg)
+	}
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].ScheduledAt.Before(messages[j].ScheduledAt)
+	})
+	return messages
+}
+
+// len 队列长度
+func (q *scheduledQueue) len() int {
+	return len(q.items)
+}
+
+// scheduledHeap 实现 heap.Interface
+type scheduledHeap []*scheduledItem
+
+func (h scheduledHeap) Len() int { return len(h) }
+
+func (h scheduledHeap) Less(i, j int) bool {
+	return h[i].msg.ScheduledAt.Before(h[j].msg.ScheduledAt)
+}
+
+func (h scheduledHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].pos = i
+	h[j].pos = j
+}
+
+func (h *scheduledHeap) Push(x interface{}) {
+	item := x.(*scheduledItem)
+	item.pos = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *scheduledHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
@@ -0,0 +1,382 @@
+package core
+
+import (
+	"container/heap"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"task_scheduler/pkg/pushAPI/base"
+	"time"
+)
+
+// defaultEnqueueTimeout 队列已满时 block 方式的默认等待时间
+const defaultEnqueueTimeout = 5 * time.Second
+
+// SendQueue 按优先级发送的消息队列
+// 入队的消息同时写入消息存储，发送完成后删除，重启后未发送的消息重新入队（至少发送一次）。
+// 每隔刷新间隔（或 Flush 时）按优先级从高到低把消息交给发送协程：紧急消息最先，其次按 Priority 从高到低，
+// 同优先级按入队顺序。队列容量只计算尚未交给发送协程的消息。
+type SendQueue struct {
+	capacity int
+	interval time.Duration
+	workers  int
+	policy   string
+	timeout  time.Duration
+	store    MessageStore
+	send     func(msg *base.QueuedMessage) // 发送一条消息，发送失败由调用方记录
+	drop     func(msg *base.QueuedMessage) // 消息被挤出队列时调用
+
+	items queuedHeap
+	index map[string]*queuedItem // 消息ID -> 堆元素
+	seq   uint64
+	space chan struct{} // 出队时关闭并重建，唤醒所有等待空位的入队调用
+	mu    sync.Mutex
+
+	ready    chan struct{}      // 刷新间隔为0时，入队后唤醒刷新循环
+	flushReq chan chan struct{} // Flush 请求，刷新完成后关闭
+	dispatch chan *base.QueuedMessage
+	inflight sync.WaitGroup
+	stopChan chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewSendQueue 创建发送队列
+func NewSendQueue(cfg base.PushConfig, store MessageStore, send, drop func(msg *base.QueuedMessage)) (*SendQueue, error) {
+	if cfg.QueueSize < 0 {
+		return nil, fmt.Errorf("队列容量不能为负数: %d", cfg.QueueSize)
+	}
+
+	policy := cfg.Queue.FullPolicy
+	switch policy {
+	case "":
+		policy = base.QueueFullBlock
+	case base.QueueFullBlock, base.QueueFullReject, base.QueueFullDropLowest:
+	default:
+		return nil, fmt.Errorf("不支持的队列已满处理方式: %s", policy)
+	}
+
+	workers := cfg.Queue.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+	timeout := cfg.Queue.EnqueueTimeout
+	if timeout <= 0 {
+		timeout = defaultEnqueueTimeout
+	}
+
+	return &SendQueue{
+		capacity: cfg.QueueSize,
+		interval: cfg.FlushInterval,
+		workers:  workers,
+		policy:   policy,
+		timeout:  timeout,
+		store:    store,
+		send:     send,
+		drop:     drop,
+		index:    make(map[string]*queuedItem),
+		space:    make(chan struct{}),
+		ready:    make(chan struct{}, 1),
+		flushReq: make(chan chan struct{}),
+		dispatch: make(chan *base.QueuedMessage),
+		stopChan: make(chan struct{}),
+	}, nil
+}
+
+// Start 从消息存储恢复未发送的消息，启动刷新循环和发送协程
+func (sq *SendQueue) Start() error {
+	messages, err := sq.store.ListQueued()
+	if err != nil {
+		return fmt.Errorf("读取发送队列失败: %w", err)
+	}
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].EnqueuedAt.Before(messages[j].EnqueuedAt)
+	})
+
+	sq.mu.Lock()
+	for _, msg := range messages {
+		sq.push(msg)
+	}
+	sq.mu.Unlock()
+	if len(messages) > 0 {
+		log.Printf("已恢复%d条待发送的队列消息", len(messages))
+		sq.notifyReady()
+	}
+
+	for i := 0; i < sq.workers; i++ {
+		sq.wg.Add(1)
+		go sq.worker()
+	}
+	sq.wg.Add(1)
+	go sq.flushLoop()
+	return nil
+}
+
+// Stop 停止发送，正在发送的消息发送完成后返回，未发送的消息保留在存储中
+func (sq *SendQueue) Stop() {
+	close(sq.stopChan)
+	sq.wg.Wait()
+}
+
+// Enqueue 消息入队（同ID的消息会被替换），队列已满时按配置的方式处理
+func (sq *SendQueue) Enqueue(msg *base.QueuedMessage) error {
+	msg.EnqueuedAt = time.Now()
+
+	var deadline <-chan time.Time
+	for {
+		sq.mu.Lock()
+		_, exists := sq.index[msg.Message.ID]
+		if exists || sq.capacity == 0 || sq.items.Len() < sq.capacity {
+			err := sq.persist(msg)
+			sq.mu.Unlock()
+			if err == nil {
+				sq.notifyReady()
+			}
+			return err
+		}
+
+		switch sq.policy {
+		case base.QueueFullReject:
+			sq.mu.Unlock()
+			return fmt.Errorf("%w（容量%d）", ErrQueueFull, sq.capacity)
+
+		case base.QueueFullDropLowest:
+			lowest := sq.lowest()
+			if lowest == nil || !sq.items.higher(&queuedItem{msg: msg, seq: sq.seq + 1}, lowest) {
+				sq.mu.Unlock()
+				return fmt.Errorf("%w（容量%d），新消息的优先级不高于队列中的消息", ErrQueueFull, sq.capacity)
+			}
+			if err := sq.store.RemoveQueued(lowest.msg.Message.ID); err != nil {
+				sq.mu.Unlock()
+				return fmt.Errorf("删除被挤出的队列消息失败: %w", err)
+			}
+			heap.Remove(&sq.items, lowest.pos)
+			delete(sq.index, lowest.msg.Message.ID)
+			err := sq.persist(msg)
+			sq.mu.Unlock()
+			log.Printf("发送队列已满，消息被挤出: %s (优先级%d)", lowest.msg.Message.ID, lowest.msg.Options.Priority)
+			if sq.drop != nil {
+				sq.drop(lowest.msg)
+			}
+			if err == nil {
+				sq.notifyReady()
+			}
+			return err
+
+		default:
+			// block：等待发送协程取走消息腾出空位
+			space := sq.space
+			sq.mu.Unlock()
+			if deadline == nil {
+				timer := time.NewTimer(sq.timeout)
+				defer timer.Stop()
+				deadline = timer.C
+			}
+			select {
+			case <-space:
+			case <-deadline:
+				return fmt.Errorf("%w（容量%d），等待%s后仍无空位", ErrQueueFull, sq.capacity, sq.timeout)
+			case <-sq.stopChan:
+				return fmt.Errorf("发送队列已停止")
+			}
+		}
+	}
+}
+
+// Flush 立即按优先级发送队列中的全部消息，等待发送完成
+func (sq *SendQueue) Flush() error {
+	done := make(chan struct{})
+	select {
+	case sq.flushReq <- done:
+	case <-sq.stopChan:
+		return fmt.Errorf("发送队列已停止")
+	}
+	select {
+	case <-done:
+		return nil
+	case <-sq.stopChan:
+		return fmt.Errorf("发送队列已停止")
+	}
+}
+
+// Len 队列中等待发送的消息数
+func (sq *SendQueue) Len() int {
+	sq.mu.Lock()
+	defer sq.mu.Unlock()
+
+	return sq.items.Len()
+}
+
+// persist 写入存储后加入堆，调用方需持有锁
+func (sq *SendQueue) persist(msg *base.QueuedMessage) error {
+	if err := sq.store.PutQueued(msg); err != nil {
+		return fmt.Errorf("保存队列消息失败: %w", err)
+	}
+	sq.push(msg)
+	return nil
+}
+
+// push 加入堆，同ID的消息会被替换，调用方需持有锁
+func (sq *SendQueue) push(msg *base.QueuedMessage) {
+	sq.seq++
+	if item, exists := sq.index[msg.Message.ID]; exists {
+		item.msg = msg
+		item.seq = sq.seq
+		heap.Fix(&sq.items, item.pos)
+		return
+	}
+	item := &queuedItem{msg: msg, seq: sq.seq}
+	heap.Push(&sq.items, item)
+	sq.index[msg.Message.ID] = item
+}
+
+// pop 取出最先发送的消息并唤醒等待空位的入队调用，队列为空时返回 nil
+func (sq *SendQueue) pop() *base.QueuedMessage {
+	sq.mu.Lock()
+	defer sq.mu.Unlock()
+
+	if sq.items.Len() == 0 {
+		return nil
+	}
+	item := heap.Pop(&sq.items).(*queuedItem)
+	delete(sq.index, item.msg.Message.ID)
+	close(sq.space)
+	sq.space = make(chan struct{})
+	return item.msg
+}
+
+// lowest 查找最后发送的消息，调用方需持有锁
+func (sq *SendQueue) lowest() *queuedItem {
+	var lowest *queuedItem
+	for _, item := range sq.items {
+		if lowest == nil || sq.items.higher(lowest, item) {
+			lowest = item
+		}
+	}
+	return lowest
+}
+
+// notifyReady 刷新间隔为0时唤醒刷新循环
+func (sq *SendQueue) notifyReady() {
+	if sq.interval > 0 {
+		return
+	}
+	select {
+	case sq.ready <- struct{}{}:
+	default:
+	}
+}
+
+// flushLoop 每隔刷新间隔把队列中的消息交给发送协程
+func (sq *SendQueue) flushLoop() {
+	defer sq.wg.Done()
+
+	var tick <-chan time.Time
+	if sq.interval > 0 {
+		ticker := time.NewTicker(sq.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		var done chan struct{}
+		select {
+		case <-sq.stopChan:
+			return
+		case <-tick:
+		case <-sq.ready:
+		case done = <-sq.flushReq:
+		}
+
+		sq.drain()
+		if done != nil {
+			sq.inflight.Wait()
+			close(done)
+		}
+	}
+}
+
+// drain 按优先级逐条交给发送协程，每次只取一条，发送期间入队的高优先级消息可以插到前面
+func (sq *SendQueue) drain() {
+	for {
+		msg := sq.pop()
+		if msg == nil {
+			return
+		}
+
+		sq.inflight.Add(1)
+		select {
+		case sq.dispatch <- msg:
+		case <-sq.stopChan:
+			// 消息仍在存储中，重启后重新入队
+			sq.inflight.Done()
+			return
+		}
+	}
+}
+
+// worker 发送协程
+func (sq *SendQueue) worker() {
+	defer sq.wg.Done()
+
+	for {
+		select {
+		case <-sq.stopChan:
+			return
+		case msg := <-sq.dispatch:
+			sq.send(msg)
+			if err := sq.store.RemoveQueued(msg.Message.ID); err != nil {
+				log.Printf("删除已发送的队列消息失败: %s, %v", msg.Message.ID, err)
+			}
+			sq.inflight.Done()
+		}
+	}
+}
+
+// queuedItem 堆元素
+type queuedItem struct {
+	msg *base.QueuedMessage
+	seq uint64 // 入队序号，同优先级按入队顺序
+	pos int    // 在堆中的位置
+}
+
+// queuedHeap 实现 heap.Interface，堆顶为最先发送的消息
+type queuedHeap []*queuedItem
+
+// higher a 是否应先于 b 发送
+func (h queuedHeap) higher(a, b *queuedItem) bool {
+	aEmergency := a.msg.Message.Level == base.Emergency
+	bEmergency := b.msg.Message.Level == base.Emergency
+	if aEmergency != bEmergency {
+		return aEmergency
+	}
+	if a.msg.Options.Priority != b.msg.Options.Priority {
+		return a.msg.Options.Priority > b.msg.Options.Priority
+	}
+	return a.seq < b.seq
+}
+
+func (h queuedHeap) Len() int { return len(h) }
+
+func (h queuedHeap) Less(i, j int) bool { return h.higher(h[i], h[j]) }
+
+func (h queuedHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].pos = i
+	h[j].pos = j
+}
+
+func (h *queuedHeap) Push(x interface{}) {
+	item := x.(*queuedItem)
+	item.pos = len(*h)
+	*h = append(*h, item)
+}
+
+func (h *queuedHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
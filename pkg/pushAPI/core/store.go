@@ -0,0 +1,112 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"task_scheduler/pkg/pushAPI/base"
+	"time"
+)
+
+const (
+	// historySuccess 成功发送记录类型
+	historySuccess = "success_send"
+	// historyFailed 发送失败记录类型
+	historyFailed = "failed_send"
+)
+
+var (
+	// ErrAckNotFound 确认令牌不存在
+	ErrAckNotFound = errors.New("确认令牌不存在")
+	// ErrScheduledNotFound 定时消息不存在（已发送、已取消或ID错误）
+	ErrScheduledNotFound = errors.New("定时消息不存在")
+	// ErrRecurringNotFound 周期推送不存在（已结束、已取消或ID错误）
+	ErrRecurringNotFound = errors.New("周期推送不存在")
+	// ErrQueueFull 发送队列已满
+	ErrQueueFull = errors.New("发送队列已满")
+	// ErrStoreLocked bolt存储已被其他进程（通常是运行中的调度器）打开
+	ErrStoreLocked = errors.New("消息存储已被运行中的调度器占用")
+)
+
+// MessageStore 消息存储接口，保存延迟消息、定时消息、发送队列和历史记录
+type MessageStore interface {
+	// AppendDelay 追加延迟消息，digestAt 为消息将要发送的摘要时间
+	AppendDelay(digestAt time.Time, msg *base.DelayMessage) error
+	// ListDelay 获取全部延迟消息
+	ListDelay() ([]*base.DelayMessage, error)
+	// ClearDelay 清空全部延迟消息
+	ClearDelay() error
+
+	// AppendScheduled 追加定时消息
+	AppendScheduled(msg *base.ScheduledMessage) error
+	// ListScheduled 获取全部定时消息
+	ListScheduled() ([]*base.ScheduledMessage, error)
+	// RemoveScheduled 按消息ID删除定时消息
+	RemoveScheduled(messageIDs ...string) error
+
+	// AppendHistory 追加历史记录，recordType 为 success_send 或 failed_send
+	AppendHistory(recordType string, record *base.HistoryRecord) error
+	// ListHistory 获取指定类型和月份（YYYYMM）的历史记录
+	ListHistory(recordType, yearMonth string) ([]*base.HistoryRecord, error)
+	// FindHistory 按消息ID查找历史记录
+	FindHistory(messageID string) ([]*base.HistoryRecord, error)
+	// HistoryMonths 获取有历史记录的月份（YYYYMM），按时间升序
+	HistoryMonths() ([]string, error)
+	// DeleteHistoryBefore 删除早于指定月份（YYYYMM）的历史记录
+	DeleteHistoryBefore(yearMonth string) error
+
+	// PutAck 保存（新增或更新）确认记录
+	PutAck(record *base.AckRecord) error
+	// GetAck 按令牌获取确认记录，不存在时返回 ErrAckNotFound
+	GetAck(token string) (*base.AckRecord, error)
+	// ListAcks 获取全部确认记录
+	ListAcks() ([]*base.AckRecord, error)
+	// DeleteAck 删除确认记录
+	DeleteAck(token string) error
+
+	// PutRecurring 保存（新增或更新）周期推送
+	PutRecurring(msg *base.RecurringMessage) error
+	// ListRecurring 获取全部周期推送
+	ListRecurring() ([]*base.RecurringMessage, error)
+	// DeleteRecurring 删除周期推送，不存在时返回 ErrRecurringNotFound
+	DeleteRecurring(id string) error
+
+	// PutQueued 保存（新增或更新）发送队列中的消息
+	PutQueued(msg *base.QueuedMessage) error
+	// ListQueued 获取发送队列中的全部消息
+	ListQueued() ([]*base.QueuedMessage, error)
+	// RemoveQueued 按消息ID删除发送队列中的消息
+	RemoveQueued(messageIDs ...string) error
+
+	// Close 关闭存储
+	Close() error
+}
+
+// NewMessageStore 根据配置创建消息存储
+func NewMessageStore(cfg base.PushConfig) (MessageStore, error) {
+	switch cfg.Store.Type {
+	case "", base.StoreFile:
+		return NewFileStore(cfg.WorkingDir, cfg.HistoryDir), nil
+	case base.StoreBolt:
+		path := cfg.Store.Path
+		if path == "" {
+			path = filepath.Join(cfg.WorkingDir, "messages.db")
+		}
+		return NewBoltStore(path)
+	default:
+		return nil, fmt.Errorf("不支持的存储类型: %s", cfg.Store.Type)
+	}
+}
+
+// fillHistoryStatus 为旧版本写入的、没有状态字段的历史记录补充状态
+func fillHistoryStatus(recordType string, records ...*base.HistoryRecord) {
+	status := base.StatusSuccess.String()
+	if recordType == historyFailed {
+		status = base.StatusFailed.String()
+	}
+	for _, record := range records {
+		if record.Status == "" {
+			record.Status = status
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"task_scheduler/pkg/pushAPI/base"
+	"text/template"
+	"time"
+)
+
+const (
+	// templateExt 模板文件扩展名
+	templateExt = ".tmpl"
+	// layoutPrefix 推送器排版模板文件名前缀，如 _layout.wechat.tmpl
+	layoutPrefix = "_layout"
+)
+
+// ErrTemplate 模板不存在或渲染失败
+var ErrTemplate = errors.New("消息模板不可用")
+
+// RenderedTemplate 模板渲染结果
+type RenderedTemplate struct {
+	Title   string
+	Content string
+	Level   base.MessageLevel
+}
+
+// TemplateManager 消息模板管理器
+// 模板文件位于模板目录下，命名规则：
+//   - {name}.tmpl            默认模板
+//   - {name}.{channel}.tmpl  渠道模板（channel为推送器名称，如 wechat、email、sms）
+//   - _layout.{channel}.tmpl 推送器排版模板（目前微信推送器支持）
+//
+// 模板内可用 {{define "title"}}、{{define "content"}}、{{define "level"}} 定义标题、内容和级别，
+// 未定义 content 时整个文件作为内容。每次渲染都会重新读取文件，修改模板无需重启。
+type TemplateManager struct {
+	templateDir string
+	providers   map[string]TemplateDataProvider // 按模板名称注册的数据提供函数
+	mu          sync.RWMutex
+}
+
+// TemplateDataProvider 模板数据提供函数，周期推送每次渲染前调用以获取最新数据
+type TemplateDataProvider func() (interface{}, error)
+
+// NewTemplateManager 创建消息模板管理器
+func NewTemplateManager(templateDir string) *TemplateManager {
+	return &TemplateManager{
+		templateDir: templateDir,
+		providers:   make(map[string]TemplateDataProvider),
+	}
+}
+
+// SetDataProvider 为模板注册数据提供函数，provider 为 nil 时取消注册
+func (tm *TemplateManager) SetDataProvider(name string, provider TemplateDataProvider) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	if provider == nil {
+		delete(tm.providers, name)
+		return
+	}
+	tm.providers[name] = provider
+}
+
+// DataProvider 获取模板的数据提供函数
+func (tm *TemplateManager) DataProvider(name string) (TemplateDataProvider, bool) {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	provider, exists := tm.providers[name]
+	return provider, exists
+}
+
+// Render 渲染指定名称的模板，优先使用渠道模板
+func (tm *TemplateManager) Render(name, channel string, data interface{}) (*RenderedTemplate, error) {
+	filePath, err := tm.resolve(name, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := template.New(filepath.Base(filePath)).Funcs(templateFuncs()).ParseFiles(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("解析模板失败 %s: %w", filePath, err)
+	}
+
+	rendered := &RenderedTemplate{Level: base.Normal}
+
+	// 内容：优先 content 块，否则整个文件
+	contentName := filepath.Base(filePath)
+	if tmpl.Lookup("content") != nil {
+		contentName = "content"
+	}
+	if rendered.Content, err = executeTemplate(tmpl, contentName, data); err != nil {
+		return nil, err
+	}
+
+	// 标题：未定义 title 块时使用模板名称
+	rendered.Title = name
+	if tmpl.Lookup("title") != nil {
+		if rendered.Title, err = executeTemplate(tmpl, "title", data); err != nil {
+			return nil, err
+		}
+	}
+
+	// 级别：模板可通过 level 块输出 emergency 提升消息级别
+	if tmpl.Lookup("level") != nil {
+		level, err := executeTemplate(tmpl, "level", data)
+		if err != nil {
+			return nil, err
+		}
+		rendered.Level = base.ParseMessageLevel(level)
+	}
+
+	return rendered, nil
+}
+
+// Layout 获取推送器排版模板内容
+func (tm *TemplateManager) Layout(channel string) (string, bool) {
+	filePath := filepath.Join(tm.templateDir, fmt.Sprintf("%s.%s%s", layoutPrefix, channel, templateExt))
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// resolve 查找模板文件路径
+func (tm *TemplateManager) resolve(name, channel string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("无效的模板名称: %s", name)
+	}
+
+	candidates := []string{fmt.Sprintf("%s%s", name, templateExt)}
+	if channel != "" {
+		candidates = append([]string{fmt.Sprintf("%s.%s%s", name, channel, templateExt)}, candidates...)
+	}
+
+	for _, candidate := range candidates {
+		filePath := filepath.Join(tm.templateDir, candidate)
+		if _, err := os.Stat(filePath); err == nil {
+			return filePath, nil
+		}
+	}
+
+	return "", fmt.Errorf("模板不存在: %s (渠道: %s, 目录: %s)", name, channel, tm.templateDir)
+}
+
+// executeTemplate 执行模板并去除首尾空白
+func executeTemplate(tmpl *template.Template, name string, data interface{}) (string, error) {
+	var sb strings.Builder
+	if err := tmpl.ExecuteTemplate(&sb, name, data); err != nil {
+		return "", fmt.Errorf("渲染模板失败 %s: %w", name, err)
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
+
+// templateFuncs 模板中可用的辅助函数
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"now": time.Now,
+		"formatTime": func(layout string, t time.Time) string {
+			return t.Format(layout)
+		},
+		"json": func(v interface{}) (string, error) {
+			data, err := json.MarshalIndent(v, "", "  ")
+			if err != nil {
+				return "", err
+			}
+			return string(data), nil
+		},
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"join":  strings.Join,
+		"default": func(def, value interface{}) interface{} {
+			if value == nil || value == "" {
+				return def
+			}
+			return value
+		},
+	}
+}
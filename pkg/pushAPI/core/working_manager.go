@@ -1,48 +1,78 @@
 package core
 
 import (
-	"encoding/json"
 	"fmt"
 	"log"
-	"os"
-	"path/filepath"
 	"sort"
 	"strings"
 	"sync"
 	"task_scheduler/pkg/pushAPI/base"
 	"task_scheduler/pkg/pushAPI/push_method"
 	"time"
+
+	"github.com/robfig/cron/v3"
 )
 
+// scheduledMaxWait 定时消息定时器的最长等待时间
+const scheduledMaxWait = time.Hour
+
 // WorkingManager 工作目录管理器
 type WorkingManager struct {
-	workingDir     string
+	store          MessageStore
 	pusher         push_method.IPusher
 	historyHandler *HistoryHandler
+	send           func(msg base.Message, options base.PushOptions) error // 发送到期的定时消息
+	digestConfig   base.DigestConfig
+	digestSchedule cron.Schedule // 延迟消息摘要发送调度
+	quietHours     *quietHours   // 免打扰时段，nil表示未配置
+	queue          *scheduledQueue
+	scheduleWake   chan struct{} // 定时消息队列变化时唤醒发送循环
 	stopChan       chan struct{}
 	wg             sync.WaitGroup
-	mu             sync.Mutex
+	mu             sync.Mutex // 保护存储和定时消息队列，发送时不持有
+	sendMu         sync.Mutex // 保证同一时间只有一次延迟消息合并发送
 }
 
-// NewWorkingManager 创建工作目录管理器
-func NewWorkingManager(workingDir string, pusher push_method.IPusher, historyHandler *HistoryHandler) *WorkingManager {
+// NewWorkingManager 创建工作目录管理器，到期的定时消息通过 send 发送
+func NewWorkingManager(store MessageStore, pusher push_method.IPusher, historyHandler *HistoryHandler, digestConfig base.DigestConfig, send func(msg base.Message, options base.PushOptions) error) *WorkingManager {
 	return &WorkingManager{
-		workingDir:     workingDir,
+		store:          store,
 		pusher:         pusher,
 		historyHandler: historyHandler,
+		send:           send,
+		digestConfig:   digestConfig,
+		queue:          newScheduledQueue(nil),
+		scheduleWake:   make(chan struct{}, 1),
 		stopChan:       make(chan struct{}),
 	}
 }
 
-// Start 启动工作目录管理器
-func (wm *WorkingManager) Start() error {
-	if err := os.MkdirAll(wm.workingDir, 0755); err != nil {
-		return fmt.Errorf("创建工作目录失败: %w", err)
+// Load 解析摘要调度并从存储重建定时消息队列，不启动发送循环
+func (wm *WorkingManager) Load() error {
+	schedule, err := parseDigestSchedule(wm.digestConfig)
+	if err != nil {
+		return err
+	}
+	quiet, err := parseQuietHours(wm.digestConfig.QuietHours)
+	if err != nil {
+		return err
+	}
+	wm.digestSchedule = schedule
+	wm.quietHours = quiet
+
+	// 从存储重建定时消息队列，停机期间过期的消息在发送循环启动后立即发送
+	scheduledMessages, err := wm.store.ListScheduled()
+	if err != nil {
+		return fmt.Errorf("读取定时消息失败: %w", err)
 	}
+	wm.queue = newScheduledQueue(scheduledMessages)
+	return nil
+}
 
+// Start 启动发送循环，停机期间过期的定时消息立即发送；需先调用 Load
+func (wm *WorkingManager) Start() {
 	wm.wg.Add(1)
 	go wm.periodicSendLoop()
-	return nil
 }
 
 // Stop 停止工作目录管理器
@@ -52,54 +82,125 @@ func (wm *WorkingManager) Stop() error {
 	return nil
 }
 
-// periodicSendLoop 定期发送循环（每4小时检查一次）
+// periodicSendLoop 发送循环：按摘要调度发送延迟消息，在定时消息到期时立即发送
 func (wm *WorkingManager) periodicSendLoop() {
 	defer wm.wg.Done()
 
-	// 每4小时检查一次延迟消息
-	delayTicker := time.NewTicker(4 * time.Hour)
-	defer delayTicker.Stop()
+	// 按摘要调度定时发送延迟消息
+	nextDigest := wm.digestSchedule.Next(time.Now())
+	digestTimer := time.NewTimer(time.Until(nextDigest))
+	defer digestTimer.Stop()
 
-	// 每分钟检查一次定时消息
-	scheduledTicker := time.NewTicker(1 * time.Minute)
-	defer scheduledTicker.Stop()
+	// 定时器指向队列中最早的定时消息
+	scheduledTimer := time.NewTimer(wm.untilNextScheduled())
+	defer scheduledTimer.Stop()
 
 	for {
 		select {
 		case <-wm.stopChan:
 			return
-		case <-delayTicker.C:
-			if err := wm.sendAllDelayMessages(); err != nil {
+		case <-digestTimer.C:
+			// 免打扰时段内跳过，留到下一次摘要发送
+			if wm.InQuietHours(nextDigest) {
+				log.Printf("免打扰时段内跳过摘要发送: %s", nextDigest.Format("2006-01-02 15:04"))
+			} else if err := wm.SendAllDelayMessages(); err != nil {
 				log.Printf("定期发送延迟消息失败: %v", err)
 			}
-		case <-scheduledTicker.C:
+			nextDigest = wm.digestSchedule.Next(time.Now())
+			digestTimer.Reset(time.Until(nextDigest))
+		case <-wm.scheduleWake:
+			if !scheduledTimer.Stop() {
+				select {
+				case <-scheduledTimer.C:
+				default:
+				}
+			}
+			scheduledTimer.Reset(wm.untilNextScheduled())
+		case <-scheduledTimer.C:
 			if err := wm.ProcessScheduledMessages(); err != nil {
 				log.Printf("处理定时消息失败: %v", err)
 			}
+			scheduledTimer.Reset(wm.untilNextScheduled())
 		}
 	}
 }
 
+// untilNextScheduled 距离最早的定时消息的等待时间
+// 最长等待 scheduledMaxWait，避免系统休眠或调整时钟后定时器长时间不触发
+func (wm *WorkingManager) untilNextScheduled() time.Duration {
+	wm.mu.Lock()
+	next := wm.queue.next()
+	wm.mu.Unlock()
+
+	if next.IsZero() {
+		return scheduledMaxWait
+	}
+	wait := time.Until(next)
+	if wait < 0 {
+		return 0
+	}
+	if wait > scheduledMaxWait {
+		return scheduledMaxWait
+	}
+	return wait
+}
+
+// notifyScheduled 通知发送循环重新计算定时器
+func (wm *WorkingManager) notifyScheduled() {
+	select {
+	case wm.scheduleWake <- struct{}{}:
+	default:
+	}
+}
+
+// InQuietHours 判断时间是否处于免打扰时段
+func (wm *WorkingManager) InQuietHours(t time.Time) bool {
+	return wm.quietHours.contains(t)
+}
+
+// NextDigestTime 返回 t 之后下一次会实际发送的摘要时间（跳过免打扰时段）
+func (wm *WorkingManager) NextDigestTime(t time.Time) time.Time {
+	schedule := wm.digestSchedule
+	if schedule == nil {
+		schedule, _ = parseDigestSchedule(base.DigestConfig{})
+	}
+
+	next := schedule.Next(t)
+	first := next
+	// 最多向后查找一周的调度，避免所有时刻都处于免打扰时段时死循环
+	for wm.InQuietHours(next) && next.Sub(first) < 7*24*time.Hour {
+		next = schedule.Next(next)
+	}
+	if next.IsZero() || wm.InQuietHours(next) {
+		return first
+	}
+	return next
+}
+
 // AddDelayMessage 添加延迟消息
 func (wm *WorkingManager) AddDelayMessage(msg base.Message, options base.PushOptions) error {
+	return wm.addDelay(msg, options, false)
+}
+
+// AddRateLimitedMessage 添加超出限流配额的消息，只随摘要发送，不随立即推送一起发送
+func (wm *WorkingManager) AddRateLimitedMessage(msg base.Message, options base.PushOptions) error {
+	return wm.addDelay(msg, options, true)
+}
+
+// addDelay 写入延迟消息
+func (wm *WorkingManager) addDelay(msg base.Message, options base.PushOptions, rateLimited bool) error {
 	wm.mu.Lock()
 	defer wm.mu.Unlock()
 
 	delayMsg := &base.DelayMessage{
-		Message:   msg,
-		Options:   options,
-		CreatedAt: time.Now(),
-	}
-
-	currentFile := wm.getDelayFileName(time.Now())
-	existingMessages, err := wm.readDelayMessages(currentFile)
-	if err != nil {
-		return fmt.Errorf("读取现有延迟消息失败: %w", err)
+		Message:     msg,
+		Options:     options,
+		CreatedAt:   time.Now(),
+		RateLimited: rateLimited,
 	}
 
-	existingMessages = append(existingMessages, delayMsg)
-
-	if err := wm.writeDelayMessages(currentFile, existingMessages); err != nil {
+	// 按消息将要发送的摘要时间分组
+	if err := wm.store.AppendDelay(wm.NextDigestTime(time.Now()), delayMsg); err != nil {
 		return fmt.Errorf("写入延迟消息失败: %w", err)
 	}
 
@@ -115,118 +216,142 @@ func (wm *WorkingManager) AddScheduledMessage(msg base.Message, options base.Pus
 	scheduledMsg := &base.ScheduledMessage{
 		Message:     msg,
 		Options:     options,
-		ScheduledAt: scheduledAt.Truncate(time.Minute),
-	}
-
-	targetFile := wm.getScheduledFileName(scheduledAt)
-	existingMessages, err := wm.readScheduledMessages(targetFile)
-	if err != nil {
-		return fmt.Errorf("读取现有定时消息失败: %w", err)
+		ScheduledAt: scheduledAt.Truncate(time.Second),
 	}
 
-	existingMessages = append(existingMessages, scheduledMsg)
-
-	if err := wm.writeScheduledMessages(targetFile, existingMessages); err != nil {
+	if err := wm.store.AppendScheduled(scheduledMsg); err != nil {
 		return fmt.Errorf("写入定时消息失败: %w", err)
 	}
+	wm.queue.push(scheduledMsg)
+	wm.notifyScheduled()
 
-	log.Printf("定时消息已安排: %s -> %s", msg.ID, scheduledAt.Format("2006-01-02 15:04"))
+	log.Printf("定时消息已安排: %s -> %s", msg.ID, scheduledAt.Format("2006-01-02 15:04:05"))
 	return nil
 }
 
-// ProcessScheduledMessages 处理定时消息
-func (wm *WorkingManager) ProcessScheduledMessages() error {
+// ListScheduledMessages 按条件列出待发送的定时消息，按计划发送时间升序
+func (wm *WorkingManager) ListScheduledMessages(filter base.ScheduledFilter) ([]*base.ScheduledMessage, error) {
 	wm.mu.Lock()
 	defer wm.mu.Unlock()
 
-	now := time.Now().Truncate(time.Minute)
-	currentFile := wm.getScheduledFileName(now)
+	matched := []*base.ScheduledMessage{}
+	for _, scheduledMsg := range wm.queue.list() {
+		if filter.Match(scheduledMsg) {
+			matched = append(matched, scheduledMsg)
+		}
+	}
+	return matched, nil
+}
+
+// CancelScheduledMessage 取消尚未发送的定时消息
+func (wm *WorkingManager) CancelScheduledMessage(messageID string) error {
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
 
-	scheduledMessages, err := wm.readScheduledMessages(currentFile)
-	if err != nil {
-		return fmt.Errorf("读取定时消息失败: %w", err)
+	if _, exists := wm.queue.get(messageID); !exists {
+		return fmt.Errorf("%w: %s", ErrScheduledNotFound, messageID)
+	}
+	if err := wm.store.RemoveScheduled(messageID); err != nil {
+		return fmt.Errorf("删除定时消息失败: %w", err)
 	}
+	wm.queue.remove(messageID)
+	wm.notifyScheduled()
 
-	var remainingMessages []*base.ScheduledMessage
-	var messagesToSend []*base.ScheduledMessage
+	log.Printf("定时消息已取消: %s", messageID)
+	return nil
+}
 
-	// 检查所有定时消息，找出需要发送的消息
-	for _, scheduledMsg := range scheduledMessages {
-		if scheduledMsg.ScheduledAt.Truncate(time.Minute).Before(now) || scheduledMsg.ScheduledAt.Truncate(time.Minute).Equal(now) {
-			// 需要发送的消息
-			messagesToSend = append(messagesToSend, scheduledMsg)
-		} else {
-			// 未到发送时间，保留消息
-			remainingMessages = append(remainingMessages, scheduledMsg)
-		}
+// RescheduleMessage 修改尚未发送的定时消息的计划发送时间
+func (wm *WorkingManager) RescheduleMessage(messageID string, scheduledAt time.Time) error {
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+
+	existing, exists := wm.queue.get(messageID)
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrScheduledNotFound, messageID)
 	}
 
-	// 如果有需要发送的定时消息
-	if len(messagesToSend) > 0 {
-		log.Printf("发现 %d 条过期的定时消息需要发送", len(messagesToSend))
+	// 文件存储按计划时间分片保存，需要先删除再写入新的时间片
+	if err := wm.store.RemoveScheduled(messageID); err != nil {
+		return fmt.Errorf("删除定时消息失败: %w", err)
+	}
+	rescheduled := &base.ScheduledMessage{
+		Message:     existing.Message,
+		Options:     existing.Options,
+		ScheduledAt: scheduledAt.Truncate(time.Second),
+	}
+	if err := wm.store.AppendScheduled(rescheduled); err != nil {
+		wm.queue.remove(messageID)
+		return fmt.Errorf("写入定时消息失败: %w", err)
+	}
+	wm.queue.push(rescheduled)
+	wm.notifyScheduled()
 
-		// 发送所有过期的定时消息
-		for _, scheduledMsg := range messagesToSend {
-			// 设置发送时间
-			sentTime := time.Now()
-			scheduledMsg.Message.SetSentAt(sentTime)
-			scheduledMsg.Message.SetSendStatus(base.StatusSuccess)
+	log.Printf("定时消息已改期: %s -> %s", messageID, scheduledAt.Format("2006-01-02 15:04:05"))
+	return nil
+}
 
-			if err := wm.pusher.Push(scheduledMsg.Message); err != nil {
-				// 设置失败状态
-				scheduledMsg.Message.SetSendStatus(base.StatusFailed)
-				// 记录发送失败
-				if wm.historyHandler != nil {
-					wm.historyHandler.RecordFailure(scheduledMsg.Message, wm.pusher.GetName(), scheduledMsg.Options, fmt.Sprintf("定时推送失败: %v", err))
-				}
-				log.Printf("定时消息发送失败: %v", err)
-			} else {
-				// 记录发送成功
-				if wm.historyHandler != nil {
-					wm.historyHandler.RecordSuccess(scheduledMsg.Message, wm.pusher.GetName(), scheduledMsg.Options)
-				}
-				log.Printf("定时消息发送成功: %s", scheduledMsg.Message.ID)
-			}
-		}
+// ProcessScheduledMessages 发送到期的定时消息（包括停机期间过期的）
+// 消息经过 send（推送控制器的发送流程）处理限流、免打扰、确认升级和历史记录；
+// 持有锁时只取出到期消息，发送（含重试等待）时不持有锁，不阻塞新消息写入
+func (wm *WorkingManager) ProcessScheduledMessages() error {
+	wm.mu.Lock()
+	due := wm.queue.popDue(time.Now())
+	wm.mu.Unlock()
 
-		// 发送定时消息后，检查并发送所有延迟消息
-		if err := wm.sendAllDelayMessages(); err != nil {
-			log.Printf("发送延迟消息失败: %v", err)
-		}
+	if len(due) == 0 {
+		return nil
 	}
+	log.Printf("发现 %d 条到期的定时消息需要发送", len(due))
 
-	// 更新文件内容
-	if err := wm.writeScheduledMessages(currentFile, remainingMessages); err != nil {
-		return fmt.Errorf("更新定时消息文件失败: %w", err)
+	dueIDs := make([]string, 0, len(due))
+	for _, scheduledMsg := range due {
+		dueIDs = append(dueIDs, scheduledMsg.Message.ID)
+		if err := wm.send(scheduledMsg.Message, scheduledMsg.Options); err != nil {
+			log.Printf("定时消息发送失败: %s, %v", scheduledMsg.Message.ID, err)
+		}
 	}
 
+	// 移除已处理的定时消息
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
+	if err := wm.store.RemoveScheduled(dueIDs...); err != nil {
+		return fmt.Errorf("更新定时消息失败: %w", err)
+	}
 	return nil
 }
 
-// SendAllDelayMessages 发送所有延迟消息
+// SendAllDelayMessages 合并发送所有延迟消息
+// 发送（含重试等待）时不持有 mu，发送期间新写入的延迟消息保留到下一次发送
 func (wm *WorkingManager) SendAllDelayMessages() error {
-	return wm.sendAllDelayMessages()
+	return wm.sendDelay(false)
 }
 
-// sendAllDelayMessages 发送所有延迟消息（内部方法）
-func (wm *WorkingManager) sendAllDelayMessages() error {
-	delayFiles, err := wm.getAllDelayFiles()
-	if err != nil {
-		return fmt.Errorf("获取延迟消息文件失败: %w", err)
-	}
+// SendDelayMessagesAfterPush 立即推送后发送延迟消息，被限流的消息留到摘要发送，避免每次立即推送都额外消耗推送配额
+func (wm *WorkingManager) SendDelayMessagesAfterPush() error {
+	return wm.sendDelay(true)
+}
 
-	if len(delayFiles) == 0 {
-		return nil
+// sendDelay 合并发送延迟消息，skipRateLimited 时跳过被限流的消息
+func (wm *WorkingManager) sendDelay(skipRateLimited bool) error {
+	wm.sendMu.Lock()
+	defer wm.sendMu.Unlock()
+
+	wm.mu.Lock()
+	pending, err := wm.store.ListDelay()
+	wm.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("读取延迟消息失败: %w", err)
 	}
 
-	var allDelayMessages []*base.DelayMessage
-	for _, file := range delayFiles {
-		messages, err := wm.readDelayMessages(file)
-		if err != nil {
-			log.Printf("读取延迟消息文件失败 %s: %v", file, err)
-			continue
+	allDelayMessages := pending
+	if skipRateLimited {
+		allDelayMessages = nil
+		for _, delayMsg := range pending {
+			if !delayMsg.RateLimited {
+				allDelayMessages = append(allDelayMessages, delayMsg)
+			}
 		}
-		allDelayMessages = append(allDelayMessages, messages...)
 	}
 
 	if len(allDelayMessages) == 0 {
@@ -241,7 +366,7 @@ func (wm *WorkingManager) sendAllDelayMessages() error {
 	mergedMessage.SetSentAt(sentTime)
 	mergedMessage.SetSendStatus(base.StatusSuccess)
 
-	if err := wm.pusher.Push(mergedMessage); err != nil {
+	if err := pushWithRetry(wm.pusher, mergedMessage, mergedOptions); err != nil {
 		// 设置失败状态
 		mergedMessage.SetSendStatus(base.StatusFailed)
 		if wm.historyHandler != nil {
@@ -256,14 +381,39 @@ func (wm *WorkingManager) sendAllDelayMessages() error {
 
 	log.Printf("延迟消息发送成功: %d条消息已合并发送", len(allDelayMessages))
 
-	// 清空所有延迟消息文件
-	for _, file := range delayFiles {
-		if err := wm.writeDelayMessages(file, []*base.DelayMessage{}); err != nil {
-			log.Printf("清空延迟消息文件失败 %s: %v", file, err)
-		}
+	// 清除已发送的延迟消息
+	if err := wm.clearSentDelay(allDelayMessages); err != nil {
+		log.Printf("清空延迟消息失败: %v", err)
 	}
+	return nil
+}
+
+// clearSentDelay 清除已发送的延迟消息，重新写入发送期间新增的消息
+func (wm *WorkingManager) clearSentDelay(sent []*base.DelayMessage) error {
+	wm.mu.Lock()
+	defer wm.mu.Unlock()
 
-	wm.cleanupOldDelayFiles()
+	sentIDs := make(map[string]bool, len(sent))
+	for _, delayMsg := range sent {
+		sentIDs[delayMsg.Message.ID] = true
+	}
+	current, err := wm.store.ListDelay()
+	if err != nil {
+		return err
+	}
+	if err := wm.store.ClearDelay(); err != nil {
+		return err
+	}
+
+	digestAt := wm.NextDigestTime(time.Now())
+	for _, delayMsg := range current {
+		if sentIDs[delayMsg.Message.ID] {
+			continue
+		}
+		if err := wm.store.AppendDelay(digestAt, delayMsg); err != nil {
+			return fmt.Errorf("重新写入延迟消息失败: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -280,11 +430,13 @@ func (wm *WorkingManager) mergeDelayMessages(messages []*base.DelayMessage) base
 	var titles []string
 	var contents []string
 	var appIDs []string
+	var blocks []base.ContentBlock
 
 	for _, msg := range messages {
 		titles = append(titles, msg.Message.Title)
 		contents = append(contents, fmt.Sprintf("[%s] %s", msg.Message.Title, msg.Message.Content))
 		appIDs = append(appIDs, msg.Message.AppID)
+		blocks = append(blocks, msg.Message.Blocks...)
 	}
 
 	uniqueAppIDs := make(map[string]bool)
@@ -304,6 +456,7 @@ func (wm *WorkingManager) mergeDelayMessages(messages []*base.DelayMessage) base
 		base.Normal,
 	)
 
+	mergedMessage.AddBlocks(blocks...)
 	mergedMessage.SetMetadata("merged_count", len(messages))
 	mergedMessage.SetMetadata("original_messages", len(messages))
 	mergedMessage.SetMetadata("merge_time", time.Now())
@@ -344,120 +497,3 @@ func (wm *WorkingManager) mergeDelayOptions(messages []*base.DelayMessage) base.
 		Retry:     maxRetry,
 	}
 }
-
-// getDelayFileName 获取延迟消息文件名
-func (wm *WorkingManager) getDelayFileName(t time.Time) string {
-	hour := t.Hour()
-	slotStart := hour - (hour % 4)
-	dateStr := t.Format("20060102")
-	timeStr := fmt.Sprintf("%02d", slotStart)
-	return filepath.Join(wm.workingDir, fmt.Sprintf("delay_%s_%s.json", dateStr, timeStr))
-}
-
-// getScheduledFileName 获取定时消息文件名
-func (wm *WorkingManager) getScheduledFileName(t time.Time) string {
-	hour := t.Hour()
-	slotStart := hour - (hour % 4)
-	dateStr := t.Format("20060102")
-	timeStr := fmt.Sprintf("%02d", slotStart)
-	return filepath.Join(wm.workingDir, fmt.Sprintf("scheduled_%s_%s.json", dateStr, timeStr))
-}
-
-// getAllDelayFiles 获取所有延迟消息文件
-func (wm *WorkingManager) getAllDelayFiles() ([]string, error) {
-	pattern := filepath.Join(wm.workingDir, "delay_*.json")
-	files, err := filepath.Glob(pattern)
-	if err != nil {
-		return nil, fmt.Errorf("查找延迟消息文件失败: %w", err)
-	}
-	return files, nil
-}
-
-// readDelayMessages 读取延迟消息文件
-func (wm *WorkingManager) readDelayMessages(filePath string) ([]*base.DelayMessage, error) {
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return []*base.DelayMessage{}, nil
-		}
-		return nil, fmt.Errorf("读取文件失败: %w", err)
-	}
-
-	var messages []*base.DelayMessage
-	if err := json.Unmarshal(data, &messages); err != nil {
-		return nil, fmt.Errorf("解析延迟消息失败: %w", err)
-	}
-
-	return messages, nil
-}
-
-// writeDelayMessages 写入延迟消息文件
-func (wm *WorkingManager) writeDelayMessages(filePath string, messages []*base.DelayMessage) error {
-	data, err := json.MarshalIndent(messages, "", "  ")
-	if err != nil {
-		return fmt.Errorf("序列化延迟消息失败: %w", err)
-	}
-
-	if err := os.WriteFile(filePath, data, 0644); err != nil {
-		return fmt.Errorf("写入延迟消息文件失败: %w", err)
-	}
-
-	return nil
-}
-
-// readScheduledMessages 读取定时消息文件
-func (wm *WorkingManager) readScheduledMessages(filePath string) ([]*base.ScheduledMessage, error) {
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return []*base.ScheduledMessage{}, nil
-		}
-		return nil, fmt.Errorf("读取文件失败: %w", err)
-	}
-
-	var messages []*base.ScheduledMessage
-	if err := json.Unmarshal(data, &messages); err != nil {
-		return nil, fmt.Errorf("解析定时消息失败: %w", err)
-	}
-
-	return messages, nil
-}
-
-// writeScheduledMessages 写入定时消息文件
-func (wm *WorkingManager) writeScheduledMessages(filePath string, messages []*base.ScheduledMessage) error {
-	data, err := json.MarshalIndent(messages, "", "  ")
-	if err != nil {
-		return fmt.Errorf("序列化定时消息失败: %w", err)
-	}
-
-	if err := os.WriteFile(filePath, data, 0644); err != nil {
-		return fmt.Errorf("写入定时消息文件失败: %w", err)
-	}
-
-	return nil
-}
-
-// cleanupOldDelayFiles 清理旧的延迟消息文件
-func (wm *WorkingManager) cleanupOldDelayFiles() {
-	files, err := wm.getAllDelayFiles()
-	if err != nil {
-		log.Printf("获取延迟消息文件失败: %v", err)
-		return
-	}
-
-	for _, file := range files {
-		data, err := os.ReadFile(file)
-		if err != nil {
-			log.Printf("读取文件失败 %s: %v", file, err)
-			continue
-		}
-
-		if len(data) == 0 || string(data) == "[]" {
-			if err := os.Remove(file); err != nil {
-				log.Printf("删除空文件失败 %s: %v", file, err)
-			} else {
-				log.Printf("已删除空文件: %s", file)
-			}
-		}
-	}
-}
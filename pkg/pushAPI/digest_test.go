@@ -0,0 +1,79 @@
+package pushAPI
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestQuietHoursHoldNormalMessages(t *testing.T) {
+	tempDir := t.TempDir()
+	now := time.Now()
+	digestAt := now.Add(3 * time.Hour)
+
+	cfg := DefaultConfig()
+	cfg.WorkingDir = filepath.Join(tempDir, "working")
+	cfg.HistoryDir = filepath.Join(tempDir, "history")
+	cfg.Digest = DigestConfig{
+		Times: []string{digestAt.Format("15:04")},
+		QuietHours: QuietHoursConfig{
+			Start: now.Add(-time.Hour).Format("15:04"),
+			End:   now.Add(time.Hour).Format("15:04"),
+		},
+	}
+
+	pusher := newCapturePusher("capture")
+	api := NewPushAPI()
+	if err := api.InitializeWithPusher(cfg, pusher); err != nil {
+		t.Fatalf("初始化失败: %v", err)
+	}
+	defer api.(*PushAPIImpl).Stop()
+
+	options := DefaultPushOptions()
+	if err := api.PushNow(*NewNormalMessage("app1", "日报", "今日无异常"), options); err != nil {
+		t.Fatalf("推送失败: %v", err)
+	}
+	if len(pusher.messages) != 0 {
+		t.Fatalf("免打扰时段内普通消息不应立即发送，实际发送%d条", len(pusher.messages))
+	}
+
+	// 普通消息按下一次摘要时间写入延迟文件
+	expected := filepath.Join(cfg.WorkingDir, "delay_"+digestAt.Format("20060102_1504")+".json")
+	files, _ := filepath.Glob(filepath.Join(cfg.WorkingDir, "delay_*.json"))
+	if len(files) != 1 || files[0] != expected {
+		t.Fatalf("延迟文件应为 %s，实际为 %v", expected, files)
+	}
+
+	// 紧急消息立即发送，但不会带出延迟消息
+	if err := api.PushNow(*NewMessage("app1", "告警", "服务不可用", Emergency), options); err != nil {
+		t.Fatalf("推送失败: %v", err)
+	}
+	if len(pusher.messages) != 1 || pusher.messages[0].Title != "告警" {
+		t.Fatalf("免打扰时段内应只发送紧急消息，实际: %+v", pusher.messages)
+	}
+	if files, _ := filepath.Glob(filepath.Join(cfg.WorkingDir, "delay_*.json")); len(files) != 1 {
+		t.Error("免打扰时段内延迟消息应保留到下一次摘要发送")
+	}
+}
+
+func TestInvalidDigestConfig(t *testing.T) {
+	tempDir := t.TempDir()
+	testCases := []DigestConfig{
+		{Cron: "not a cron"},
+		{Times: []string{"25:00"}},
+		{QuietHours: QuietHoursConfig{Start: "22:00"}},
+	}
+
+	for _, digest := range testCases {
+		cfg := DefaultConfig()
+		cfg.WorkingDir = filepath.Join(tempDir, "working")
+		cfg.HistoryDir = filepath.Join(tempDir, "history")
+		cfg.Digest = digest
+
+		api := NewPushAPI()
+		if err := api.InitializeWithPusher(cfg, newCapturePusher("capture")); err == nil {
+			api.(*PushAPIImpl).Stop()
+			t.Errorf("无效的摘要配置应初始化失败: %+v", digest)
+		}
+	}
+}
@@ -53,7 +53,7 @@ func ExampleUsage() {
 		log.Printf("紧急消息推送失败: %v", err)
 	}
 
-	// 入队推送（延迟消息）
+	// 入队推送（按优先级发送）
 	delayMessage := NewNormalMessage("app2", "延迟消息", "这是一条延迟消息")
 	delayMessage.SetMetadata("delay_reason", "scheduled")
 
@@ -68,7 +68,7 @@ func ExampleUsage() {
 		log.Printf("入队失败: %v", err)
 	}
 
-	// 手动刷新队列（会自动合并并发送所有延迟消息）
+	// 手动刷新队列（按优先级发送队列消息，并合并发送所有延迟消息）
 	if err := api.FlushQueue(); err != nil {
 		log.Printf("刷新队列失败: %v", err)
 	}
@@ -0,0 +1,133 @@
+package pushAPI
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"task_scheduler/pkg/pushAPI/base"
+)
+
+func TestQueryHistory(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.WorkingDir = filepath.Join(tempDir, "working")
+	cfg.HistoryDir = filepath.Join(tempDir, "history")
+
+	pusher := &failingPusher{capturePusher: newCapturePusher("capture")}
+	api := NewPushAPI()
+	if err := api.InitializeWithPusher(cfg, pusher); err != nil {
+		t.Fatalf("初始化失败: %v", err)
+	}
+
+	options := DefaultPushOptions()
+	options.Retry = 0
+	confirm := NewNormalMessage("auto-buy", "交易确认", "已买入 0.001 BTC")
+	api.PushNow(*confirm, options)
+	api.PushNow(*NewNormalMessage("app2", "日报", "今日无异常"), options)
+	pusher.fail = true
+	api.PushNow(*NewMessage("auto-buy", "交易失败", "余额不足", Emergency), options)
+
+	testCases := []struct {
+		name  string
+		query HistoryQuery
+		want  int
+	}{
+		{name: "全部", query: HistoryQuery{}, want: 3},
+		{name: "AppID", query: HistoryQuery{AppID: "auto-buy"}, want: 2},
+		{name: "失败", query: HistoryQuery{Status: "failed"}, want: 1},
+		{name: "级别", query: HistoryQuery{Level: "emergency"}, want: 1},
+		{name: "关键字", query: HistoryQuery{Keyword: "btc"}, want: 1},
+		{name: "消息ID", query: HistoryQuery{MessageID: confirm.ID}, want: 1},
+		{name: "推送器", query: HistoryQuery{PusherName: "wechat"}, want: 0},
+		{name: "时间范围", query: HistoryQuery{Since: time.Now().Add(-time.Hour), Until: time.Now().Add(time.Hour)}, want: 3},
+		{name: "时间范围外", query: HistoryQuery{Until: time.Now().Add(-time.Hour)}, want: 0},
+	}
+	for _, tc := range testCases {
+		result, err := api.QueryHistory(tc.query)
+		if err != nil {
+			t.Fatalf("[%s] 查询失败: %v", tc.name, err)
+		}
+		if result.Total != tc.want {
+			t.Errorf("[%s] 期望%d条，实际%d条", tc.name, tc.want, result.Total)
+		}
+	}
+
+	// 分页：按时间倒序
+	page, _ := api.QueryHistory(HistoryQuery{Offset: 1, Limit: 1})
+	if page.Total != 3 || len(page.Records) != 1 || page.Records[0].Title != "日报" {
+		t.Errorf("分页结果不正确: total=%d records=%+v", page.Total, page.Records)
+	}
+	api.(*PushAPIImpl).Stop()
+
+	// 离线查询（不初始化推送器）
+	result, err := QueryHistory(cfg, HistoryQuery{AppID: "auto-buy", Status: "success"})
+	if err != nil || result.Total != 1 || result.Records[0].MessageID != confirm.ID {
+		t.Errorf("离线查询结果不正确: %+v, %v", result, err)
+	}
+}
+
+// failingPusher 可切换为失败的测试推送器
+type failingPusher struct {
+	*capturePusher
+	fail bool
+}
+
+func (fp *failingPusher) Push(msg base.Message) error {
+	if fp.fail {
+		return errors.New("模拟发送失败")
+	}
+	return fp.capturePusher.Push(msg)
+}
+
+func TestQueryHistoryRunningInstance(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("分配端口失败: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	tempDir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.WorkingDir = filepath.Join(tempDir, "working")
+	cfg.HistoryDir = filepath.Join(tempDir, "history")
+	cfg.Store = StoreConfig{Type: StoreBolt}
+	cfg.Ack = AckConfig{ListenAddr: addr}
+
+	api := NewPushAPI()
+	if err := api.InitializeWithPusher(cfg, newCapturePusher("capture")); err != nil {
+		t.Fatalf("初始化失败: %v", err)
+	}
+	options := DefaultPushOptions()
+	options.Retry = 0
+	api.PushNow(*NewNormalMessage("auto-buy", "交易确认", "已买入 0.001 BTC"), options)
+
+	// 运行中的实例占用bolt存储，查询需经过其历史记录接口
+	result, err := QueryHistory(cfg, HistoryQuery{AppID: "auto-buy"})
+	if err != nil || result.Total != 1 || result.Records[0].Title != "交易确认" {
+		t.Fatalf("实例运行中查询结果不正确: %+v, %v", result, err)
+	}
+
+	// 历史记录接口只接受本机直接发起的请求
+	req, _ := http.NewRequest(http.MethodPost, "http://"+addr+"/history", strings.NewReader("{}"))
+	req.Header.Set("X-Forwarded-For", "203.0.113.1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("经代理转发的请求期望 403，实际 %d", resp.StatusCode)
+	}
+
+	// 没有运行中的实例时直接读取存储
+	api.(*PushAPIImpl).Stop()
+	if result, err := QueryHistory(cfg, HistoryQuery{AppID: "auto-buy"}); err != nil || result.Total != 1 {
+		t.Errorf("实例停止后查询结果不正确: %+v, %v", result, err)
+	}
+}
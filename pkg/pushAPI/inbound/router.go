@@ -0,0 +1,135 @@
+package inbound
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Command 收到的一条命令
+type Command struct {
+	Name   string   // 命令名称（不含斜杠），如 run
+	Args   []string // 命令参数
+	UserID string   // 发送者ID
+	Source string   // 命令来源：telegram、webhook
+	Text   string   // 原始文本
+}
+
+// Arg 返回第 i 个参数，不存在时返回空字符串
+func (c Command) Arg(i int) string {
+	if i < len(c.Args) {
+		return c.Args[i]
+	}
+	return ""
+}
+
+// Handler 命令处理函数，返回回复内容
+type Handler func(ctx context.Context, cmd Command) (string, error)
+
+// route 已注册的命令
+type route struct {
+	usage   string
+	handler Handler
+}
+
+// Router 命令路由：校验发送者是否在白名单中，再分发给已注册的处理函数
+type Router struct {
+	mu      sync.RWMutex
+	allowed map[string]bool
+	routes  map[string]route
+}
+
+// NewRouter 创建命令路由，allowedUsers 为允许执行命令的用户ID，为空时拒绝所有命令
+func NewRouter(allowedUsers []string) *Router {
+	allowed := make(map[string]bool, len(allowedUsers))
+	for _, userID := range allowedUsers {
+		allowed[strings.TrimSpace(userID)] = true
+	}
+	return &Router{
+		allowed: allowed,
+		routes:  make(map[string]route),
+	}
+}
+
+// Handle 注册命令，name 不含斜杠，usage 用于 /help 输出
+func (r *Router) Handle(name, usage string, handler Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes[strings.ToLower(name)] = route{usage: usage, handler: handler}
+}
+
+// Authorized 判断用户是否在白名单中
+func (r *Router) Authorized(userID string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.allowed[userID]
+}
+
+// Dispatch 执行命令并返回回复内容，未授权、未知命令和执行失败都以回复文本返回
+func (r *Router) Dispatch(ctx context.Context, cmd Command) string {
+	if !r.Authorized(cmd.UserID) {
+		log.Printf("拒绝未授权用户的命令: %s %s -> %s", cmd.Source, cmd.UserID, cmd.Text)
+		return fmt.Sprintf("未授权的用户: %s", cmd.UserID)
+	}
+
+	if cmd.Name == "help" || cmd.Name == "start" {
+		return r.help()
+	}
+
+	r.mu.RLock()
+	route, exists := r.routes[cmd.Name]
+	r.mu.RUnlock()
+	if !exists {
+		return fmt.Sprintf("未知命令: /%s\n\n%s", cmd.Name, r.help())
+	}
+
+	log.Printf("执行命令: %s %s -> %s", cmd.Source, cmd.UserID, cmd.Text)
+	reply, err := route.handler(ctx, cmd)
+	if err != nil {
+		return fmt.Sprintf("执行失败: %v", err)
+	}
+	if reply == "" {
+		return "完成"
+	}
+	return reply
+}
+
+// help 列出已注册的命令
+func (r *Router) help() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.routes))
+	for name := range r.routes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteString("可用命令:")
+	for _, name := range names {
+		sb.WriteString(fmt.Sprintf("\n/%s %s", name, r.routes[name].usage))
+	}
+	return sb.String()
+}
+
+// ParseCommand 解析 "/run auto-buy" 格式的文本，群聊中的 "/run@bot_name" 会去掉机器人名；不是命令时返回 false
+func ParseCommand(text string) (Command, bool) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") || len(fields[0]) == 1 {
+		return Command{}, false
+	}
+
+	name := strings.TrimPrefix(fields[0], "/")
+	if at := strings.Index(name, "@"); at >= 0 {
+		name = name[:at]
+	}
+	return Command{
+		Name: strings.ToLower(name),
+		Args: fields[1:],
+		Text: strings.TrimSpace(text),
+	}, true
+}
@@ -0,0 +1,132 @@
+package inbound
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Config 命令接收配置
+type Config struct {
+	AllowedUsers []string       `json:"allowed_users"` // 允许执行命令的用户ID（Telegram用户ID或Webhook请求中的 user_id）
+	Telegram     TelegramConfig `json:"telegram"`      // Telegram机器人，BotToken 为空时不启用
+	Webhook      WebhookConfig  `json:"webhook"`       // 通用Webhook，Addr 为空时不启用
+}
+
+// Enabled 是否配置了任一命令来源
+func (c Config) Enabled() bool {
+	return c.Telegram.BotToken != "" || c.Webhook.Addr != ""
+}
+
+// Server 命令接收服务，管理已启用的命令来源
+type Server struct {
+	config     Config
+	router     *Router
+	telegram   *TelegramSource
+	httpServer *http.Server
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+// NewServer 创建命令接收服务
+func NewServer(cfg Config) (*Server, error) {
+	if !cfg.Enabled() {
+		return nil, fmt.Errorf("未配置命令来源（telegram.bot_token 或 webhook.addr）")
+	}
+	// 请求体中的 user_id 由发送方填写，没有签名时任何能访问监听地址的人都可以冒充白名单用户
+	if cfg.Webhook.Addr != "" && cfg.Webhook.Secret == "" {
+		return nil, fmt.Errorf("启用命令Webhook时必须配置 webhook.secret")
+	}
+	if len(cfg.AllowedUsers) == 0 {
+		log.Println("命令接收未配置 allowed_users，所有命令都将被拒绝")
+	}
+
+	server := &Server{
+		config: cfg,
+		router: NewRouter(cfg.AllowedUsers),
+	}
+	if cfg.Telegram.BotToken != "" {
+		source, err := NewTelegramSource(cfg.Telegram, server.router)
+		if err != nil {
+			return nil, err
+		}
+		server.telegram = source
+	}
+	return server, nil
+}
+
+// Handle 注册命令
+func (s *Server) Handle(name, usage string, handler Handler) {
+	s.router.Handle(name, usage, handler)
+}
+
+// Router 返回命令路由
+func (s *Server) Router() *Router {
+	return s.router
+}
+
+// Start 启动已启用的命令来源，Webhook监听失败时返回错误
+func (s *Server) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	if s.config.Webhook.Addr != "" {
+		path := s.config.Webhook.Path
+		if path == "" {
+			path = defaultWebhookPath
+		}
+		mux := http.NewServeMux()
+		mux.Handle(path, NewWebhookHandler(s.config.Webhook.Secret, s.router))
+
+		listener, err := net.Listen("tcp", s.config.Webhook.Addr)
+		if err != nil {
+			cancel()
+			return fmt.Errorf("监听命令Webhook失败: %w", err)
+		}
+		// 请求的 ctx 派生自服务的 ctx，Stop 时取消执行中的命令
+		s.httpServer = &http.Server{
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+			BaseContext:       func(net.Listener) context.Context { return ctx },
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			if err := s.httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("命令Webhook服务异常退出: %v", err)
+			}
+		}()
+		log.Printf("命令Webhook已启动: %s%s", listener.Addr(), path)
+	}
+
+	if s.telegram != nil {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.telegram.Run(ctx)
+		}()
+		log.Println("Telegram命令接收已启动")
+	}
+	return nil
+}
+
+// Stop 停止接收命令并取消执行中命令的 ctx，等待命令回复完成
+func (s *Server) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	if s.httpServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		s.httpServer.Shutdown(ctx)
+	}
+	s.wg.Wait()
+	log.Println("命令接收已停止")
+}
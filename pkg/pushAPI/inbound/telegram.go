@@ -0,0 +1,203 @@
+package inbound
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultTelegramBaseURL Telegram Bot API 默认地址
+	defaultTelegramBaseURL = "https://api.telegram.org"
+	// defaultPollTimeout 默认长轮询等待时间
+	defaultPollTimeout = 30 * time.Second
+	// pollRetryDelay 拉取失败后的重试间隔
+	pollRetryDelay = 5 * time.Second
+	// maxReplyLength Telegram 单条消息的长度上限
+	maxReplyLength = 4096
+)
+
+// TelegramConfig Telegram机器人命令接收配置
+type TelegramConfig struct {
+	BotToken    string        `json:"bot_token"`    // 机器人Token，为空时不启用
+	BaseURL     string        `json:"base_url"`     // Bot API地址，默认 https://api.telegram.org
+	PollTimeout time.Duration `json:"poll_timeout"` // 长轮询等待时间，默认30秒
+}
+
+// telegramUpdate getUpdates 返回的更新
+type telegramUpdate struct {
+	UpdateID int64 `json:"update_id"`
+	Message  *struct {
+		MessageID int64 `json:"message_id"`
+		Date      int64 `json:"date"` // 发送时间，Unix秒
+		From      *struct {
+			ID int64 `json:"id"`
+		} `json:"from"`
+		Chat struct {
+			ID int64 `json:"id"`
+		} `json:"chat"`
+		Text string `json:"text"`
+	} `json:"message"`
+}
+
+// TelegramSource 通过 getUpdates 长轮询接收Telegram命令，并回复到原会话
+type TelegramSource struct {
+	config TelegramConfig
+	router *Router
+	client *http.Client
+	offset int64
+	wg     sync.WaitGroup
+}
+
+// NewTelegramSource 创建Telegram命令接收器
+func NewTelegramSource(cfg TelegramConfig, router *Router) (*TelegramSource, error) {
+	if cfg.BotToken == "" {
+		return nil, fmt.Errorf("telegram bot_token不能为空")
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = defaultTelegramBaseURL
+	}
+	if cfg.PollTimeout <= 0 {
+		cfg.PollTimeout = defaultPollTimeout
+	}
+
+	return &TelegramSource{
+		config: cfg,
+		router: router,
+		// 请求超时需要长于长轮询等待时间
+		client: &http.Client{Timeout: cfg.PollTimeout + 10*time.Second},
+	}, nil
+}
+
+// Run 持续拉取命令直到 ctx 取消；每条命令在单独的协程中执行，返回前等待执行中的命令回复完成
+// 启动前积压的消息（如停机期间发送的 /run）只确认不执行，避免重启后重放过期命令
+func (ts *TelegramSource) Run(ctx context.Context) {
+	defer ts.wg.Wait()
+
+	started := time.Now().Unix()
+	for ctx.Err() == nil {
+		updates, err := ts.getUpdates(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("拉取telegram命令失败: %v", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(pollRetryDelay):
+			}
+			continue
+		}
+
+		for _, update := range updates {
+			ts.offset = update.UpdateID + 1
+			if update.Message != nil && update.Message.Date < started {
+				log.Printf("忽略启动前的telegram消息: %d", update.UpdateID)
+				continue
+			}
+			ts.handle(ctx, update)
+		}
+	}
+}
+
+// handle 解析并执行一条更新中的命令，非命令消息直接忽略
+func (ts *TelegramSource) handle(ctx context.Context, update telegramUpdate) {
+	message := update.Message
+	if message == nil || message.From == nil {
+		return
+	}
+	cmd, ok := ParseCommand(message.Text)
+	if !ok {
+		return
+	}
+	cmd.UserID = strconv.FormatInt(message.From.ID, 10)
+	cmd.Source = "telegram"
+
+	ts.wg.Add(1)
+	go func() {
+		defer ts.wg.Done()
+		reply := ts.router.Dispatch(ctx, cmd)
+		if err := ts.sendMessage(message.Chat.ID, message.MessageID, reply); err != nil {
+			log.Printf("回复telegram命令失败: %v", err)
+		}
+	}()
+}
+
+// getUpdates 长轮询拉取新的更新
+func (ts *TelegramSource) getUpdates(ctx context.Context) ([]telegramUpdate, error) {
+	var updates []telegramUpdate
+	err := ts.call(ctx, "getUpdates", map[string]interface{}{
+		"offset":          ts.offset,
+		"timeout":         int(ts.config.PollTimeout / time.Second),
+		"allowed_updates": []string{"message"},
+	}, &updates)
+	return updates, err
+}
+
+// sendMessage 以纯文本回复命令，超长时截断
+func (ts *TelegramSource) sendMessage(chatID, replyTo int64, text string) error {
+	if runes := []rune(text); len(runes) > maxReplyLength {
+		text = string(runes[:maxReplyLength-1]) + "…"
+	}
+	// 回复不受 Stop 取消影响，保证已执行的命令能收到结果
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return ts.call(ctx, "sendMessage", map[string]interface{}{
+		"chat_id":             chatID,
+		"text":                text,
+		"reply_to_message_id": replyTo,
+	}, nil)
+}
+
+// call 调用Bot API方法，result 非空时解析响应中的 result 字段
+func (ts *TelegramSource) call(ctx context.Context, method string, params map[string]interface{}, result interface{}) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("序列化telegram请求失败: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/bot%s/%s", strings.TrimRight(ts.config.BaseURL, "/"), ts.config.BotToken, method)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("创建telegram请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := ts.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求telegram %s失败: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("读取telegram响应失败: %w", err)
+	}
+
+	var envelope struct {
+		OK          bool            `json:"ok"`
+		Description string          `json:"description"`
+		Result      json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(respBody, &envelope); err != nil {
+		return fmt.Errorf("解析telegram响应失败: %w", err)
+	}
+	if !envelope.OK {
+		return fmt.Errorf("telegram %s失败: %s", method, envelope.Description)
+	}
+	if result != nil {
+		if err := json.Unmarshal(envelope.Result, result); err != nil {
+			return fmt.Errorf("解析telegram %s结果失败: %w", method, err)
+		}
+	}
+	return nil
+}
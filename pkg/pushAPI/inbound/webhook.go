@@ -0,0 +1,143 @@
+package inbound
+
+import (
+	"crypto/hmac"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"task_scheduler/pkg/pushAPI/push_method"
+)
+
+const (
+	// defaultWebhookPath 默认命令接收路径
+	defaultWebhookPath = "/commands"
+	// signatureHeader 签名请求头，格式同推送Webhook：sha256=<hex(HMAC-SHA256(timestamp + "." + body))>
+	signatureHeader = "X-Signature-256"
+	// signatureWindow 请求时间戳与本地时间允许的偏差，超出时拒绝，窗口内重复的签名视为重放
+	signatureWindow = 5 * time.Minute
+	// maxRequestBody 请求体大小上限
+	maxRequestBody = 64 << 10
+)
+
+// WebhookConfig 通用Webhook命令接收配置
+type WebhookConfig struct {
+	Addr   string `json:"addr"`   // 监听地址，如 :8090，为空时不启用
+	Path   string `json:"path"`   // 请求路径，默认 /commands
+	Secret string `json:"secret"` // 签名密钥，校验 X-Webhook-Timestamp 和 X-Signature-256 请求头，启用 Webhook 时必填
+}
+
+// webhookRequest 命令请求体
+type webhookRequest struct {
+	UserID string `json:"user_id"`
+	Text   string `json:"text"`
+}
+
+// webhookResponse 命令响应体
+type webhookResponse struct {
+	Reply string `json:"reply,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// WebhookHandler 接收 POST {"user_id": "...", "text": "/status"} 形式的命令，以 {"reply": "..."} 返回执行结果
+type WebhookHandler struct {
+	secret string
+	router *Router
+	now    func() time.Time
+
+	mu   sync.Mutex
+	seen map[string]time.Time // 时间窗口内已接受的签名及其过期时间，用于拒绝重放
+}
+
+// NewWebhookHandler 创建Webhook命令处理器，所有请求都必须带有效签名；secret 为空时拒绝所有请求
+func NewWebhookHandler(secret string, router *Router) *WebhookHandler {
+	return &WebhookHandler{secret: secret, router: router, now: time.Now, seen: make(map[string]time.Time)}
+}
+
+// ServeHTTP 实现 http.Handler
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, webhookResponse{Error: "只支持POST请求"})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBody))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, webhookResponse{Error: "读取请求失败"})
+		return
+	}
+	if !h.verify(body, r.Header.Get(push_method.WebhookTimestampHeader), r.Header.Get(signatureHeader)) {
+		writeJSON(w, http.StatusUnauthorized, webhookResponse{Error: "签名校验失败"})
+		return
+	}
+
+	var req webhookRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeJSON(w, http.StatusBadRequest, webhookResponse{Error: "请求格式错误"})
+		return
+	}
+	cmd, ok := ParseCommand(req.Text)
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, webhookResponse{Error: "不是有效的命令"})
+		return
+	}
+	cmd.UserID = req.UserID
+	cmd.Source = "webhook"
+
+	if !h.router.Authorized(cmd.UserID) {
+		writeJSON(w, http.StatusForbidden, webhookResponse{Reply: h.router.Dispatch(r.Context(), cmd)})
+		return
+	}
+	writeJSON(w, http.StatusOK, webhookResponse{Reply: h.router.Dispatch(r.Context(), cmd)})
+}
+
+// verify 校验签名和时间戳：时间戳需在 signatureWindow 内，同一签名只接受一次
+func (h *WebhookHandler) verify(body []byte, timestamp, header string) bool {
+	if h.secret == "" {
+		return false
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	now := h.now()
+	signedAt := time.Unix(seconds, 0)
+	if signedAt.Before(now.Add(-signatureWindow)) || signedAt.After(now.Add(signatureWindow)) {
+		return false
+	}
+	signature, err := hex.DecodeString(strings.TrimPrefix(header, "sha256="))
+	if err != nil {
+		return false
+	}
+	expected, _ := hex.DecodeString(push_method.Sign(h.secret, timestamp, body))
+	if !hmac.Equal(signature, expected) {
+		return false
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for seen, expiresAt := range h.seen {
+		if now.After(expiresAt) {
+			delete(h.seen, seen)
+		}
+	}
+	key := hex.EncodeToString(signature)
+	if _, replayed := h.seen[key]; replayed {
+		return false
+	}
+	// 时间戳超出窗口后请求会被直接拒绝，签名无需再保留
+	h.seen[key] = signedAt.Add(signatureWindow)
+	return true
+}
+
+// writeJSON 写入JSON响应
+func writeJSON(w http.ResponseWriter, status int, resp webhookResponse) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
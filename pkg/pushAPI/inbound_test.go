@@ -0,0 +1,237 @@
+package pushAPI
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"task_scheduler/pkg/pushAPI/inbound"
+	"task_scheduler/pkg/pushAPI/push_method"
+)
+
+func TestTelegramCommands(t *testing.T) {
+	var mu sync.Mutex
+	var replies []string
+	var polls int
+	done := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var params map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&params)
+
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/getUpdates"):
+			mu.Lock()
+			polls++
+			first := polls == 1
+			mu.Unlock()
+			if first {
+				// 第一条是启动前积压的命令，只确认不执行
+				now := time.Now().Unix()
+				fmt.Fprintf(w, `{"ok":true,"result":[
+					{"update_id":9,"message":{"message_id":1,"date":%d,"from":{"id":42},"chat":{"id":42},"text":"/run stale"}},
+					{"update_id":10,"message":{"message_id":2,"date":%d,"from":{"id":42},"chat":{"id":42},"text":"/run@scheduler_bot auto-buy"}},
+					{"update_id":11,"message":{"message_id":3,"date":%d,"from":{"id":7},"chat":{"id":7},"text":"/pause auto-buy"}},
+					{"update_id":12,"message":{"message_id":4,"date":%d,"from":{"id":42},"chat":{"id":42},"text":"hello"}}
+				]}`, now-3600, now, now, now)
+				return
+			}
+			if params["offset"] != float64(13) {
+				t.Errorf("期望 offset 13，实际: %v", params["offset"])
+			}
+			// 模拟长轮询：没有新消息时等待到超时或客户端断开
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			w.Write([]byte(`{"ok":true,"result":[]}`))
+		case strings.HasSuffix(r.URL.Path, "/sendMessage"):
+			mu.Lock()
+			replies = append(replies, fmt.Sprintf("%v:%v", params["chat_id"], params["text"]))
+			if len(replies) == 2 {
+				close(done)
+			}
+			mu.Unlock()
+			w.Write([]byte(`{"ok":true,"result":{}}`))
+		}
+	}))
+	defer server.Close()
+
+	commands, err := inbound.NewServer(inbound.Config{
+		AllowedUsers: []string{"42"},
+		Telegram:     inbound.TelegramConfig{BotToken: "TOKEN", BaseURL: server.URL, PollTimeout: time.Second},
+	})
+	if err != nil {
+		t.Fatalf("创建命令服务失败: %v", err)
+	}
+	var ran []string
+	commands.Handle("run", "<任务名> 立即执行任务", func(ctx context.Context, cmd inbound.Command) (string, error) {
+		ran = append(ran, cmd.Arg(0))
+		return "任务 " + cmd.Arg(0) + " 执行成功", nil
+	})
+	commands.Handle("pause", "<任务名> 暂停任务", func(ctx context.Context, cmd inbound.Command) (string, error) {
+		t.Errorf("未授权用户的命令不应执行")
+		return "", nil
+	})
+	if err := commands.Start(); err != nil {
+		t.Fatalf("启动命令服务失败: %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("等待命令回复超时")
+	}
+	commands.Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if fmt.Sprint(ran) != "[auto-buy]" {
+		t.Errorf("期望执行 auto-buy，实际: %v", ran)
+	}
+	got := strings.Join(replies, "|")
+	if !strings.Contains(got, "42:任务 auto-buy 执行成功") || !strings.Contains(got, "7:未授权的用户: 7") {
+		t.Errorf("回复不正确: %v", replies)
+	}
+}
+
+func TestWebhookCommands(t *testing.T) {
+	router := inbound.NewRouter([]string{"ops"})
+	router.Handle("status", "查看任务状态", func(ctx context.Context, cmd inbound.Command) (string, error) {
+		return "auto-buy [调度中]", nil
+	})
+	server := httptest.NewServer(inbound.NewWebhookHandler("secret", router))
+	defer server.Close()
+
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	// timestamp 为空时不签名
+	sendAt := func(body, timestamp string) (int, string) {
+		req, _ := http.NewRequest(http.MethodPost, server.URL, bytes.NewBufferString(body))
+		if timestamp != "" {
+			req.Header.Set("X-Webhook-Timestamp", timestamp)
+			req.Header.Set("X-Signature-256", "sha256="+push_method.Sign("secret", timestamp, []byte(body)))
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("请求失败: %v", err)
+		}
+		defer resp.Body.Close()
+		var result struct {
+			Reply string `json:"reply"`
+		}
+		json.NewDecoder(resp.Body).Decode(&result)
+		return resp.StatusCode, result.Reply
+	}
+	send := func(body string, sign bool) (int, string) {
+		if !sign {
+			return sendAt(body, "")
+		}
+		return sendAt(body, now)
+	}
+
+	if status, _ := send(`{"user_id":"ops","text":"/status"}`, false); status != http.StatusUnauthorized {
+		t.Errorf("未签名请求期望 401，实际: %d", status)
+	}
+	if status, _ := send(`{"user_id":"guest","text":"/status"}`, true); status != http.StatusForbidden {
+		t.Errorf("未授权用户期望 403，实际: %d", status)
+	}
+	if status, reply := send(`{"user_id":"ops","text":"/status"}`, true); status != http.StatusOK || reply != "auto-buy [调度中]" {
+		t.Errorf("期望返回任务状态，实际: %d %q", status, reply)
+	}
+	if _, reply := send(`{"user_id":"ops","text":"/unknown"}`, true); !strings.Contains(reply, "未知命令: /unknown") || !strings.Contains(reply, "/status 查看任务状态") {
+		t.Errorf("未知命令应返回帮助，实际: %q", reply)
+	}
+
+	// 同一签名请求重放、时间戳过期或签名未覆盖时间戳时拒绝
+	if status, _ := send(`{"user_id":"ops","text":"/status"}`, true); status != http.StatusUnauthorized {
+		t.Errorf("重放的请求期望 401，实际: %d", status)
+	}
+	stale := strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10)
+	if status, _ := sendAt(`{"user_id":"ops","text":"/status "}`, stale); status != http.StatusUnauthorized {
+		t.Errorf("过期时间戳期望 401，实际: %d", status)
+	}
+	req, _ := http.NewRequest(http.MethodPost, server.URL, bytes.NewBufferString(`{"user_id":"ops","text":"/status  "}`))
+	req.Header.Set("X-Webhook-Timestamp", now)
+	req.Header.Set("X-Signature-256", "sha256="+push_method.Sign("secret", stale, []byte(`{"user_id":"ops","text":"/status  "}`)))
+	if resp, err := http.DefaultClient.Do(req); err != nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("签名与时间戳不匹配时期望 401: %v", err)
+	} else {
+		resp.Body.Close()
+	}
+
+	// 未配置密钥时拒绝启用，处理器也不接受任何请求
+	if _, err := inbound.NewServer(inbound.Config{AllowedUsers: []string{"ops"}, Webhook: inbound.WebhookConfig{Addr: "127.0.0.1:0"}}); err == nil {
+		t.Error("未配置 webhook.secret 时应拒绝启用")
+	}
+	unsigned := httptest.NewServer(inbound.NewWebhookHandler("", router))
+	defer unsigned.Close()
+	resp, err := http.Post(unsigned.URL, "application/json", bytes.NewBufferString(`{"user_id":"ops","text":"/status"}`))
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("未配置密钥时期望 401，实际: %d", resp.StatusCode)
+	}
+}
+
+func TestStopCancelsRunningCommand(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("分配端口失败: %v", err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	server, err := inbound.NewServer(inbound.Config{AllowedUsers: []string{"ops"}, Webhook: inbound.WebhookConfig{Addr: addr, Secret: "secret"}})
+	if err != nil {
+		t.Fatalf("创建命令服务失败: %v", err)
+	}
+	started := make(chan struct{})
+	server.Handle("run", "执行任务", func(ctx context.Context, cmd inbound.Command) (string, error) {
+		close(started)
+		// 模拟长时间执行的任务，只在 ctx 取消时结束
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+	if err := server.Start(); err != nil {
+		t.Fatalf("启动命令服务失败: %v", err)
+	}
+
+	body := `{"user_id":"ops","text":"/run auto-buy"}`
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, _ := http.NewRequest(http.MethodPost, "http://"+addr+"/commands", bytes.NewBufferString(body))
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Signature-256", "sha256="+push_method.Sign("secret", timestamp, []byte(body)))
+	go func() {
+		if resp, err := http.DefaultClient.Do(req); err == nil {
+			resp.Body.Close()
+		}
+	}()
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("命令没有开始执行")
+	}
+
+	// 停止时取消执行中的命令，不等待到关闭超时
+	stopped := make(chan struct{})
+	go func() {
+		server.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("停止命令服务时没有取消执行中的命令")
+	}
+}
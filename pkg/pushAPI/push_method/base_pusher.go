@@ -7,7 +7,8 @@ import (
 
 // BasePusher 基础推送器
 type BasePusher struct {
-	Name string
+	Name        string
+	addressBook base.AddressBookConfig
 }
 
 // GetName 返回推送器名称
@@ -21,6 +22,10 @@ func (bp *BasePusher) Validate(options base.PushOptions) error {
 		return fmt.Errorf("接收者列表不能为空")
 	}
 
+	if err := bp.addressBook.Check(options.Receivers); err != nil {
+		return err
+	}
+
 	if options.Priority < 0 || options.Priority > 10 {
 		return fmt.Errorf("优先级必须在0-10之间")
 	}
@@ -36,3 +41,13 @@ func (bp *BasePusher) Validate(options base.PushOptions) error {
 func (bp *BasePusher) HealthCheck() bool {
 	return true
 }
+
+// SetAddressBook 设置通讯录
+func (bp *BasePusher) SetAddressBook(book base.AddressBookConfig) {
+	bp.addressBook = book
+}
+
+// receiverAddresses 通过通讯录将消息接收者解析为本渠道的地址，返回 nil 表示使用默认地址
+func (bp *BasePusher) receiverAddresses(msg base.Message) ([]string, error) {
+	return bp.addressBook.Addresses(msg.Receivers, bp.Name)
+}
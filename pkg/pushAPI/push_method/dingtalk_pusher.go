@@ -0,0 +1,119 @@
+package push_method
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"task_scheduler/pkg/pushAPI/base"
+	"time"
+)
+
+// defaultDingTalkBaseURL 钉钉开放平台默认地址
+const defaultDingTalkBaseURL = "https://oapi.dingtalk.com"
+
+// DingTalkPusher 钉钉自定义机器人推送器
+type DingTalkPusher struct {
+	BasePusher
+	config base.DingTalkConfig
+	client *http.Client
+}
+
+// dingTalkResponse 钉钉机器人响应
+type dingTalkResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+// NewDingTalkPusher 创建钉钉推送器
+func NewDingTalkPusher(cfg base.DingTalkConfig) (*DingTalkPusher, error) {
+	if cfg.AccessToken == "" {
+		return nil, fmt.Errorf("dingtalk access_token不能为空")
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = defaultDingTalkBaseURL
+	}
+
+	return &DingTalkPusher{
+		BasePusher: BasePusher{Name: "dingtalk"},
+		config:     cfg,
+		client:     newHTTPClient(cfg.Timeout),
+	}, nil
+}
+
+// Push 推送消息
+func (dp *DingTalkPusher) Push(msg base.Message) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"msgtype": "markdown",
+		"markdown": map[string]interface{}{
+			"title": msg.Title,
+			"text":  dp.buildMessageContent(msg),
+		},
+		"at": map[string]interface{}{
+			"atMobiles": dp.config.AtMobiles,
+			// 紧急消息@所有人
+			"isAtAll": msg.Level == base.Emergency,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("序列化钉钉消息失败: %w", err)
+	}
+
+	respBody, err := postBody(dp.client, dp.Name, dp.requestURL(time.Now()), "application/json", nil, body)
+	if err != nil {
+		return err
+	}
+
+	var resp dingTalkResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return fmt.Errorf("解析钉钉响应失败: %w", err)
+	}
+	if resp.ErrCode != 0 {
+		return &PlatformError{Pusher: dp.Name, Code: resp.ErrCode, Message: resp.ErrMsg}
+	}
+	return nil
+}
+
+// requestURL 构建请求地址，配置了加签密钥时附带 timestamp 和 sign
+func (dp *DingTalkPusher) requestURL(now time.Time) string {
+	query := url.Values{}
+	query.Set("access_token", dp.config.AccessToken)
+	if dp.config.Secret != "" {
+		timestamp := strconv.FormatInt(now.UnixMilli(), 10)
+		query.Set("timestamp", timestamp)
+		query.Set("sign", dingTalkSign(dp.config.Secret, timestamp))
+	}
+	return fmt.Sprintf("%s/robot/send?%s", strings.TrimRight(dp.config.BaseURL, "/"), query.Encode())
+}
+
+// buildMessageContent 构建钉钉markdown内容
+func (dp *DingTalkPusher) buildMessageContent(msg base.Message) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("### %s [%s] %s\n\n", levelIcon(msg.Level), levelLabel(msg.Level), msg.Title))
+	sb.WriteString(fmt.Sprintf("> 来源: %s\n\n", msg.AppID))
+	sb.WriteString(msg.Content)
+	// 钉钉markdown不支持表格语法，表格退化为列表
+	sb.WriteString(markdownBlocks(msg.Blocks, false))
+	sb.WriteString("\n")
+
+	if len(msg.Metadata) > 0 {
+		sb.WriteString("\n---\n\n")
+		for _, pair := range sortedMetadata(msg.Metadata) {
+			sb.WriteString(fmt.Sprintf("- **%s**: %s\n", pair.Key, pair.Value))
+		}
+	}
+
+	return sb.String()
+}
+
+// dingTalkSign 钉钉加签：base64(HmacSHA256(secret, timestamp+"\n"+secret))
+func dingTalkSign(secret, timestamp string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
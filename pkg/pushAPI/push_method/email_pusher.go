@@ -1,26 +1,300 @@
 package push_method
 
 import (
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"io"
 	"log"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
 	"task_scheduler/pkg/pushAPI/base"
+	"time"
+)
+
+const (
+	// defaultSMTPPort SMTP默认端口（STARTTLS）
+	defaultSMTPPort = 587
+	// smtpsPort SSL直连端口
+	smtpsPort = 465
 )
 
 // EmailPusher 邮件推送器
+// 未配置SMTP服务器时只记录日志；配置后发送HTML邮件，附件内容块作为真实附件，图片内嵌在正文中
 type EmailPusher struct {
 	BasePusher
+	config base.EmailConfig
 }
 
-// NewEmailPusher 创建邮件推送器
+// NewEmailPusher 创建邮件推送器（只记录日志）
 func NewEmailPusher() *EmailPusher {
 	return &EmailPusher{
 		BasePusher: BasePusher{Name: "email"},
 	}
 }
 
-// Push 推送消息
+// NewEmailPusherWithConfig 创建通过SMTP发送的邮件推送器
+func NewEmailPusherWithConfig(cfg base.EmailConfig) (*EmailPusher, error) {
+	if cfg.Host == "" {
+		return nil, fmt.Errorf("email host不能为空")
+	}
+	if cfg.Port == 0 {
+		cfg.Port = defaultSMTPPort
+	}
+	if cfg.From == "" {
+		cfg.From = cfg.Username
+	}
+	if cfg.From == "" {
+		return nil, fmt.Errorf("email from不能为空")
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultHTTPTimeout
+	}
+
+	return &EmailPusher{
+		BasePusher: BasePusher{Name: "email"},
+		config:     cfg,
+	}, nil
+}
+
+// Push 推送消息，收件人为通讯录中接收者的邮箱，未指定接收者时使用配置的默认收件人
 func (ep *EmailPusher) Push(msg base.Message) error {
-	log.Printf("邮件推送: %s - %s", msg.ID, msg.Content)
-	// 这里应该实现真实的邮件推送逻辑
-	// 例如使用SMTP发送邮件
-	return nil
+	recipients, err := ep.receiverAddresses(msg)
+	if err != nil {
+		return err
+	}
+
+	if ep.config.Host == "" {
+		log.Printf("邮件推送: %s -> %v - %s", msg.ID, recipients, msg.Content+plainBlocks(msg.Blocks))
+		return nil
+	}
+
+	if recipients == nil {
+		recipients = ep.config.To
+	}
+	if len(recipients) == 0 {
+		return fmt.Errorf("未配置邮件收件人")
+	}
+
+	body, err := ep.buildMail(msg, recipients, time.Now())
+	if err != nil {
+		return err
+	}
+	return ep.send(recipients, body)
+}
+
+// HealthCheck 健康检查（仅检查配置，不连接服务器）
+func (ep *EmailPusher) HealthCheck() bool {
+	return ep.config.Host == "" || ep.config.From != ""
+}
+
+// buildMail 构建MIME邮件：HTML正文 + 附件，来自文件或内存的图片以 cid 内嵌
+func (ep *EmailPusher) buildMail(msg base.Message, recipients []string, now time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	subject := msg.Title
+	if msg.Level == base.Emergency {
+		subject = fmt.Sprintf("[%s] %s", levelLabel(msg.Level), msg.Title)
+	}
+	fmt.Fprintf(&buf, "From: %s\r\n", ep.config.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(recipients, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", msg.ID, ep.config.Host)
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", writer.Boundary())
+
+	// 先读取全部附件，正文中的内嵌图片需要引用 Content-ID
+	type attachment struct {
+		block     base.ContentBlock
+		data      []byte
+		contentID string
+	}
+	var attachments []attachment
+	contentIDs := make(map[int]string)
+	for i, block := range msg.Blocks {
+		if !block.HasPayload() {
+			continue
+		}
+		data, err := block.Load()
+		if err != nil {
+			return nil, err
+		}
+		item := attachment{block: block, data: data}
+		if block.Type == base.BlockImage {
+			item.contentID = newContentID(ep.config.Host)
+			contentIDs[i] = item.contentID
+		}
+		attachments = append(attachments, item)
+	}
+
+	bodyPart, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/html; charset=UTF-8"},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("构建邮件正文失败: %w", err)
+	}
+	writeBase64Lines(bodyPart, []byte(emailHTML(msg, contentIDs)))
+
+	for _, item := range attachments {
+		disposition := "attachment"
+		header := textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(item.block.ContentType(), map[string]string{"name": item.block.FileName()})},
+			"Content-Transfer-Encoding": {"base64"},
+		}
+		if item.contentID != "" {
+			disposition = "inline"
+			header.Set("Content-ID", "<"+item.contentID+">")
+		}
+		header.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": item.block.FileName()}))
+
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return nil, fmt.Errorf("构建邮件附件失败: %w", err)
+		}
+		writeBase64Lines(part, item.data)
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("构建邮件失败: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// send 连接SMTP服务器发送邮件：465端口使用SSL直连，其他端口在服务器支持时升级STARTTLS
+func (ep *EmailPusher) send(recipients []string, body []byte) error {
+	addr := net.JoinHostPort(ep.config.Host, strconv.Itoa(ep.config.Port))
+	dialer := &net.Dialer{Timeout: ep.config.Timeout}
+	tlsConfig := &tls.Config{ServerName: ep.config.Host}
+
+	var conn net.Conn
+	var err error
+	if ep.config.Port == smtpsPort {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("连接SMTP服务器失败: %w", err)
+	}
+	conn.SetDeadline(time.Now().Add(ep.config.Timeout))
+
+	client, err := smtp.NewClient(conn, ep.config.Host)
+	if err != nil {
+		conn.Close()
+		return ep.smtpError("连接SMTP服务器失败", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && ep.config.Port != smtpsPort {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return ep.smtpError("STARTTLS失败", err)
+		}
+	}
+	if ep.config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", ep.config.Username, ep.config.Password, ep.config.Host)); err != nil {
+			return ep.smtpError("SMTP认证失败", err)
+		}
+	}
+
+	if err := client.Mail(ep.config.From); err != nil {
+		return ep.smtpError("设置发件人失败", err)
+	}
+	for _, recipient := range recipients {
+		if err := client.Rcpt(recipient); err != nil {
+			return ep.smtpError("设置收件人失败 "+recipient, err)
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return ep.smtpError("发送邮件失败", err)
+	}
+	if _, err := writer.Write(body); err != nil {
+		return ep.smtpError("发送邮件失败", err)
+	}
+	if err := writer.Close(); err != nil {
+		return ep.smtpError("发送邮件失败", err)
+	}
+	return client.Quit()
+}
+
+// smtpError 包装SMTP错误，5xx永久性错误转为不重试的 PlatformError
+func (ep *EmailPusher) smtpError(action string, err error) error {
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) && protoErr.Code >= 500 {
+		return &PlatformError{Pusher: ep.Name, Code: protoErr.Code, Message: fmt.Sprintf("%s: %s", action, protoErr.Msg)}
+	}
+	return fmt.Errorf("%s: %w", action, err)
+}
+
+// emailHTML 构建HTML正文，contentIDs 为内嵌图片块的下标到 Content-ID 的映射
+func emailHTML(msg base.Message, contentIDs map[int]string) string {
+	var sb strings.Builder
+	sb.WriteString("<html><body>\n")
+
+	// 模板渲染的邮件内容通常已经是HTML，纯文本内容保留换行
+	if strings.HasPrefix(strings.TrimSpace(msg.Content), "<") {
+		sb.WriteString(msg.Content)
+	} else {
+		sb.WriteString(fmt.Sprintf("<div style=\"white-space:pre-wrap\">%s</div>", html.EscapeString(msg.Content)))
+	}
+	sb.WriteString("\n")
+
+	for i, block := range msg.Blocks {
+		switch {
+		case block.Type == base.BlockFile:
+			// 附件随邮件发送，正文中不再重复
+		case block.Type == base.BlockImage && contentIDs[i] != "":
+			sb.WriteString(fmt.Sprintf("<p><img src=\"cid:%s\" alt=\"%s\"></p>\n", contentIDs[i], html.EscapeString(block.FileName())))
+		case block.Type == base.BlockImage:
+			sb.WriteString(fmt.Sprintf("<p><img src=\"%s\" alt=\"%s\"></p>\n", html.EscapeString(block.URL), html.EscapeString(block.Text)))
+		case block.Type == base.BlockTable:
+			if block.Text != "" {
+				sb.WriteString(fmt.Sprintf("<h4>%s</h4>\n", html.EscapeString(block.Text)))
+			}
+			sb.WriteString("<table border=\"1\" cellpadding=\"4\" style=\"border-collapse:collapse\">\n")
+			for _, row := range block.Rows {
+				sb.WriteString(fmt.Sprintf("<tr><th align=\"left\">%s</th><td>%s</td></tr>\n", html.EscapeString(row.Key), html.EscapeString(row.Value)))
+			}
+			sb.WriteString("</table>\n")
+		case block.Type == base.BlockLink:
+			sb.WriteString(fmt.Sprintf("<p><a href=\"%s\">%s</a></p>\n", html.EscapeString(block.URL), html.EscapeString(linkText(block))))
+		default:
+			sb.WriteString(fmt.Sprintf("<div style=\"white-space:pre-wrap\">%s</div>\n", html.EscapeString(block.Text)))
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf("<p style=\"color:#888\">来源: %s | 消息ID: %s</p>\n", html.EscapeString(msg.AppID), html.EscapeString(msg.ID)))
+	sb.WriteString("</body></html>")
+	return sb.String()
+}
+
+// writeBase64Lines 以每行76个字符写入base64编码内容
+func writeBase64Lines(w io.Writer, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		w.Write([]byte(encoded[:76] + "\r\n"))
+		encoded = encoded[76:]
+	}
+	w.Write([]byte(encoded + "\r\n"))
+}
+
+// newContentID 生成内嵌图片的 Content-ID
+func newContentID(host string) string {
+	buf := make([]byte, 8)
+	rand.Read(buf)
+	return hex.EncodeToString(buf) + "@" + host
 }
@@ -0,0 +1,66 @@
+package push_method
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// PushError 推送请求返回非2xx状态码时的错误
+type PushError struct {
+	Pusher     string // 推送器名称
+	URL        string // 请求地址
+	StatusCode int    // HTTP状态码
+	Body       string // 响应内容（截断）
+}
+
+// Error 实现error接口
+func (e *PushError) Error() string {
+	return fmt.Sprintf("%s推送失败: %s 返回状态码 %d: %s", e.Pusher, e.URL, e.StatusCode, e.Body)
+}
+
+// Retryable 判断该错误是否值得重试（5xx、429、408可重试，其余4xx不可重试）
+func (e *PushError) Retryable() bool {
+	switch {
+	case e.StatusCode >= http.StatusInternalServerError:
+		return true
+	case e.StatusCode == http.StatusTooManyRequests, e.StatusCode == http.StatusRequestTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsRetryable 判断推送错误是否可以重试
+// PushError/PlatformError 以外的错误（如网络错误）默认视为可重试
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var pushErr *PushError
+	if errors.As(err, &pushErr) {
+		return pushErr.Retryable()
+	}
+	var platformErr *PlatformError
+	if errors.As(err, &platformErr) {
+		return platformErr.Retryable()
+	}
+	return true
+}
+
+// PlatformError 推送平台返回的业务错误（HTTP 2xx 但业务码非0）
+type PlatformError struct {
+	Pusher  string // 推送器名称
+	Code    int    // 平台错误码
+	Message string // 平台错误信息
+}
+
+// Error 实现error接口
+func (e *PlatformError) Error() string {
+	return fmt.Sprintf("%s推送失败: 错误码 %d: %s", e.Pusher, e.Code, e.Message)
+}
+
+// Retryable 平台业务错误一般为配置或内容问题，不重试
+func (e *PlatformError) Retryable() bool {
+	return false
+}
@@ -0,0 +1,168 @@
+package push_method
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"task_scheduler/pkg/pushAPI/base"
+	"time"
+)
+
+// defaultFeishuBaseURL 飞书开放平台默认地址（Lark可配置为 https://open.larksuite.com）
+const defaultFeishuBaseURL = "https://open.feishu.cn"
+
+// FeishuPusher 飞书/Lark自定义机器人推送器
+type FeishuPusher struct {
+	BasePusher
+	config base.FeishuConfig
+	client *http.Client
+}
+
+// feishuResponse 飞书机器人响应
+type feishuResponse struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+// NewFeishuPusher 创建飞书推送器
+func NewFeishuPusher(cfg base.FeishuConfig) (*FeishuPusher, error) {
+	if cfg.HookToken == "" {
+		return nil, fmt.Errorf("feishu hook_token不能为空")
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = defaultFeishuBaseURL
+	}
+
+	return &FeishuPusher{
+		BasePusher: BasePusher{Name: "feishu"},
+		config:     cfg,
+		client:     newHTTPClient(cfg.Timeout),
+	}, nil
+}
+
+// Push 推送消息
+func (fp *FeishuPusher) Push(msg base.Message) error {
+	payload := map[string]interface{}{
+		"msg_type": "interactive",
+		"card":     fp.buildCard(msg),
+	}
+	if fp.config.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		payload["timestamp"] = timestamp
+		payload["sign"] = feishuSign(fp.config.Secret, timestamp)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("序列化飞书消息失败: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/open-apis/bot/v2/hook/%s", strings.TrimRight(fp.config.BaseURL, "/"), fp.config.HookToken)
+	respBody, err := postBody(fp.client, fp.Name, url, "application/json", nil, body)
+	if err != nil {
+		return err
+	}
+
+	var resp feishuResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return fmt.Errorf("解析飞书响应失败: %w", err)
+	}
+	if resp.Code != 0 {
+		return &PlatformError{Pusher: fp.Name, Code: resp.Code, Message: resp.Msg}
+	}
+	return nil
+}
+
+// buildCard 构建飞书消息卡片
+func (fp *FeishuPusher) buildCard(msg base.Message) map[string]interface{} {
+	template := "blue"
+	if msg.Level == base.Emergency {
+		template = "red"
+	}
+
+	elements := []map[string]interface{}{
+		{
+			"tag":  "div",
+			"text": map[string]interface{}{"tag": "lark_md", "content": msg.Content},
+		},
+	}
+
+	elements = append(elements, feishuBlockElements(msg.Blocks)...)
+
+	if len(msg.Metadata) > 0 {
+		var fields []map[string]interface{}
+		for _, pair := range sortedMetadata(msg.Metadata) {
+			fields = append(fields, map[string]interface{}{
+				"is_short": true,
+				"text":     map[string]interface{}{"tag": "lark_md", "content": fmt.Sprintf("**%s**\n%s", pair.Key, pair.Value)},
+			})
+		}
+		elements = append(elements, map[string]interface{}{"tag": "hr"})
+		elements = append(elements, map[string]interface{}{"tag": "div", "fields": fields})
+	}
+
+	elements = append(elements, map[string]interface{}{
+		"tag": "note",
+		"elements": []map[string]interface{}{
+			{"tag": "plain_text", "content": fmt.Sprintf("来源: %s | 消息ID: %s", msg.AppID, msg.ID)},
+		},
+	})
+
+	return map[string]interface{}{
+		"header": map[string]interface{}{
+			"title":    map[string]interface{}{"tag": "plain_text", "content": fmt.Sprintf("%s [%s] %s", levelIcon(msg.Level), levelLabel(msg.Level), msg.Title)},
+			"template": template,
+		},
+		"elements": elements,
+	}
+}
+
+// feishuBlockElements 将内容块渲染为卡片元素：表格使用并排字段，图片和链接使用 lark_md 链接，附件只显示文件名
+func feishuBlockElements(blocks []base.ContentBlock) []map[string]interface{} {
+	var elements []map[string]interface{}
+	for _, block := range blocks {
+		switch {
+		case block.Type == base.BlockTable && !block.HasPayload():
+			var fields []map[string]interface{}
+			for _, row := range block.Rows {
+				fields = append(fields, map[string]interface{}{
+					"is_short": true,
+					"text":     map[string]interface{}{"tag": "lark_md", "content": fmt.Sprintf("**%s**\n%s", row.Key, row.Value)},
+				})
+			}
+			element := map[string]interface{}{"tag": "div", "fields": fields}
+			if block.Text != "" {
+				element["text"] = map[string]interface{}{"tag": "lark_md", "content": fmt.Sprintf("**%s**", block.Text)}
+			}
+			elements = append(elements, element)
+		case block.HasPayload():
+			elements = append(elements, map[string]interface{}{
+				"tag":      "note",
+				"elements": []map[string]interface{}{{"tag": "plain_text", "content": attachmentLabel(block)}},
+			})
+		case block.Type == base.BlockLink, block.Type == base.BlockImage:
+			// 卡片图片需要先上传获取 image_key，网络图片以链接展示
+			elements = append(elements, map[string]interface{}{
+				"tag":  "div",
+				"text": map[string]interface{}{"tag": "lark_md", "content": fmt.Sprintf("[%s](%s)", linkText(block), block.URL)},
+			})
+		default:
+			elements = append(elements, map[string]interface{}{
+				"tag":  "div",
+				"text": map[string]interface{}{"tag": "lark_md", "content": block.Text},
+			})
+		}
+	}
+	return elements
+}
+
+// feishuSign 飞书加签：base64(HmacSHA256(key=timestamp+"\n"+secret, 空消息))
+func feishuSign(secret, timestamp string) string {
+	mac := hmac.New(sha256.New, []byte(timestamp+"\n"+secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,185 @@
+package push_method
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"task_scheduler/pkg/pushAPI/base"
+	"time"
+)
+
+// levelLabel 返回消息级别的中文标识
+func levelLabel(level base.MessageLevel) string {
+	switch level {
+	case base.Emergency:
+		return "紧急"
+	default:
+		return "普通"
+	}
+}
+
+// levelIcon 返回消息级别对应的图标
+func levelIcon(level base.MessageLevel) string {
+	switch level {
+	case base.Emergency:
+		return "🚨"
+	default:
+		return "📢"
+	}
+}
+
+// MetadataPair 元数据键值对
+type MetadataPair struct {
+	Key   string
+	Value string
+}
+
+// sortedMetadata 按键名排序元数据，保证输出稳定
+func sortedMetadata(metadata map[string]interface{}) []MetadataPair {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]MetadataPair, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, MetadataPair{Key: key, Value: fmt.Sprintf("%v", metadata[key])})
+	}
+	return pairs
+}
+
+// LayoutData 排版模板可用的数据
+type LayoutData struct {
+	ID         string         // 消息ID
+	AppID      string         // 发送方ID
+	Title      string         // 标题
+	Content    string         // 内容（含渲染为Markdown的内容块）
+	Level      string         // 级别（normal/emergency）
+	LevelLabel string         // 级别中文标识（普通/紧急）
+	Time       string         // 渲染时间 2006-01-02 15:04:05
+	Metadata   []MetadataPair // 按键名排序的元数据（.Key/.Value）
+}
+
+// newLayoutData 从消息构建排版数据
+func newLayoutData(msg base.Message) LayoutData {
+	return LayoutData{
+		ID:         msg.ID,
+		AppID:      msg.AppID,
+		Title:      msg.Title,
+		Content:    msg.Content + markdownBlocks(msg.Blocks, true),
+		Level:      msg.Level.String(),
+		LevelLabel: levelLabel(msg.Level),
+		Time:       time.Now().Format("2006-01-02 15:04:05"),
+		Metadata:   sortedMetadata(msg.Metadata),
+	}
+}
+
+// linkText 链接或图片的显示文字，未设置时使用地址
+func linkText(block base.ContentBlock) string {
+	if block.Text != "" {
+		return block.Text
+	}
+	return block.URL
+}
+
+// attachmentLabel 附件在不支持附件的渠道中的文字说明
+func attachmentLabel(block base.ContentBlock) string {
+	if block.Type == base.BlockImage {
+		return "🖼 图片: " + block.FileName()
+	}
+	return "📎 附件: " + block.FileName()
+}
+
+// markdownBlocks 将内容块渲染为Markdown，每块前空一行；tables 为 false 时表格退化为列表（部分平台不支持表格语法）
+func markdownBlocks(blocks []base.ContentBlock, tables bool) string {
+	var sb strings.Builder
+	for _, block := range blocks {
+		sb.WriteString("\n\n")
+		switch {
+		case block.HasPayload():
+			sb.WriteString(attachmentLabel(block))
+		case block.Type == base.BlockTable:
+			if block.Text != "" {
+				sb.WriteString(fmt.Sprintf("**%s**\n\n", block.Text))
+			}
+			if tables {
+				sb.WriteString("| 项目 | 值 |\n| --- | --- |")
+				for _, row := range block.Rows {
+					sb.WriteString(fmt.Sprintf("\n| %s | %s |", escapeTableCell(row.Key), escapeTableCell(row.Value)))
+				}
+			} else {
+				for i, row := range block.Rows {
+					if i > 0 {
+						sb.WriteString("\n")
+					}
+					sb.WriteString(fmt.Sprintf("- **%s**: %s", row.Key, row.Value))
+				}
+			}
+		case block.Type == base.BlockLink:
+			sb.WriteString(fmt.Sprintf("[%s](%s)", linkText(block), block.URL))
+		case block.Type == base.BlockImage:
+			sb.WriteString(fmt.Sprintf("![%s](%s)", block.Text, block.URL))
+		default:
+			sb.WriteString(block.Text)
+		}
+	}
+	return sb.String()
+}
+
+// plainBlocks 将内容块渲染为纯文本，每块前空一行
+func plainBlocks(blocks []base.ContentBlock) string {
+	var sb strings.Builder
+	for _, block := range blocks {
+		sb.WriteString("\n\n")
+		switch {
+		case block.HasPayload():
+			sb.WriteString(attachmentLabel(block))
+		case block.Type == base.BlockTable:
+			if block.Text != "" {
+				sb.WriteString(block.Text + "\n")
+			}
+			for i, row := range block.Rows {
+				if i > 0 {
+					sb.WriteString("\n")
+				}
+				sb.WriteString(fmt.Sprintf("%s: %s", row.Key, row.Value))
+			}
+		case block.Type == base.BlockLink, block.Type == base.BlockImage:
+			if block.Text != "" {
+				sb.WriteString(block.Text + ": ")
+			}
+			sb.WriteString(block.URL)
+		default:
+			sb.WriteString(block.Text)
+		}
+	}
+	return sb.String()
+}
+
+// escapeTableCell 转义Markdown表格单元格中的竖线和换行
+func escapeTableCell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	return strings.ReplaceAll(text, "\n", " ")
+}
+
+// loadBlockPayloads 读取附件文件内容，返回把文件路径替换为内容的副本（接收方无法访问本地路径）
+func loadBlockPayloads(blocks []base.ContentBlock) ([]base.ContentBlock, error) {
+	if len(blocks) == 0 {
+		return nil, nil
+	}
+	loaded := make([]base.ContentBlock, len(blocks))
+	for i, block := range blocks {
+		if block.HasPayload() && block.Path != "" {
+			data, err := block.Load()
+			if err != nil {
+				return nil, err
+			}
+			block.Name = block.FileName()
+			block.Data = data
+			block.Path = ""
+		}
+		loaded[i] = block
+	}
+	return loaded, nil
+}
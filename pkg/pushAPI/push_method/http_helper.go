@@ -0,0 +1,61 @@
+package push_method
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// defaultHTTPTimeout HTTP推送默认超时时间
+const defaultHTTPTimeout = 10 * time.Second
+
+// maxErrorBodyLen 错误信息中保留的响应内容最大长度
+const maxErrorBodyLen = 512
+
+// newHTTPClient 创建带超时的HTTP客户端
+func newHTTPClient(timeout time.Duration) *http.Client {
+	if timeout <= 0 {
+		timeout = defaultHTTPTimeout
+	}
+	return &http.Client{Timeout: timeout}
+}
+
+// postBody 发送POST请求，非2xx响应返回 *PushError
+func postBody(client *http.Client, pusherName, url, contentType string, headers map[string]string, body []byte) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s推送请求失败: %w", pusherName, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取响应失败: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		text := string(respBody)
+		if len(text) > maxErrorBodyLen {
+			text = text[:maxErrorBodyLen]
+		}
+		return nil, &PushError{
+			Pusher:     pusherName,
+			URL:        url,
+			StatusCode: resp.StatusCode,
+			Body:       text,
+		}
+	}
+
+	return respBody, nil
+}
@@ -9,3 +9,20 @@ type IPusher interface {
 	Validate(options base.PushOptions) error // 参数验证
 	HealthCheck() bool                       // 健康检查
 }
+
+// LayoutSetter 支持自定义排版模板的推送器
+type LayoutSetter interface {
+	SetLayoutTemplate(text string) error
+}
+
+// AddressBookSetter 支持通过通讯录解析接收者的推送器
+type AddressBookSetter interface {
+	SetAddressBook(book base.AddressBookConfig)
+}
+
+// MultiTargetPusher 向多个目标（地址、会话等）分别推送的推送器
+// 重试时只向推送失败的目标重发，避免已成功的目标收到重复消息
+type MultiTargetPusher interface {
+	Targets(msg base.Message) ([]string, error)   // 消息的推送目标
+	PushTo(msg base.Message, target string) error // 推送到单个目标
+}
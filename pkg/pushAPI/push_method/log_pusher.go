@@ -20,6 +20,6 @@ func NewLogPusher() *LogPusher {
 
 // Push 推送消息
 func (lp *LogPusher) Push(msg base.Message) error {
-	log.Printf("日志推送 [%s]: %s - %s", time.Now().Format("2006-01-02 15:04:05"), msg.ID, msg.Content)
+	log.Printf("日志推送 [%s]: %s - %s", time.Now().Format("2006-01-02 15:04:05"), msg.ID, msg.Content+plainBlocks(msg.Blocks))
 	return nil
 }
@@ -0,0 +1,157 @@
+package push_method
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"task_scheduler/pkg/pushAPI/base"
+)
+
+// SlackPusher Slack Incoming Webhook推送器
+type SlackPusher struct {
+	BasePusher
+	config base.SlackConfig
+	client *http.Client
+}
+
+// NewSlackPusher 创建Slack推送器
+func NewSlackPusher(cfg base.SlackConfig) (*SlackPusher, error) {
+	if cfg.WebhookURL == "" {
+		return nil, fmt.Errorf("slack webhook_url不能为空")
+	}
+
+	return &SlackPusher{
+		BasePusher: BasePusher{Name: "slack"},
+		config:     cfg,
+		client:     newHTTPClient(cfg.Timeout),
+	}, nil
+}
+
+// Push 推送消息
+func (sp *SlackPusher) Push(msg base.Message) error {
+	body, err := json.Marshal(sp.buildPayload(msg))
+	if err != nil {
+		return fmt.Errorf("序列化slack消息失败: %w", err)
+	}
+
+	// Incoming Webhook 成功时返回纯文本 "ok"，失败时返回非2xx状态码
+	_, err = postBody(sp.client, sp.Name, sp.config.WebhookURL, "application/json", nil, body)
+	return err
+}
+
+// buildPayload 构建Slack Block Kit消息
+func (sp *SlackPusher) buildPayload(msg base.Message) map[string]interface{} {
+	color := "#2eb886"
+	if msg.Level == base.Emergency {
+		color = "#d00000"
+	}
+
+	blocks := []map[string]interface{}{
+		{
+			"type": "header",
+			"text": map[string]interface{}{
+				"type": "plain_text",
+				"text": fmt.Sprintf("%s [%s] %s", levelIcon(msg.Level), levelLabel(msg.Level), msg.Title),
+			},
+		},
+		{
+			"type": "section",
+			"text": map[string]interface{}{"type": "mrkdwn", "text": msg.Content},
+		},
+	}
+
+	blocks = append(blocks, slackBlocks(msg.Blocks)...)
+
+	if len(msg.Metadata) > 0 {
+		var fields []map[string]interface{}
+		for _, pair := range sortedMetadata(msg.Metadata) {
+			fields = append(fields, map[string]interface{}{
+				"type": "mrkdwn",
+				"text": fmt.Sprintf("*%s*\n%s", pair.Key, pair.Value),
+			})
+		}
+		// Slack 限制每个 section 最多10个字段
+		for start := 0; start < len(fields); start += 10 {
+			end := start + 10
+			if end > len(fields) {
+				end = len(fields)
+			}
+			blocks = append(blocks, map[string]interface{}{"type": "section", "fields": fields[start:end]})
+		}
+	}
+
+	blocks = append(blocks, map[string]interface{}{
+		"type": "context",
+		"elements": []map[string]interface{}{
+			{"type": "mrkdwn", "text": fmt.Sprintf("来源: %s | 消息ID: %s", msg.AppID, msg.ID)},
+		},
+	})
+
+	payload := map[string]interface{}{
+		"text": fmt.Sprintf("[%s] %s", levelLabel(msg.Level), msg.Title),
+		"attachments": []map[string]interface{}{
+			{"color": color, "blocks": blocks},
+		},
+	}
+	if sp.config.Channel != "" {
+		payload["channel"] = sp.config.Channel
+	}
+	if sp.config.Username != "" {
+		payload["username"] = sp.config.Username
+	}
+	return payload
+}
+
+// slackBlocks 将内容块渲染为Block Kit：表格使用字段，网络图片使用 image 块，
+// Incoming Webhook 无法上传文件，附件只显示文件名
+func slackBlocks(blocks []base.ContentBlock) []map[string]interface{} {
+	var result []map[string]interface{}
+	for _, block := range blocks {
+		switch {
+		case block.HasPayload():
+			result = append(result, map[string]interface{}{
+				"type":     "context",
+				"elements": []map[string]interface{}{{"type": "mrkdwn", "text": attachmentLabel(block)}},
+			})
+		case block.Type == base.BlockTable:
+			var fields []map[string]interface{}
+			for _, row := range block.Rows {
+				fields = append(fields, map[string]interface{}{
+					"type": "mrkdwn",
+					"text": fmt.Sprintf("*%s*\n%s", row.Key, row.Value),
+				})
+			}
+			if block.Text != "" {
+				result = append(result, map[string]interface{}{
+					"type": "section",
+					"text": map[string]interface{}{"type": "mrkdwn", "text": fmt.Sprintf("*%s*", block.Text)},
+				})
+			}
+			// Slack 限制每个 section 最多10个字段
+			for start := 0; start < len(fields); start += 10 {
+				end := start + 10
+				if end > len(fields) {
+					end = len(fields)
+				}
+				result = append(result, map[string]interface{}{"type": "section", "fields": fields[start:end]})
+			}
+		case block.Type == base.BlockImage:
+			result = append(result, map[string]interface{}{
+				"type":      "image",
+				"image_url": block.URL,
+				"alt_text":  linkText(block),
+			})
+		case block.Type == base.BlockLink:
+			result = append(result, map[string]interface{}{
+				"type": "section",
+				"text": map[string]interface{}{"type": "mrkdwn", "text": fmt.Sprintf("<%s|%s>", block.URL, linkText(block))},
+			})
+		default:
+			result = append(result, map[string]interface{}{
+				"type": "section",
+				"text": map[string]interface{}{"type": "mrkdwn", "text": block.Text},
+			})
+		}
+	}
+	return result
+}
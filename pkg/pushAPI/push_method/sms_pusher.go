@@ -19,7 +19,12 @@ func NewSMSPusher() *SMSPusher {
 
 // Push 推送消息
 func (sp *SMSPusher) Push(msg base.Message) error {
-	log.Printf("短信推送: %s - %s", msg.ID, msg.Content)
+	phones, err := sp.receiverAddresses(msg)
+	if err != nil {
+		return err
+	}
+	// 短信只能发送文本，内容块退化为纯文本
+	log.Printf("短信推送: %s -> %v - %s", msg.ID, phones, msg.Content+plainBlocks(msg.Blocks))
 	// 这里应该实现真实的短信推送逻辑
 	// 例如调用短信服务商API
 	return nil
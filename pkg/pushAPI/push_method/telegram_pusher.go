@@ -0,0 +1,203 @@
+package push_method
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"task_scheduler/pkg/pushAPI/base"
+)
+
+// defaultTelegramBaseURL Telegram Bot API 默认地址
+const defaultTelegramBaseURL = "https://api.telegram.org"
+
+// TelegramPusher Telegram机器人推送器
+type TelegramPusher struct {
+	BasePusher
+	config base.TelegramConfig
+	client *http.Client
+}
+
+// telegramResponse Telegram Bot API 响应
+type telegramResponse struct {
+	OK          bool   `json:"ok"`
+	ErrorCode   int    `json:"error_code"`
+	Description string `json:"description"`
+}
+
+// NewTelegramPusher 创建Telegram推送器
+func NewTelegramPusher(cfg base.TelegramConfig) (*TelegramPusher, error) {
+	if cfg.BotToken == "" {
+		return nil, fmt.Errorf("telegram bot_token不能为空")
+	}
+	if len(cfg.ChatIDs) == 0 {
+		return nil, fmt.Errorf("telegram chat_ids不能为空")
+	}
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = defaultTelegramBaseURL
+	}
+
+	return &TelegramPusher{
+		BasePusher: BasePusher{Name: "telegram"},
+		config:     cfg,
+		client:     newHTTPClient(cfg.Timeout),
+	}, nil
+}
+
+// Push 推送消息到接收者的会话，未指定接收者时推送到所有配置的会话；附件在消息之后逐个以文件发送
+func (tp *TelegramPusher) Push(msg base.Message) error {
+	chatIDs, err := tp.Targets(msg)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, chatID := range chatIDs {
+		if err := tp.PushTo(msg, chatID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Targets 返回接收者的会话，未指定接收者时返回所有配置的会话
+func (tp *TelegramPusher) Targets(msg base.Message) ([]string, error) {
+	chatIDs, err := tp.receiverAddresses(msg)
+	if err != nil {
+		return nil, err
+	}
+	if chatIDs == nil {
+		chatIDs = tp.config.ChatIDs
+	}
+	return chatIDs, nil
+}
+
+// PushTo 推送消息和附件到单个会话
+func (tp *TelegramPusher) PushTo(msg base.Message, chatID string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"chat_id":                  chatID,
+		"text":                     tp.buildMessageContent(msg),
+		"parse_mode":               "HTML",
+		"disable_web_page_preview": true,
+	})
+	if err != nil {
+		return fmt.Errorf("序列化telegram消息失败: %w", err)
+	}
+
+	if err := tp.post(tp.methodURL("sendMessage"), "application/json", body); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, block := range msg.Blocks {
+		if block.HasPayload() {
+			if err := tp.sendDocument(chatID, block); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// sendDocument 以文件形式发送附件
+func (tp *TelegramPusher) sendDocument(chatID string, block base.ContentBlock) error {
+	data, err := block.Load()
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	writer.WriteField("chat_id", chatID)
+	part, err := writer.CreateFormFile("document", block.FileName())
+	if err != nil {
+		return fmt.Errorf("构建telegram附件失败: %w", err)
+	}
+	part.Write(data)
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("构建telegram附件失败: %w", err)
+	}
+
+	return tp.post(tp.methodURL("sendDocument"), writer.FormDataContentType(), buf.Bytes())
+}
+
+// methodURL Bot API 方法地址
+func (tp *TelegramPusher) methodURL(method string) string {
+	return fmt.Sprintf("%s/bot%s/%s", strings.TrimRight(tp.config.BaseURL, "/"), tp.config.BotToken, method)
+}
+
+// post 调用Bot API并检查业务结果
+func (tp *TelegramPusher) post(url, contentType string, body []byte) error {
+	respBody, err := postBody(tp.client, tp.Name, url, contentType, nil, body)
+	if err != nil {
+		return err
+	}
+
+	var resp telegramResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return fmt.Errorf("解析telegram响应失败: %w", err)
+	}
+	if !resp.OK {
+		return &PlatformError{Pusher: tp.Name, Code: resp.ErrorCode, Message: resp.Description}
+	}
+	return nil
+}
+
+// buildMessageContent 构建Telegram HTML格式内容
+func (tp *TelegramPusher) buildMessageContent(msg base.Message) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s <b>[%s] %s</b>\n", levelIcon(msg.Level), levelLabel(msg.Level), html.EscapeString(msg.Title)))
+	sb.WriteString(fmt.Sprintf("<i>来源: %s</i>\n\n", html.EscapeString(msg.AppID)))
+	sb.WriteString(html.EscapeString(msg.Content))
+	sb.WriteString(telegramBlocks(msg.Blocks))
+
+	if len(msg.Metadata) > 0 {
+		sb.WriteString("\n")
+		for _, pair := range sortedMetadata(msg.Metadata) {
+			sb.WriteString(fmt.Sprintf("\n<b>%s</b>: <code>%s</code>", html.EscapeString(pair.Key), html.EscapeString(pair.Value)))
+		}
+	}
+
+	return sb.String()
+}
+
+// telegramBlocks 将内容块渲染为Telegram HTML：表格使用等宽的键值列表，附件以文件单独发送，这里只列出文件名
+func telegramBlocks(blocks []base.ContentBlock) string {
+	var sb strings.Builder
+	for _, block := range blocks {
+		sb.WriteString("\n\n")
+		switch {
+		case block.HasPayload():
+			sb.WriteString(html.EscapeString(attachmentLabel(block)))
+		case block.Type == base.BlockTable:
+			if block.Text != "" {
+				sb.WriteString(fmt.Sprintf("<b>%s</b>\n", html.EscapeString(block.Text)))
+			}
+			width := 0
+			for _, row := range block.Rows {
+				if n := len([]rune(row.Key)); n > width {
+					width = n
+				}
+			}
+			sb.WriteString("<pre>")
+			for i, row := range block.Rows {
+				if i > 0 {
+					sb.WriteString("\n")
+				}
+				padding := strings.Repeat(" ", width-len([]rune(row.Key)))
+				sb.WriteString(html.EscapeString(row.Key) + padding + "  " + html.EscapeString(row.Value))
+			}
+			sb.WriteString("</pre>")
+		case block.Type == base.BlockLink, block.Type == base.BlockImage:
+			sb.WriteString(fmt.Sprintf("<a href=\"%s\">%s</a>", html.EscapeString(block.URL), html.EscapeString(linkText(block))))
+		default:
+			sb.WriteString(html.EscapeString(block.Text))
+		}
+	}
+	return sb.String()
+}
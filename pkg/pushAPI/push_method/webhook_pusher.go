@@ -0,0 +1,172 @@
+package push_method
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"task_scheduler/pkg/pushAPI/base"
+	"text/template"
+	"time"
+)
+
+const (
+	// defaultSignatureHeader 默认签名请求头
+	defaultSignatureHeader = "X-Signature-256"
+	// WebhookTimestampHeader 请求时间戳（Unix秒）请求头，参与签名，接收方据此拒绝过期或重放的请求
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+)
+
+// WebhookPusher 通用Webhook推送器
+type WebhookPusher struct {
+	BasePusher
+	config       base.WebhookConfig
+	bodyTemplate *template.Template
+	client       *http.Client
+}
+
+// webhookPayload 默认的JSON推送内容
+type webhookPayload struct {
+	ID        string                 `json:"id"`
+	AppID     string                 `json:"app_id"`
+	Title     string                 `json:"title"`
+	Content   string                 `json:"content"`
+	Blocks    []base.ContentBlock    `json:"blocks,omitempty"` // 附件内容以base64编码的 data 字段发送
+	Level     string                 `json:"level"`
+	Metadata  map[string]interface{} `json:"metadata,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+	SentAt    time.Time              `json:"sent_at"`
+}
+
+// NewWebhookPusher 创建Webhook推送器
+func NewWebhookPusher(cfg base.WebhookConfig) (*WebhookPusher, error) {
+	if len(cfg.URLs) == 0 {
+		return nil, fmt.Errorf("webhook地址不能为空")
+	}
+
+	wp := &WebhookPusher{
+		BasePusher: BasePusher{Name: "webhook"},
+		config:     cfg,
+		client:     newHTTPClient(cfg.Timeout),
+	}
+
+	if cfg.BodyTemplate != "" {
+		tmpl, err := template.New("webhook").Funcs(template.FuncMap{
+			"json": toJSON,
+		}).Parse(cfg.BodyTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("解析webhook模板失败: %w", err)
+		}
+		wp.bodyTemplate = tmpl
+	}
+
+	return wp, nil
+}
+
+// Push 推送消息到所有配置的地址
+func (wp *WebhookPusher) Push(msg base.Message) error {
+	var errs []error
+	for _, url := range wp.config.URLs {
+		if err := wp.PushTo(msg, url); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Targets 返回所有配置的地址
+func (wp *WebhookPusher) Targets(msg base.Message) ([]string, error) {
+	return wp.config.URLs, nil
+}
+
+// PushTo 推送消息到单个地址，每次请求重新生成时间戳和签名
+func (wp *WebhookPusher) PushTo(msg base.Message, url string) error {
+	body, err := wp.buildBody(msg)
+	if err != nil {
+		return err
+	}
+
+	headers := make(map[string]string, len(wp.config.Headers)+2)
+	for key, value := range wp.config.Headers {
+		headers[key] = value
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	headers[WebhookTimestampHeader] = timestamp
+	if wp.config.Secret != "" {
+		headerName := wp.config.SignatureHeader
+		if headerName == "" {
+			headerName = defaultSignatureHeader
+		}
+		headers[headerName] = "sha256=" + Sign(wp.config.Secret, timestamp, body)
+	}
+
+	contentType := wp.config.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+
+	_, err = postBody(wp.client, wp.Name, url, contentType, headers, body)
+	return err
+}
+
+// HealthCheck 健康检查（仅检查配置，不发送请求）
+func (wp *WebhookPusher) HealthCheck() bool {
+	return len(wp.config.URLs) > 0
+}
+
+// buildBody 构建请求体，优先使用自定义模板
+func (wp *WebhookPusher) buildBody(msg base.Message) ([]byte, error) {
+	blocks, err := loadBlockPayloads(msg.Blocks)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := webhookPayload{
+		ID:        msg.ID,
+		AppID:     msg.AppID,
+		Title:     msg.Title,
+		Content:   msg.Content,
+		Blocks:    blocks,
+		Level:     msg.Level.String(),
+		Metadata:  msg.Metadata,
+		CreatedAt: msg.CreatedAt,
+		SentAt:    msg.SentAt,
+	}
+
+	if wp.bodyTemplate == nil {
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("序列化webhook消息失败: %w", err)
+		}
+		return body, nil
+	}
+
+	var buf bytes.Buffer
+	if err := wp.bodyTemplate.Execute(&buf, payload); err != nil {
+		return nil, fmt.Errorf("渲染webhook模板失败: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Sign 使用HMAC-SHA256对 timestamp + "." + body 签名，返回十六进制字符串
+// timestamp 为 X-Webhook-Timestamp 请求头的值，接收方用同一函数校验签名
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// toJSON 模板函数：将值序列化为JSON字符串
+func toJSON(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
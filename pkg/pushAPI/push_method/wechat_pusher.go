@@ -1,16 +1,26 @@
 package push_method
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"strings"
 	"task_scheduler/pkg/pushAPI/base"
-	"time"
+	"text/template"
 
 	serverchan "github.com/easychen/serverchan-sdk-golang"
 )
 
+// defaultWeChatLayout 微信默认排版
+var defaultWeChatLayout = template.Must(template.New("wechat_layout").Parse(
+	"【{{.LevelLabel}}】\n时间: {{.Time}}\n\n来源: {{.AppID}}\n\n消息ID: {{.ID}}\n\n内容: \n\n\n{{.Content}}\n\n" +
+		"{{if .Metadata}}\n【元数据】\n{{range .Metadata}}{{.Key}}: {{.Value}}\n{{end}}{{end}}"))
+
 // WeChatPusher 微信推送器
 type WeChatPusher struct {
-	sendKey string
+	sendKey     string
+	layout      *template.Template
+	addressBook base.AddressBookConfig
 }
 
 // NewWeChatPusher 创建微信推送器
@@ -32,13 +42,38 @@ func (w *WeChatPusher) GetName() string {
 	return "wechat"
 }
 
-// Push 推送消息
+// Push 推送消息，接收者通过通讯录解析为各自的sendKey，未指定时使用默认sendKey
 func (w *WeChatPusher) Push(msg base.Message) error {
-	// 构建消息内容
-	content := w.buildMessageContent(msg)
+	sendKeys, err := w.Targets(msg)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, sendKey := range sendKeys {
+		if err := w.PushTo(msg, sendKey); err != nil {
+			errs = append(errs, err)
+		}
+	}
 
-	// 发送消息
-	resp, err := serverchan.ScSend(w.sendKey, msg.Title, content, nil)
+	return errors.Join(errs...)
+}
+
+// Targets 返回接收者的sendKey，未指定接收者时返回默认sendKey
+func (w *WeChatPusher) Targets(msg base.Message) ([]string, error) {
+	sendKeys, err := w.addressBook.Addresses(msg.Receivers, w.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if sendKeys == nil {
+		sendKeys = []string{w.sendKey}
+	}
+	return sendKeys, nil
+}
+
+// PushTo 使用单个sendKey推送消息
+func (w *WeChatPusher) PushTo(msg base.Message, sendKey string) error {
+	resp, err := serverchan.ScSend(sendKey, msg.Title, w.buildMessageContent(msg), nil)
 	if err != nil {
 		return fmt.Errorf("微信推送失败: %w", err)
 	}
@@ -47,14 +82,12 @@ func (w *WeChatPusher) Push(msg base.Message) error {
 	if resp != nil && resp.Code != 0 {
 		return fmt.Errorf("微信推送失败: %s", resp.Message)
 	}
-
 	return nil
 }
 
 // Validate 验证推送选项
 func (w *WeChatPusher) Validate(options base.PushOptions) error {
-
-	return nil
+	return w.addressBook.Check(options.Receivers)
 }
 
 // HealthCheck 健康检查
@@ -74,36 +107,34 @@ func (w *WeChatPusher) HealthCheck() bool {
 
 // buildMessageContent 构建消息内容
 func (w *WeChatPusher) buildMessageContent(msg base.Message) string {
-	content := msg.Content
-
-	// 添加消息级别标识
-	levelStr := "普通"
-	switch msg.Level {
-	case base.Emergency:
-		levelStr = "紧急"
-	case base.Normal:
-		levelStr = "普通"
+	layout := w.layout
+	if layout == nil {
+		layout = defaultWeChatLayout
 	}
 
-	// 添加时间戳
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-
-	// 构建完整内容
-	fullContent := fmt.Sprintf("【%s】\n", levelStr)
-	fullContent += fmt.Sprintf("时间: %s\n\n", timestamp)
-	fullContent += fmt.Sprintf("来源: %s\n\n", msg.AppID)
-	fullContent += fmt.Sprintf("消息ID: %s\n\n", msg.ID)
-	fullContent += fmt.Sprintf("内容: \n\n\n%s\n\n", content)
-
-	// 添加元数据
-	if len(msg.Metadata) > 0 {
-		fullContent += "\n【元数据】\n"
-		for key, value := range msg.Metadata {
-			fullContent += fmt.Sprintf("%s: %v\n", key, value)
-		}
+	var sb strings.Builder
+	if err := layout.Execute(&sb, newLayoutData(msg)); err != nil {
+		// 自定义排版出错时回退到默认排版，避免消息丢失
+		log.Printf("微信排版模板渲染失败，使用默认排版: %v", err)
+		sb.Reset()
+		defaultWeChatLayout.Execute(&sb, newLayoutData(msg))
 	}
+	return sb.String()
+}
+
+// SetLayoutTemplate 设置自定义排版模板
+func (w *WeChatPusher) SetLayoutTemplate(text string) error {
+	layout, err := template.New("wechat_layout").Parse(text)
+	if err != nil {
+		return fmt.Errorf("解析微信排版模板失败: %w", err)
+	}
+	w.layout = layout
+	return nil
+}
 
-	return fullContent
+// SetAddressBook 设置通讯录
+func (w *WeChatPusher) SetAddressBook(book base.AddressBookConfig) {
+	w.addressBook = book
 }
 
 // SetSendKey 设置sendKey
@@ -0,0 +1,77 @@
+package pushAPI
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.WorkingDir = filepath.Join(tempDir, "working")
+	cfg.HistoryDir = filepath.Join(tempDir, "history")
+	cfg.QueueSize = 3
+	cfg.FlushInterval = time.Hour // 只在 FlushQueue 时发送
+	cfg.Queue.FullPolicy = QueueFullReject
+
+	pusher := newCapturePusher("capture")
+	api := NewPushAPI()
+	if err := api.InitializeWithPusher(cfg, pusher); err != nil {
+		t.Fatalf("初始化失败: %v", err)
+	}
+
+	enqueue := func(api PushAPI, priority int) error {
+		options := DefaultPushOptions()
+		options.Priority = priority
+		return api.Enqueue(*NewNormalMessage("app1", fmt.Sprintf("p%d", priority), "内容"), options)
+	}
+	for _, priority := range []int{1, 5, 3} {
+		if err := enqueue(api, priority); err != nil {
+			t.Fatalf("入队失败: %v", err)
+		}
+	}
+	if err := enqueue(api, 8); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("队列已满时期望 ErrQueueFull，实际: %v", err)
+	}
+	api.(*PushAPIImpl).Stop()
+	if len(pusher.messages) != 0 {
+		t.Fatalf("刷新前不应发送消息，实际: %+v", pusher.messages)
+	}
+
+	// 重启后恢复未发送的消息；队列已满时挤出优先级最低的消息
+	cfg.Queue.FullPolicy = QueueFullDropLowest
+	api = NewPushAPI()
+	if err := api.InitializeWithPusher(cfg, pusher); err != nil {
+		t.Fatalf("重新初始化失败: %v", err)
+	}
+	defer api.(*PushAPIImpl).Stop()
+
+	if err := enqueue(api, 0); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("优先级最低的新消息期望 ErrQueueFull，实际: %v", err)
+	}
+	if err := enqueue(api, 9); err != nil {
+		t.Fatalf("高优先级消息入队失败: %v", err)
+	}
+	if err := api.FlushQueue(); err != nil {
+		t.Fatalf("刷新队列失败: %v", err)
+	}
+
+	var titles []string
+	for _, msg := range pusher.messages {
+		titles = append(titles, msg.Title)
+	}
+	if fmt.Sprint(titles) != "[p9 p5 p3]" {
+		t.Errorf("期望按优先级发送 [p9 p5 p3]，实际: %v", titles)
+	}
+
+	failed, err := api.QueryHistory(HistoryQuery{Status: "failed"})
+	if err != nil {
+		t.Fatalf("查询历史失败: %v", err)
+	}
+	if failed.Total != 1 || failed.Records[0].Title != "p1" {
+		t.Errorf("被挤出的消息应记为发送失败，实际: %+v", failed.Records)
+	}
+}
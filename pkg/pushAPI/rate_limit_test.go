@@ -0,0 +1,144 @@
+package pushAPI
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimitDivertsToDelay(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.WorkingDir = filepath.Join(tempDir, "working")
+	cfg.HistoryDir = filepath.Join(tempDir, "history")
+	cfg.RateLimit = RateLimitConfig{AppLimit: 1, Interval: time.Hour}
+
+	pusher := newCapturePusher("capture")
+	api := NewPushAPI()
+	if err := api.InitializeWithPusher(cfg, pusher); err != nil {
+		t.Fatalf("初始化失败: %v", err)
+	}
+	defer api.(*PushAPIImpl).Stop()
+
+	options := DefaultPushOptions()
+	for i := 0; i < 3; i++ {
+		msg := NewNormalMessage("app1", "状态", time.Now().String())
+		if err := api.PushNow(*msg, options); err != nil {
+			t.Fatalf("推送失败: %v", err)
+		}
+	}
+
+	if len(pusher.messages) != 1 {
+		t.Fatalf("限流后期望只立即发送1条，实际%d条", len(pusher.messages))
+	}
+	files, _ := filepath.Glob(filepath.Join(cfg.WorkingDir, "delay_*.json"))
+	if len(files) == 0 {
+		t.Fatal("超出配额的消息应写入延迟文件")
+	}
+
+	// 紧急消息不受限流影响
+	if err := api.PushNow(*NewMessage("app1", "告警", "紧急", Emergency), options); err != nil {
+		t.Fatalf("推送失败: %v", err)
+	}
+	if len(pusher.messages) < 2 {
+		t.Error("紧急消息应立即发送")
+	}
+}
+
+func TestDeduplicationWindow(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.WorkingDir = filepath.Join(tempDir, "working")
+	cfg.HistoryDir = filepath.Join(tempDir, "history")
+	cfg.RateLimit = RateLimitConfig{DedupWindow: time.Minute}
+
+	pusher := newCapturePusher("capture")
+	api := NewPushAPI()
+	if err := api.InitializeWithPusher(cfg, pusher); err != nil {
+		t.Fatalf("初始化失败: %v", err)
+	}
+	defer api.(*PushAPIImpl).Stop()
+
+	options := DefaultPushOptions()
+	api.PushNow(*NewNormalMessage("app1", "磁盘告警", "磁盘使用率95%"), options)
+	api.PushNow(*NewNormalMessage("app1", "磁盘告警", "磁盘使用率95%"), options)
+
+	// 显式 dedup_key：内容不同但键相同也视为重复
+	first := NewNormalMessage("app1", "订单", "已提交")
+	first.SetMetadata("dedup_key", "order-1")
+	second := NewNormalMessage("app1", "订单", "已提交（重试）")
+	second.SetMetadata("dedup_key", "order-1")
+	api.PushNow(*first, options)
+	api.PushNow(*second, options)
+
+	if len(pusher.messages) != 2 {
+		t.Errorf("去重后期望发送2条消息，实际%d条", len(pusher.messages))
+	}
+}
+
+func TestDeduplicationAfterFailure(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.WorkingDir = filepath.Join(tempDir, "working")
+	cfg.HistoryDir = filepath.Join(tempDir, "history")
+	cfg.RateLimit = RateLimitConfig{DedupWindow: time.Minute}
+
+	pusher := &failingPusher{capturePusher: newCapturePusher("capture"), fail: true}
+	api := NewPushAPI()
+	if err := api.InitializeWithPusher(cfg, pusher); err != nil {
+		t.Fatalf("初始化失败: %v", err)
+	}
+	defer api.(*PushAPIImpl).Stop()
+
+	// 首次发送失败不应占用去重记录，重发必须送出
+	options := DefaultPushOptions()
+	options.Retry = 0
+	alert := NewMessage("app1", "定投失败", "余额不足", Emergency)
+	if err := api.PushNow(*alert, options); err == nil {
+		t.Fatal("首次发送应返回错误")
+	}
+	pusher.fail = false
+	if err := api.PushNow(*alert, options); err != nil {
+		t.Fatalf("重发失败: %v", err)
+	}
+	if len(pusher.messages) != 1 {
+		t.Errorf("重发的消息应送出，实际%d条", len(pusher.messages))
+	}
+}
+
+func TestRateLimitFloodPusherCalls(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.WorkingDir = filepath.Join(tempDir, "working")
+	cfg.HistoryDir = filepath.Join(tempDir, "history")
+	cfg.RateLimit = RateLimitConfig{AppLimit: 1, Interval: time.Hour}
+
+	pusher := newCapturePusher("capture")
+	api := NewPushAPI()
+	if err := api.InitializeWithPusher(cfg, pusher); err != nil {
+		t.Fatalf("初始化失败: %v", err)
+	}
+	defer api.(*PushAPIImpl).Stop()
+
+	// app1 持续刷屏，其他应用的推送放行时不应顺带发送被限流的消息
+	options := DefaultPushOptions()
+	for i := 0; i < 20; i++ {
+		api.PushNow(*NewNormalMessage("app1", "状态", fmt.Sprintf("第%d次", i)), options)
+		if i%5 == 0 {
+			api.PushNow(*NewNormalMessage(fmt.Sprintf("app%d", i+2), "日报", "今日无异常"), options)
+		}
+	}
+	if calls := len(pusher.messages); calls != 5 {
+		t.Fatalf("期望推送器调用5次（app1首条和4个其他应用），实际%d次", calls)
+	}
+
+	// 被限流的消息随摘要一起发送
+	if err := api.FlushQueue(); err != nil {
+		t.Fatalf("发送摘要失败: %v", err)
+	}
+	if calls := len(pusher.messages); calls != 6 || !strings.Contains(pusher.messages[5].Content, "第19次") {
+		t.Errorf("摘要应合并发送被限流的消息，实际调用%d次", calls)
+	}
+}
@@ -0,0 +1,100 @@
+package pushAPI
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecurringPush(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.WorkingDir = filepath.Join(tempDir, "working")
+	cfg.HistoryDir = filepath.Join(tempDir, "history")
+	cfg.TemplateDir = filepath.Join(tempDir, "templates")
+	os.MkdirAll(cfg.TemplateDir, 0755)
+	os.WriteFile(filepath.Join(cfg.TemplateDir, "portfolio.tmpl"),
+		[]byte(`{{define "title"}}持仓周报{{end}}{{define "content"}}总值 {{.Total}}{{end}}`), 0644)
+
+	pusher := &syncPusher{capturePusher: newCapturePusher("capture")}
+	api := NewPushAPI()
+	if err := api.InitializeWithPusher(cfg, pusher); err != nil {
+		t.Fatalf("初始化失败: %v", err)
+	}
+
+	options := DefaultPushOptions()
+	options.Retry = 0
+	if err := api.PushEvery(*NewNormalMessage("app1", "心跳", "ok"), options, "not a cron"); err == nil {
+		t.Error("无效的cron表达式应返回错误")
+	}
+	if err := api.PushRecurring(*NewNormalMessage("app1", "心跳", "ok"), options, RecurringRule{Cron: "@daily", EndAt: time.Now().Add(-time.Hour)}); err == nil {
+		t.Error("截止时间已过应返回错误")
+	}
+
+	// 每秒发送，共两次；模板每次发送前使用数据提供函数的最新数据重新渲染
+	heartbeat := NewNormalMessage("app1", "心跳", "ok")
+	if err := api.PushRecurring(*heartbeat, options, RecurringRule{Cron: "* * * * * *", MaxCount: 2}); err != nil {
+		t.Fatalf("添加周期推送失败: %v", err)
+	}
+	total := 0
+	api.RegisterTemplateData("portfolio", func() (interface{}, error) {
+		total++
+		return map[string]int{"Total": total}, nil
+	})
+	if _, err := api.PushTemplateRecurring("auto-buy", "portfolio", map[string]int{"Total": 0}, options, RecurringRule{Cron: "* * * * * *", MaxCount: 2}); err != nil {
+		t.Fatalf("添加模板周期推送失败: %v", err)
+	}
+	weekly := NewNormalMessage("auto-buy", "周报", "每周一")
+	if err := api.PushEvery(*weekly, options, "0 9 * * 1"); err != nil {
+		t.Fatalf("添加周期推送失败: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(pusher.sent()) < 4 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	var heartbeats int
+	var contents []string
+	for _, msg := range pusher.sent() {
+		switch msg.Title {
+		case "心跳":
+			heartbeats++
+			if msg.ID == heartbeat.ID || msg.Metadata["recurring_id"] != heartbeat.ID {
+				t.Errorf("每次发送应生成新的消息ID并标注周期推送ID: %s %v", msg.ID, msg.Metadata)
+			}
+		case "持仓周报":
+			contents = append(contents, msg.Content)
+		}
+	}
+	if heartbeats != 2 {
+		t.Errorf("期望发送2次心跳，实际%d次", heartbeats)
+	}
+	if len(contents) != 2 || contents[0] == contents[1] {
+		t.Errorf("模板应在每次发送前重新渲染: %v", contents)
+	}
+
+	// 达到次数的周期推送被删除，未结束的在重启后保留
+	api.(*PushAPIImpl).Stop()
+	api = NewPushAPI()
+	if err := api.InitializeWithPusher(cfg, pusher); err != nil {
+		t.Fatalf("重新初始化失败: %v", err)
+	}
+	defer api.(*PushAPIImpl).Stop()
+
+	remaining, err := api.ListRecurring()
+	if err != nil {
+		t.Fatalf("列出周期推送失败: %v", err)
+	}
+	if len(remaining) != 1 || remaining[0].ID != weekly.ID || remaining[0].NextAt.Weekday() != time.Monday {
+		t.Fatalf("重启后应保留每周推送: %+v", remaining)
+	}
+	if err := api.CancelRecurring(weekly.ID); err != nil {
+		t.Fatalf("取消周期推送失败: %v", err)
+	}
+	if err := api.CancelRecurring(weekly.ID); !errors.Is(err, ErrRecurringNotFound) {
+		t.Errorf("重复取消应返回 ErrRecurringNotFound，实际 %v", err)
+	}
+}
@@ -2,11 +2,15 @@ package pushAPI
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
+
+	"task_scheduler/pkg/pushAPI/base"
+	"task_scheduler/pkg/pushAPI/core"
 )
 
 func TestScheduledPush(t *testing.T) {
@@ -19,6 +23,7 @@ func TestScheduledPush(t *testing.T) {
 	// 配置
 	cfg := DefaultConfig()
 	cfg.WorkingDir = tempDir
+	cfg.HistoryDir = filepath.Join(tempDir, "history")
 	cfg.FlushInterval = 1 * time.Second
 
 	// 初始化
@@ -39,8 +44,8 @@ func TestScheduledPush(t *testing.T) {
 		Retry:     2,
 	}
 
-	// 安排10秒后发送
-	scheduledTime := time.Now().Add(10 * time.Second)
+	// 安排2秒后发送（定时消息精确到秒）
+	scheduledTime := time.Now().Add(2 * time.Second)
 	if err := api.PushAt(*message, options, scheduledTime); err != nil {
 		t.Fatalf("安排定时推送失败: %v", err)
 	}
@@ -51,8 +56,8 @@ func TestScheduledPush(t *testing.T) {
 		t.Errorf("定时消息文件应该存在: %s", currentFile)
 	}
 
-	// 等待一段时间让定时推送处理器处理
-	time.Sleep(20 * time.Second)
+	// 等待定时器触发发送
+	time.Sleep(3 * time.Second)
 
 	// 检查文件是否被清空（消息已发送）
 	data, err := os.ReadFile(currentFile)
@@ -122,6 +127,7 @@ func TestScheduledPushShort(t *testing.T) {
 	// 配置
 	cfg := DefaultConfig()
 	cfg.WorkingDir = tempDir
+	cfg.HistoryDir = filepath.Join(tempDir, "history")
 	cfg.FlushInterval = 1 * time.Second
 
 	// 初始化
@@ -142,9 +148,8 @@ func TestScheduledPushShort(t *testing.T) {
 		Retry:     2,
 	}
 
-	// 安排下一个整分钟发送
-	now := time.Now()
-	scheduledTime := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute()+1, 0, 0, now.Location())
+	// 安排下一个整秒发送
+	scheduledTime := time.Now().Truncate(time.Second).Add(time.Second)
 	if err := api.PushAt(*message, options, scheduledTime); err != nil {
 		t.Fatalf("安排定时推送失败: %v", err)
 	}
@@ -155,8 +160,8 @@ func TestScheduledPushShort(t *testing.T) {
 		t.Errorf("定时消息文件应该存在: %s", currentFile)
 	}
 
-	// 等待70秒让定时推送处理器处理
-	time.Sleep(70 * time.Second)
+	// 等待定时器触发发送
+	time.Sleep(2 * time.Second)
 
 	// 检查文件是否被清空（消息已发送）
 	data, err := os.ReadFile(currentFile)
@@ -178,6 +183,7 @@ func TestDelayMessageMergeAndCleanup(t *testing.T) {
 	api := NewPushAPI()
 	cfg := DefaultConfig()
 	cfg.WorkingDir = tempDir
+	cfg.HistoryDir = filepath.Join(tempDir, "history")
 	cfg.FlushInterval = 1 * time.Second
 	if err := api.Initialize(cfg, Logger); err != nil {
 		t.Fatalf("初始化失败: %v", err)
@@ -216,6 +222,7 @@ func TestScheduledTriggerDelaySend(t *testing.T) {
 	api := NewPushAPI()
 	cfg := DefaultConfig()
 	cfg.WorkingDir = tempDir
+	cfg.HistoryDir = filepath.Join(tempDir, "history")
 	cfg.FlushInterval = 1 * time.Second
 	if err := api.Initialize(cfg, Logger); err != nil {
 		t.Fatalf("初始化失败: %v", err)
@@ -232,15 +239,14 @@ func TestScheduledTriggerDelaySend(t *testing.T) {
 	if err := api.Enqueue(*msg, options); err != nil {
 		t.Fatalf("入队失败: %v", err)
 	}
-	// 安排1分钟后定时推送
-	now := time.Now()
-	scheduledTime := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute()+1, 0, 0, now.Location())
+	// 安排1秒后定时推送
+	scheduledTime := time.Now().Add(time.Second)
 	scheduledMsg := NewNormalMessage("app1", "定时触发", "定时内容")
 	if err := api.PushAt(*scheduledMsg, options, scheduledTime); err != nil {
 		t.Fatalf("定时推送失败: %v", err)
 	}
-	// 等待70秒让定时推送和延迟消息合并发送
-	time.Sleep(70 * time.Second)
+	// 等待定时推送和延迟消息合并发送
+	time.Sleep(2 * time.Second)
 	// 检查延迟消息文件是否被清空
 	pattern := filepath.Join(tempDir, "delay_*.json")
 	files, _ := filepath.Glob(pattern)
@@ -254,55 +260,55 @@ func TestScheduledTriggerDelaySend(t *testing.T) {
 
 func TestScheduledMessageExpirationCheck(t *testing.T) {
 	tempDir := t.TempDir()
-	api := NewPushAPI()
 	cfg := DefaultConfig()
-	cfg.WorkingDir = tempDir
-	cfg.FlushInterval = 1 * time.Second
-	if err := api.Initialize(cfg, Logger); err != nil {
-		t.Fatalf("初始化失败: %v", err)
-	}
-	defer func() {
-		if impl, ok := api.(*PushAPIImpl); ok {
-			impl.Stop()
-		}
-	}()
-
-	options := PushOptions{Receivers: []string{"user1"}, Priority: 1, Retry: 1}
+	cfg.WorkingDir = filepath.Join(tempDir, "working")
+	cfg.HistoryDir = filepath.Join(tempDir, "history")
 
-	// 安排多条定时消息，其中一些已经过期
+	// 模拟停机期间错过的定时消息：分别位于8小时前、1天前的时间片文件中
+	store := core.NewFileStore(cfg.WorkingDir, cfg.HistoryDir)
 	now := time.Now()
+	options := base.PushOptions{Receivers: []string{"user1"}, Priority: 1, Retry: 0}
+	for i, offset := range []time.Duration{-8 * time.Hour, -24 * time.Hour} {
+		msg := base.NewMessage("app1", fmt.Sprintf("过期消息%d", i+1), "内容", base.Normal)
+		store.AppendScheduled(&base.ScheduledMessage{Message: *msg, Options: options, ScheduledAt: now.Add(offset)})
+	}
+	future := base.NewMessage("app1", "未来消息", "内容", base.Normal)
+	store.AppendScheduled(&base.ScheduledMessage{Message: *future, Options: options, ScheduledAt: now.Add(time.Hour)})
 
-	// 1分钟后发送
-	scheduledTime1 := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute()+1, 0, 0, now.Location())
-	msg1 := NewNormalMessage("app1", "定时消息1", "内容1")
-	if err := api.PushAt(*msg1, options, scheduledTime1); err != nil {
-		t.Fatalf("安排定时推送失败: %v", err)
+	pusher := &syncPusher{capturePusher: newCapturePusher("capture")}
+	api := NewPushAPI()
+	if err := api.InitializeWithPusher(cfg, pusher); err != nil {
+		t.Fatalf("初始化失败: %v", err)
 	}
+	defer api.(*PushAPIImpl).Stop()
 
-	// 2分钟后发送
-	scheduledTime2 := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute()+2, 0, 0, now.Location())
-	msg2 := NewNormalMessage("app1", "定时消息2", "内容2")
-	if err := api.PushAt(*msg2, options, scheduledTime2); err != nil {
-		t.Fatalf("安排定时推送失败: %v", err)
+	// 启动后立即补发所有过期消息，按计划时间先后发送
+	deadline := time.Now().Add(2 * time.Second)
+	for len(pusher.sent()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	sent := pusher.sent()
+	if len(sent) != 2 || sent[0].Title != "过期消息2" || sent[1].Title != "过期消息1" {
+		t.Fatalf("过期消息应全部按时间顺序补发: %+v", sent)
 	}
 
-	// 等待70秒让定时推送处理器处理
-	time.Sleep(70 * time.Second)
+	pending, err := api.ListScheduled(ScheduledFilter{})
+	if err != nil {
+		t.Fatalf("列出定时消息失败: %v", err)
+	}
+	if len(pending) != 1 || pending[0].Message.ID != future.ID {
+		t.Errorf("只应剩余未到期的消息: %+v", pending)
+	}
 
-	// 检查定时消息文件是否被正确更新（过期的消息应该被移除）
-	pattern := filepath.Join(tempDir, "scheduled_*.json")
-	files, _ := filepath.Glob(pattern)
-	for _, file := range files {
-		data, _ := os.ReadFile(file)
-		if len(data) > 0 {
-			// 检查文件中是否还有未过期的消息
-			var messages []interface{}
-			if err := json.Unmarshal(data, &messages); err == nil {
-				if len(messages) > 0 {
-					t.Logf("文件中还有 %d 条未过期的定时消息", len(messages))
-				}
-			}
-		}
+	// 秒级精度：1秒后的消息在1秒左右发送
+	soon := NewNormalMessage("app1", "秒级消息", "内容")
+	api.PushAt(*soon, DefaultPushOptions(), time.Now().Add(time.Second))
+	deadline = time.Now().Add(3 * time.Second)
+	for len(pusher.sent()) < 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(pusher.sent()) != 3 {
+		t.Error("秒级定时消息未按时发送")
 	}
 }
 
@@ -369,3 +375,123 @@ func TestHistoryRecordWithSendTime(t *testing.T) {
 		}
 	}
 }
+
+func TestCancelAndReschedule(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.WorkingDir = filepath.Join(tempDir, "working")
+	cfg.HistoryDir = filepath.Join(tempDir, "history")
+
+	api := NewPushAPI()
+	if err := api.Initialize(cfg, Logger); err != nil {
+		t.Fatalf("初始化失败: %v", err)
+	}
+	defer api.(*PushAPIImpl).Stop()
+
+	now := time.Now()
+	options := DefaultPushOptions()
+	report := NewNormalMessage("auto-buy", "周报", "本周定投3次")
+	reminder := NewMessage("auto-buy", "提醒", "检查余额", Emergency)
+	daily := NewNormalMessage("app2", "日报", "今日无异常")
+	api.PushAt(*report, options, now.Add(3*time.Hour))
+	api.PushAt(*reminder, options, now.Add(2*time.Hour))
+	api.PushAt(*daily, options, now.Add(time.Hour))
+
+	all, err := api.ListScheduled(ScheduledFilter{})
+	if err != nil {
+		t.Fatalf("列出定时消息失败: %v", err)
+	}
+	if len(all) != 3 || all[0].Message.ID != daily.ID || all[2].Message.ID != report.ID {
+		t.Fatalf("定时消息应按计划时间升序: %+v", all)
+	}
+	if filtered, _ := api.ListScheduled(ScheduledFilter{AppID: "auto-buy", Level: "emergency"}); len(filtered) != 1 || filtered[0].Message.ID != reminder.ID {
+		t.Errorf("按AppID和级别过滤结果不正确: %+v", filtered)
+	}
+
+	// 改期到最前面，并迁移到新的时间片文件
+	newTime := now.Add(30 * time.Minute)
+	if err := api.Reschedule(report.ID, newTime); err != nil {
+		t.Fatalf("改期失败: %v", err)
+	}
+	all, _ = api.ListScheduled(ScheduledFilter{})
+	if len(all) != 3 || all[0].Message.ID != report.ID || !all[0].ScheduledAt.Equal(newTime.Truncate(time.Second)) {
+		t.Errorf("改期后的定时消息不正确: %+v", all[0])
+	}
+	if within, _ := api.ListScheduled(ScheduledFilter{Until: now.Add(45 * time.Minute)}); len(within) != 1 {
+		t.Errorf("时间范围过滤结果不正确: %d", len(within))
+	}
+
+	if err := api.CancelScheduled(reminder.ID); err != nil {
+		t.Fatalf("取消失败: %v", err)
+	}
+	if err := api.CancelScheduled(reminder.ID); !errors.Is(err, ErrScheduledNotFound) {
+		t.Errorf("重复取消应返回 ErrScheduledNotFound，实际 %v", err)
+	}
+	if err := api.Reschedule("missing", now); !errors.Is(err, ErrScheduledNotFound) {
+		t.Errorf("改期不存在的消息应返回 ErrScheduledNotFound，实际 %v", err)
+	}
+	if all, _ = api.ListScheduled(ScheduledFilter{}); len(all) != 2 {
+		t.Errorf("取消后应剩余2条定时消息，实际%d条", len(all))
+	}
+}
+
+// blockingPusher 推送时阻塞直到 release 关闭，用于模拟重试等待中的发送
+type blockingPusher struct {
+	*capturePusher
+	started chan struct{}
+	release chan struct{}
+}
+
+func (bp *blockingPusher) Push(msg base.Message) error {
+	close(bp.started)
+	<-bp.release
+	return bp.capturePusher.Push(msg)
+}
+
+func TestSendDoesNotHoldLocks(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.WorkingDir = filepath.Join(tempDir, "working")
+	cfg.HistoryDir = filepath.Join(tempDir, "history")
+
+	pusher := &blockingPusher{
+		capturePusher: newCapturePusher("blocking"),
+		started:       make(chan struct{}),
+		release:       make(chan struct{}),
+	}
+	api := NewPushAPI()
+	if err := api.InitializeWithPusher(cfg, pusher); err != nil {
+		t.Fatalf("初始化失败: %v", err)
+	}
+	defer api.(*PushAPIImpl).Stop()
+
+	sent := make(chan error, 1)
+	go func() {
+		sent <- api.PushNow(*NewNormalMessage("app1", "标题", "内容"), DefaultPushOptions())
+	}()
+	<-pusher.started
+
+	// 发送进行中时注册推送器、安排定时消息不应被阻塞
+	done := make(chan error, 1)
+	go func() {
+		if err := api.RegisterPusher(Logger); err != nil {
+			done <- err
+			return
+		}
+		done <- api.PushAt(*NewNormalMessage("app1", "定时", "内容"), DefaultPushOptions(), time.Now().Add(time.Hour))
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("发送期间操作失败: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		close(pusher.release)
+		t.Fatal("发送期间注册推送器或安排定时消息被阻塞")
+	}
+
+	close(pusher.release)
+	if err := <-sent; err != nil {
+		t.Fatalf("推送失败: %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package pushAPI
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"task_scheduler/pkg/pushAPI/base"
+	"task_scheduler/pkg/pushAPI/core"
+)
+
+func TestMessageStores(t *testing.T) {
+	stores := map[string]func(dir string) (core.MessageStore, error){
+		"file": func(dir string) (core.MessageStore, error) {
+			return core.NewFileStore(filepath.Join(dir, "working"), filepath.Join(dir, "history")), nil
+		},
+		"bolt": func(dir string) (core.MessageStore, error) {
+			return core.NewBoltStore(filepath.Join(dir, "messages.db"))
+		},
+	}
+
+	for name, open := range stores {
+		t.Run(name, func(t *testing.T) {
+			store, err := open(t.TempDir())
+			if err != nil {
+				t.Fatalf("打开存储失败: %v", err)
+			}
+			defer store.Close()
+
+			now := time.Now()
+			msg := base.NewMessage("app1", "标题", "内容", base.Normal)
+
+			// 延迟消息
+			for i := 0; i < 2; i++ {
+				if err := store.AppendDelay(now, &base.DelayMessage{Message: *msg, CreatedAt: now}); err != nil {
+					t.Fatalf("追加延迟消息失败: %v", err)
+				}
+			}
+			if delayed, _ := store.ListDelay(); len(delayed) != 2 {
+				t.Errorf("期望2条延迟消息，实际%d条", len(delayed))
+			}
+			store.ClearDelay()
+			if delayed, _ := store.ListDelay(); len(delayed) != 0 {
+				t.Errorf("清空后仍有%d条延迟消息", len(delayed))
+			}
+
+			// 定时消息
+			other := base.NewMessage("app1", "另一条", "内容", base.Normal)
+			store.AppendScheduled(&base.ScheduledMessage{Message: *msg, ScheduledAt: now})
+			store.AppendScheduled(&base.ScheduledMessage{Message: *other, ScheduledAt: now.Add(5 * time.Hour)})
+			if err := store.RemoveScheduled(msg.ID); err != nil {
+				t.Fatalf("删除定时消息失败: %v", err)
+			}
+			scheduled, _ := store.ListScheduled()
+			if len(scheduled) != 1 || scheduled[0].Message.ID != other.ID {
+				t.Errorf("删除后定时消息不正确: %+v", scheduled)
+			}
+
+			// 历史记录
+			old := base.NewSuccessHistoryRecord(*other, "logger", base.PushOptions{})
+			old.Timestamp = now.AddDate(0, -3, 0)
+			store.AppendHistory("success_send", old)
+			store.AppendHistory("success_send", base.NewSuccessHistoryRecord(*msg, "logger", base.PushOptions{}))
+			store.AppendHistory("failed_send", base.NewFailedHistoryRecord(*msg, "logger", base.PushOptions{}, "超时"))
+
+			if records, _ := store.FindHistory(msg.ID); len(records) != 2 {
+				t.Errorf("按消息ID期望找到2条记录，实际%d条", len(records))
+			}
+			if records, _ := store.ListHistory("success_send", now.Format("200601")); len(records) != 1 {
+				t.Errorf("本月期望1条成功记录，实际%d条", len(records))
+			}
+			if months, _ := store.HistoryMonths(); len(months) != 2 {
+				t.Errorf("期望2个月份，实际%v", months)
+			}
+
+			if err := store.DeleteHistoryBefore(now.AddDate(0, -1, 0).Format("200601")); err != nil {
+				t.Fatalf("清理历史记录失败: %v", err)
+			}
+			if months, _ := store.HistoryMonths(); len(months) != 1 || months[0] != now.Format("200601") {
+				t.Errorf("清理后月份不正确: %v", months)
+			}
+			if records, _ := store.FindHistory(other.ID); len(records) != 0 {
+				t.Errorf("已清理的记录不应再被找到: %d", len(records))
+			}
+		})
+	}
+}
+
+func TestBoltStoreBackedPushAPI(t *testing.T) {
+	tempDir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.WorkingDir = filepath.Join(tempDir, "working")
+	cfg.HistoryDir = filepath.Join(tempDir, "history")
+	cfg.Store = StoreConfig{Type: StoreBolt}
+
+	pusher := newCapturePusher("capture")
+	api := NewPushAPI()
+	if err := api.InitializeWithPusher(cfg, pusher); err != nil {
+		t.Fatalf("初始化失败: %v", err)
+	}
+	defer api.(*PushAPIImpl).Stop()
+
+	options := DefaultPushOptions()
+	for i := 0; i < 2; i++ {
+		if err := api.Enqueue(*NewNormalMessage("app1", "入队", time.Now().String()), options); err != nil {
+			t.Fatalf("入队失败: %v", err)
+		}
+	}
+	if err := api.FlushQueue(); err != nil {
+		t.Fatalf("刷新队列失败: %v", err)
+	}
+
+	if len(pusher.messages) != 2 {
+		t.Fatalf("期望发送2条消息，实际: %+v", pusher.messages)
+	}
+	if files, _ := filepath.Glob(filepath.Join(cfg.WorkingDir, "*.json")); len(files) != 0 {
+		t.Errorf("bolt存储不应产生JSON文件: %v", files)
+	}
+}
+
+func TestBoltStoreLocked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "messages.db")
+	store, err := core.NewBoltStore(path)
+	if err != nil {
+		t.Fatalf("打开存储失败: %v", err)
+	}
+	defer store.Close()
+
+	// 文件被占用时返回明确的错误而不是bbolt的超时错误
+	if _, err := core.NewBoltStore(path); !errors.Is(err, ErrStoreLocked) {
+		t.Errorf("存储被占用时期望 ErrStoreLocked，实际: %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package pushAPI
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"task_scheduler/pkg/pushAPI/base"
+	"task_scheduler/pkg/pushAPI/push_method"
+)
+
+// capturePusher 记录收到消息的测试推送器
+type capturePusher struct {
+	push_method.BasePusher
+	messages []base.Message
+}
+
+func newCapturePusher(name string) *capturePusher {
+	return &capturePusher{BasePusher: push_method.BasePusher{Name: name}}
+}
+
+func (cp *capturePusher) Push(msg base.Message) error {
+	cp.messages = append(cp.messages, msg)
+	return nil
+}
+
+func TestPushTemplateChannelVariant(t *testing.T) {
+	tempDir := t.TempDir()
+	templateDir := filepath.Join(tempDir, "templates")
+	if err := os.MkdirAll(templateDir, 0755); err != nil {
+		t.Fatalf("创建模板目录失败: %v", err)
+	}
+
+	files := map[string]string{
+		"order.tmpl":     `{{define "title"}}订单 {{.ID}}{{end}}{{define "content"}}**金额**: {{.Amount}}{{end}}`,
+		"order.sms.tmpl": `{{define "title"}}订单{{end}}{{define "level"}}emergency{{end}}订单{{.ID}}金额{{.Amount}}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(templateDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("写入模板失败: %v", err)
+		}
+	}
+
+	cfg := DefaultConfig()
+	cfg.WorkingDir = filepath.Join(tempDir, "working")
+	cfg.HistoryDir = filepath.Join(tempDir, "history")
+	cfg.TemplateDir = templateDir
+
+	data := map[string]interface{}{"ID": "A1", "Amount": 100}
+	options := DefaultPushOptions()
+
+	testCases := []struct {
+		channel string
+		title   string
+		content string
+		level   base.MessageLevel
+	}{
+		{channel: "wechat", title: "订单 A1", content: "**金额**: 100", level: base.Normal},
+		{channel: "sms", title: "订单", content: "订单A1金额100", level: base.Emergency},
+	}
+
+	for _, tc := range testCases {
+		pusher := newCapturePusher(tc.channel)
+		api := NewPushAPI()
+		if err := api.InitializeWithPusher(cfg, pusher); err != nil {
+			t.Fatalf("初始化失败: %v", err)
+		}
+
+		if err := api.PushTemplate("app1", "order", data, options); err != nil {
+			t.Fatalf("模板推送失败: %v", err)
+		}
+		api.(*PushAPIImpl).Stop()
+
+		if len(pusher.messages) != 1 {
+			t.Fatalf("[%s] 期望推送1条消息，实际%d条", tc.channel, len(pusher.messages))
+		}
+		msg := pusher.messages[0]
+		if msg.Title != tc.title || msg.Content != tc.content || msg.Level != tc.level {
+			t.Errorf("[%s] 渲染结果不正确: title=%q content=%q level=%v", tc.channel, msg.Title, msg.Content, msg.Level)
+		}
+	}
+
+	api := NewPushAPI()
+	if err := api.InitializeWithPusher(cfg, newCapturePusher("wechat")); err != nil {
+		t.Fatalf("初始化失败: %v", err)
+	}
+	defer api.(*PushAPIImpl).Stop()
+	if err := api.PushTemplate("app1", "missing", data, options); !errors.Is(err, ErrTemplate) {
+		t.Errorf("模板不存在时应返回ErrTemplate，实际为%v", err)
+	}
+}
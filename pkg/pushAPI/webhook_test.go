@@ -0,0 +1,137 @@
+package pushAPI
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"task_scheduler/pkg/pushAPI/push_method"
+)
+
+func TestWebhookPusherSignedPayload(t *testing.T) {
+	secret := "test-secret"
+	var gotBody []byte
+	var gotSignature, gotToken, gotTimestamp string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotSignature = r.Header.Get("X-Signature-256")
+		gotTimestamp = r.Header.Get("X-Webhook-Timestamp")
+		gotToken = r.Header.Get("X-Token")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	cfg := DefaultConfig().WebhookConfig
+	cfg.URLs = []string{server.URL}
+	cfg.Secret = secret
+	cfg.Headers = map[string]string{"X-Token": "abc"}
+
+	pusher, err := push_method.NewWebhookPusher(cfg.ToCore())
+	if err != nil {
+		t.Fatalf("创建webhook推送器失败: %v", err)
+	}
+
+	message := NewMessage("app1", "告警", "磁盘空间不足", Emergency)
+	if err := pusher.Push(message.ToCore()); err != nil {
+		t.Fatalf("推送失败: %v", err)
+	}
+
+	if gotToken != "abc" {
+		t.Errorf("期望自定义请求头X-Token为'abc'，实际为'%s'", gotToken)
+	}
+	if gotTimestamp == "" {
+		t.Fatal("缺少时间戳请求头")
+	}
+	// 签名覆盖时间戳，篡改时间戳后签名不再匹配
+	if expected := "sha256=" + push_method.Sign(secret, gotTimestamp, gotBody); gotSignature != expected {
+		t.Errorf("签名不匹配，期望%s，实际%s", expected, gotSignature)
+	}
+	if forged := "sha256=" + push_method.Sign(secret, "0", gotBody); gotSignature == forged {
+		t.Error("签名未覆盖时间戳")
+	}
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(gotBody, &payload); err != nil {
+		t.Fatalf("解析请求体失败: %v", err)
+	}
+	if payload["title"] != "告警" || payload["level"] != "emergency" {
+		t.Errorf("请求体内容不正确: %s", string(gotBody))
+	}
+}
+
+func TestWebhookPusherTypedErrors(t *testing.T) {
+	status := http.StatusBadRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	pusher, err := push_method.NewWebhookPusher(WebhookConfig{
+		URLs:         []string{server.URL},
+		BodyTemplate: `{"text": {{json .Title}}}`,
+	}.ToCore())
+	if err != nil {
+		t.Fatalf("创建webhook推送器失败: %v", err)
+	}
+
+	message := NewNormalMessage("app1", "标题", "内容")
+	err = pusher.Push(message.ToCore())
+	var pushErr *push_method.PushError
+	if !errors.As(err, &pushErr) || pushErr.StatusCode != http.StatusBadRequest {
+		t.Fatalf("期望返回400的PushError，实际为%v", err)
+	}
+	if push_method.IsRetryable(err) {
+		t.Error("4xx错误不应该重试")
+	}
+
+	status = http.StatusServiceUnavailable
+	if err := pusher.Push(message.ToCore()); !push_method.IsRetryable(err) {
+		t.Errorf("5xx错误应该可以重试，实际为%v", err)
+	}
+}
+
+func TestWebhookRetryOnlyFailedTargets(t *testing.T) {
+	var okHits, flakyHits atomic.Int32
+	okServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		okHits.Add(1)
+	}))
+	defer okServer.Close()
+	flakyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if flakyHits.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer flakyServer.Close()
+
+	pusher, err := push_method.NewWebhookPusher(WebhookConfig{
+		URLs: []string{okServer.URL, flakyServer.URL},
+	}.ToCore())
+	if err != nil {
+		t.Fatalf("创建webhook推送器失败: %v", err)
+	}
+
+	tempDir := t.TempDir()
+	cfg := DefaultConfig()
+	cfg.WorkingDir = filepath.Join(tempDir, "working")
+	cfg.HistoryDir = filepath.Join(tempDir, "history")
+	api := NewPushAPI()
+	if err := api.InitializeWithPusher(cfg, pusher); err != nil {
+		t.Fatalf("初始化失败: %v", err)
+	}
+	defer api.(*PushAPIImpl).Stop()
+
+	options := DefaultPushOptions()
+	options.Retry = 1
+	if err := api.PushNow(*NewNormalMessage("app1", "标题", "内容"), options); err != nil {
+		t.Fatalf("重试后应推送成功: %v", err)
+	}
+	if okHits.Load() != 1 || flakyHits.Load() != 2 {
+		t.Errorf("只应向失败的地址重发，实际成功地址%d次，失败地址%d次", okHits.Load(), flakyHits.Load())
+	}
+}
@@ -3,32 +3,64 @@ package autobuy
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
-	"os"
+	"strconv"
 	"strings"
 	"task_scheduler/internal/plugins"
 	"task_scheduler/pkg/ccxt"
 	"task_scheduler/pkg/pushAPI"
+	"time"
+)
+
+const (
+	// reportTemplateName 定投结果通知模板名称（位于推送模板目录）
+	reportTemplateName = "auto_buy_result"
+	// defaultExchange 未配置 exchange 时使用的交易所
+	defaultExchange = "binance"
+	// buySymbol 定投的交易对
+	buySymbol = "BTCUSDT"
+	// defaultOrderTimeout 限价单等待成交的默认时间
+	defaultOrderTimeout = 10 * time.Minute
+	// defaultOrderStatePath 未完成订单的默认持久化文件
+	defaultOrderStatePath = "data/orders/auto-buy.json"
+	// defaultOrderJournalPath 订单日志的默认文件
+	defaultOrderJournalPath = "data/orders/journal.jsonl"
+	// executionMargin 默认执行超时时间在订单最长等待时间之外预留的时间，用于获取指标、继续跟踪中断的订单和推送结果
+	executionMargin = 5 * time.Minute
 )
 
 // AutoBuyPlugin auto-buy插件实现
-type AutoBuyPlugin struct{}
+type AutoBuyPlugin struct {
+	pushConfig *pushAPI.Config // 推送配置，为空时使用默认配置
+}
 
 // AutoBuyTask auto-buy任务实现
 type AutoBuyTask struct {
 	name             string
 	config           map[string]interface{}
 	baseAmount       float64
+	exchangeName     string
+	exchangeOptions  map[string]interface{}
+	trackerConfig    ccxt.TrackerConfig
+	executionTimeout time.Duration // 单次执行的超时时间，需长于订单最长等待时间
 	ahr999TimerTable Ahr999TimerTable
 	pusher           pushAPI.PushAPI
+	recordDir        string                                    // 每日定投记录目录
+	ahr999Source     func() (price, ahr999 float64, err error) // 当前价格和AHR999指标来源，默认 GetAhr999
 }
 
-// NewPlugin 创建auto-buy插件
+// NewPlugin 创建auto-buy插件，使用默认推送配置
 func NewPlugin() plugins.Plugin {
 	return &AutoBuyPlugin{}
 }
 
+// NewPluginWithPushConfig 创建auto-buy插件，定投结果按主配置中的推送配置发送
+func NewPluginWithPushConfig(cfg pushAPI.Config) plugins.Plugin {
+	return &AutoBuyPlugin{pushConfig: &cfg}
+}
+
 // Name 返回插件名称
 func (p *AutoBuyPlugin) Name() string {
 	return "auto-buy"
@@ -37,8 +69,16 @@ func (p *AutoBuyPlugin) Name() string {
 // CreateTask 创建任务实例
 func (p *AutoBuyPlugin) CreateTask(config map[string]interface{}) (plugins.Task, error) {
 	task := &AutoBuyTask{
-		name:   "auto-buy",
-		config: config,
+		name:         "auto-buy",
+		config:       config,
+		exchangeName: defaultExchange,
+		recordDir:    recordDir,
+		ahr999Source: GetAhr999,
+		trackerConfig: ccxt.TrackerConfig{
+			Timeout:   defaultOrderTimeout,
+			OnTimeout: ccxt.TimeoutMarket,
+			StatePath: defaultOrderStatePath,
+		},
 	}
 
 	// 解析基准金额配置
@@ -59,6 +99,76 @@ func (p *AutoBuyPlugin) CreateTask(config map[string]interface{}) (plugins.Task,
 		return nil, fmt.Errorf("error, 配置中缺少 base_amount")
 	}
 
+	// 解析交易所配置
+	if exchangeRaw, exists := config["exchange"]; exists {
+		exchangeName, ok := exchangeRaw.(string)
+		if !ok || exchangeName == "" {
+			return nil, fmt.Errorf("exchange 必须是非空字符串")
+		}
+		task.exchangeName = exchangeName
+	}
+	if optionsRaw, exists := config["exchange_options"]; exists {
+		options, ok := optionsRaw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("exchange_options 必须是映射")
+		}
+		task.exchangeOptions = options
+	}
+
+	// 解析订单跟踪配置
+	if timeoutRaw, exists := config["order_timeout"]; exists {
+		timeoutStr, _ := timeoutRaw.(string)
+		timeout, err := time.ParseDuration(timeoutStr)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("order_timeout 必须是正的时长，如 10m: %v", timeoutRaw)
+		}
+		task.trackerConfig.Timeout = timeout
+	}
+	if actionRaw, exists := config["order_timeout_action"]; exists {
+		action, _ := actionRaw.(string)
+		switch ccxt.TimeoutAction(action) {
+		case ccxt.TimeoutCancel, ccxt.TimeoutMarket, ccxt.TimeoutReprice:
+			task.trackerConfig.OnTimeout = ccxt.TimeoutAction(action)
+		default:
+			return nil, fmt.Errorf("order_timeout_action 必须是 cancel、market 或 reprice: %v", actionRaw)
+		}
+	}
+	if statePathRaw, exists := config["order_state_path"]; exists {
+		statePath, ok := statePathRaw.(string)
+		if !ok || statePath == "" {
+			return nil, fmt.Errorf("order_state_path 必须是非空字符串")
+		}
+		task.trackerConfig.StatePath = statePath
+	}
+	journalPath := defaultOrderJournalPath
+	if journalPathRaw, exists := config["order_journal_path"]; exists {
+		path, ok := journalPathRaw.(string)
+		if !ok || path == "" {
+			return nil, fmt.Errorf("order_journal_path 必须是非空字符串")
+		}
+		journalPath = path
+	}
+	task.trackerConfig.Journal = ccxt.NewOrderJournal(journalPath)
+	task.executionTimeout = task.trackerConfig.MaxWait() + executionMargin
+	if timeoutRaw, exists := config["execution_timeout"]; exists {
+		timeoutStr, _ := timeoutRaw.(string)
+		timeout, err := time.ParseDuration(timeoutStr)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("execution_timeout 必须是正的时长，如 15m: %v", timeoutRaw)
+		}
+		if maxWait := task.trackerConfig.MaxWait(); timeout <= maxWait {
+			return nil, fmt.Errorf("execution_timeout 必须长于订单最长等待时间 %s: %v", maxWait, timeoutRaw)
+		}
+		task.executionTimeout = timeout
+	}
+	if recordDirRaw, exists := config["record_dir"]; exists {
+		dir, ok := recordDirRaw.(string)
+		if !ok || dir == "" {
+			return nil, fmt.Errorf("record_dir 必须是非空字符串")
+		}
+		task.recordDir = dir
+	}
+
 	// 解析AHR999倍数表配置
 	if timerTableRaw, exists := config["ahr999_timer_table"]; exists {
 		if timerTableStr, ok := timerTableRaw.(string); ok {
@@ -87,8 +197,14 @@ func (p *AutoBuyPlugin) CreateTask(config map[string]interface{}) (plugins.Task,
 	} else {
 		return nil, fmt.Errorf("error, 配置中缺少 ahr999_timer_table")
 	}
+	// 定投失败时以紧急消息发送，未配置推送时未确认则每15分钟重发提醒，直到有人确认
+	pushConfig := pushAPI.DefaultConfig()
+	pushConfig.Ack.Window = 15 * time.Minute
+	if p.pushConfig != nil {
+		pushConfig = *p.pushConfig
+	}
 	task.pusher = pushAPI.NewPushAPI()
-	task.pusher.Initialize(pushAPI.DefaultConfig(), pushAPI.WeChat)
+	task.pusher.Initialize(pushConfig, pushAPI.WeChat)
 
 	return task, nil
 }
@@ -106,6 +222,11 @@ func (t *AutoBuyTask) Name() string {
 	return t.name
 }
 
+// Timeout 返回单次执行的超时时间，超时后停止跟踪订单，未完成的订单下次执行时继续跟踪
+func (t *AutoBuyTask) Timeout() time.Duration {
+	return t.executionTimeout
+}
+
 // Execute 执行任务
 func (t *AutoBuyTask) Execute(ctx context.Context) error {
 	log.Printf("开始执行 Auto-Buy 任务")
@@ -119,8 +240,15 @@ func (t *AutoBuyTask) Execute(ctx context.Context) error {
 
 	debug, _ := t.config["debug"].(bool)
 
+	// 客户端订单号由调度计划时间决定，同一次调度重试或补跑时不会重复买入
+	run, ok := plugins.RunInfoFromContext(ctx)
+	if !ok {
+		run = plugins.RunInfo{TaskName: t.name, ScheduledTime: time.Now()}
+	}
+	clientOrderID := ccxt.ClientOrderID(t.name, run.ScheduledTime)
+
 	// 执行比特币定投逻辑
-	if err := t.executeBitcoinStrategy(debug); err != nil {
+	if err := t.executeBitcoinStrategy(ctx, debug, clientOrderID); err != nil {
 		return fmt.Errorf("执行比特币定投策略失败: %w", err)
 	}
 
@@ -129,9 +257,9 @@ func (t *AutoBuyTask) Execute(ctx context.Context) error {
 }
 
 // executeBitcoinStrategy 执行比特币定投策略
-func (t *AutoBuyTask) executeBitcoinStrategy(debug bool) error {
+func (t *AutoBuyTask) executeBitcoinStrategy(ctx context.Context, debug bool, clientOrderID string) error {
 	// 获取当前ahr999指标
-	currPrice, ahr999Value, err := GetAhr999()
+	currPrice, ahr999Value, err := t.ahr999Source()
 	if err != nil {
 		return fmt.Errorf("获取AHR999指标失败: %w", err)
 	}
@@ -151,32 +279,126 @@ func (t *AutoBuyTask) executeBitcoinStrategy(debug bool) error {
 		log.Printf("建议定投金额: $%.2f", investmentAmount)
 	}
 
+	exchangeConfig := ccxt.ConfigFromEnv(t.exchangeName)
+	exchangeConfig.Options = t.exchangeOptions
+	exchange, err := ccxt.NewExchange(t.exchangeName, exchangeConfig)
+	if err != nil {
+		return fmt.Errorf("创建交易所失败: %w", err)
+	}
+
+	tracker, err := ccxt.NewOrderTracker(exchange, t.trackerConfig)
+	if err != nil {
+		return fmt.Errorf("创建订单跟踪器失败: %w", err)
+	}
+	// 上次运行中断时未完成的订单先跟踪到结束
+	if results, err := tracker.Resume(ctx); err != nil {
+		log.Printf("继续跟踪未完成订单失败: %v", err)
+	} else if len(results) > 0 {
+		log.Printf("已完成上次中断的 %d 个订单", len(results))
+	}
+
 	buyResult := "未执行"
 	buyMsg := ""
-	ccxtClient := ccxt.NewClient(os.Getenv("BINANCE_API_KEY"), os.Getenv("BINANCE_SECRET_KEY"), "")
+	var result *ccxt.TrackResult
 	if investmentAmount > 0 {
-		// 如果定投金额>0，调用 ccxt 库进行定投
-		buyMsg = ccxtClient.BuyCoinByBestPrice(context.Background(), "BTCUSDT", investmentAmount)
-		if strings.Contains(buyMsg, "失败") {
+		// 如果定投金额>0，以卖一价挂单定投，等待成交，超时后按配置撤单、市价补单或重新挂单
+		req, err := ccxt.BestPriceBuyRequest(ctx, exchange, buySymbol, investmentAmount)
+		if err == nil {
+			req.ClientOrderID = clientOrderID
+			result, err = tracker.Place(ctx, req)
+		}
+		switch {
+		case errors.Is(err, ccxt.ErrInsufficientBalance):
+			buyResult = "定投失败（余额不足）"
+			buyMsg = err.Error()
+		case errors.Is(err, ccxt.ErrMinNotional):
+			buyResult = "定投失败（金额低于交易所最小下单金额）"
+			buyMsg = err.Error()
+		case err != nil:
 			buyResult = "定投失败"
-		} else {
+			buyMsg = err.Error()
+		case result.Duplicate:
+			buyResult = "本期已下单，未重复买入"
+			buyMsg = fmt.Sprintf("客户端订单号 %s 的订单已存在，状态: %s", clientOrderID, result.Status)
+		case result.Filled():
 			buyResult = "定投成功"
+		case result.Status == ccxt.OrderStatusPartiallyFilled:
+			buyResult = "部分成交"
+		default:
+			buyResult = "定投失败（未成交）"
 		}
 	}
 
-	btcBalance := ccxtClient.GetBTCBalance(context.Background())
+	btcBalance := ""
+	if balances, err := exchange.GetBalances(ctx); err != nil {
+		btcBalance = fmt.Sprintf("获取余额失败: %v", err)
+	} else {
+		btcBalance = strconv.FormatFloat(ccxt.FindBalance(balances, "BTC").Free, 'f', -1, 64)
+	}
 
 	// 推送消息, 包括当前价格/当前指标/定投结果(成功或失败)
-	title := fmt.Sprintf("定投大饼 %v: $%.2f USDT", buyResult, investmentAmount)
-	content := fmt.Sprintf("当前价格: $%.2f\n\nAHR999: %.3f\n\nBTC余额: %s\n\n详细信息: %s", currPrice, ahr999Value, btcBalance, buyMsg)
-	// 推送消息
-	t.pusher.PushNow(*pushAPI.NewNormalMessage("auto-buy", title, content), pushAPI.DefaultPushOptions())
-	fmt.Println(title)
-	fmt.Println(content)
+	report := buyReport{
+		Result:     buyResult,
+		Failed:     strings.HasPrefix(buyResult, "定投失败"),
+		Amount:     investmentAmount,
+		Price:      currPrice,
+		Ahr999:     ahr999Value,
+		BTCBalance: btcBalance,
+		Detail:     buyMsg,
+	}
+	t.pushReport(report, result)
 
 	return nil
 }
 
+// buyReport 定投结果通知的模板数据
+type buyReport struct {
+	Result     string  // 定投结果（未执行/定投成功/部分成交/定投失败）
+	Failed     bool    // 是否定投失败，失败时以紧急消息推送
+	Amount     float64 // 定投金额（USDT）
+	Price      float64 // 当前价格
+	Ahr999     float64 // AHR999指标
+	BTCBalance string  // BTC余额
+	Detail     string  // 下单详细信息
+}
+
+// pushReport 推送定投结果，优先使用 auto_buy_result 模板，模板不可用时使用内置格式
+// 下单成功时订单摘要以表格展示，订单JSON和当天的定投记录CSV作为附件
+func (t *AutoBuyTask) pushReport(report buyReport, result *ccxt.TrackResult) {
+	now := time.Now()
+	var blocks []pushAPI.ContentBlock
+	if result != nil {
+		blocks = append(blocks, orderBlocks(result, now)...)
+		if report.Detail == "" {
+			report.Detail = "订单详情见附件"
+		}
+	}
+	if recordPath, err := appendDailyRecord(t.recordDir, report, now); err != nil {
+		log.Printf("写入定投记录失败: %v", err)
+	} else {
+		blocks = append(blocks, pushAPI.FileBlock(recordPath))
+	}
+
+	options := pushAPI.DefaultPushOptions()
+	err := t.pusher.PushTemplate("auto-buy", reportTemplateName, report, options, blocks...)
+	if !errors.Is(err, pushAPI.ErrTemplate) {
+		return
+	}
+	log.Printf("模板不可用，使用内置格式: %v", err)
+
+	title := fmt.Sprintf("定投大饼 %v: $%.2f USDT", report.Result, report.Amount)
+	content := fmt.Sprintf("当前价格: $%.2f\n\nAHR999: %.3f\n\nBTC余额: %s\n\n详细信息: %s", report.Price, report.Ahr999, report.BTCBalance, report.Detail)
+	level := pushAPI.Normal
+	if report.Failed {
+		level = pushAPI.Emergency
+	}
+	message := pushAPI.NewMessage("auto-buy", title, content, level)
+	message.AddBlocks(blocks...)
+	t.pusher.PushNow(*message, options)
+	fmt.Println(title)
+	fmt.Println(content)
+}
+
 // calculateInvestmentAmount 根据AHR999指标计算定投金额
 func (t *AutoBuyTask) calculateInvestmentAmount(ahr999 float64) (float64, error) {
 	// 使用已解析的配置
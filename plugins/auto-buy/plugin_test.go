@@ -0,0 +1,101 @@
+package autobuy
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"task_scheduler/internal/plugins"
+	"task_scheduler/pkg/ccxt/binancetest"
+	"task_scheduler/pkg/pushAPI"
+)
+
+// recordingPusher 记录推送的定投结果
+type recordingPusher struct {
+	pushAPI.PushAPI
+	reports []buyReport
+}
+
+func (p *recordingPusher) PushTemplate(appID, templateName string, data interface{}, options pushAPI.PushOptions, blocks ...pushAPI.ContentBlock) error {
+	p.reports = append(p.reports, data.(buyReport))
+	return nil
+}
+
+func TestExecuteOffline(t *testing.T) {
+	server := binancetest.NewServer()
+	defer server.Close()
+	server.AddSymbol(binancetest.Symbol{Symbol: "BTCUSDT", BaseAsset: "BTC", QuoteAsset: "USDT", TickSize: "0.01", StepSize: "0.00001", MinNotional: "5", QuoteAssetPrecision: 8})
+	server.SetBookTicker("BTCUSDT", 99990, 100000)
+	server.SetBalance("USDT", 1000)
+	t.Setenv("BINANCE_API_KEY", "KEY")
+	t.Setenv("BINANCE_SECRET_KEY", "SECRET")
+	t.Setenv("BINANCE_BASE_URL", server.URL)
+
+	dir := t.TempDir()
+	task, err := NewPlugin().CreateTask(map[string]interface{}{
+		"enabled":            true,
+		"base_amount":        100,
+		"ahr999_timer_table": `{"<0.45": 2, ">0.45": 1}`,
+		"order_state_path":   filepath.Join(dir, "orders.json"),
+		"order_journal_path": filepath.Join(dir, "journal.jsonl"),
+		"record_dir":         filepath.Join(dir, "records"),
+	})
+	if err != nil {
+		t.Fatalf("创建任务失败: %v", err)
+	}
+	autoBuy := task.(*AutoBuyTask)
+	pusher := &recordingPusher{}
+	autoBuy.pusher = pusher
+	autoBuy.ahr999Source = func() (float64, float64, error) { return 100000, 0.4, nil }
+
+	// 同一次调度执行两次，只下一笔单
+	ctx := plugins.WithRunInfo(context.Background(), plugins.RunInfo{TaskName: "auto-buy", ScheduledTime: time.Date(2025, 1, 1, 23, 0, 0, 0, time.UTC)})
+	for i := 0; i < 2; i++ {
+		if err := task.Execute(ctx); err != nil {
+			t.Fatalf("执行任务失败: %v", err)
+		}
+	}
+
+	if orders := server.Orders(); len(orders) != 1 || orders[0].ClientOrderID != "auto-buy-20250101230000" || orders[0].Status != "FILLED" {
+		t.Errorf("应只成交一笔订单: %+v", orders)
+	}
+	if len(pusher.reports) != 2 || pusher.reports[0].Result != "定投成功" || pusher.reports[0].Amount != 200 || pusher.reports[1].Result != "本期已下单，未重复买入" {
+		t.Errorf("推送的定投结果不正确: %+v", pusher.reports)
+	}
+	if free, _ := server.Balance("USDT"); free != 800 {
+		t.Errorf("USDT余额不正确: %v", free)
+	}
+}
+
+func TestExecutionTimeout(t *testing.T) {
+	config := func(extra map[string]interface{}) map[string]interface{} {
+		config := map[string]interface{}{
+			"base_amount":          100,
+			"ahr999_timer_table":   `{">0": 1}`,
+			"order_timeout":        "10m",
+			"order_timeout_action": "reprice",
+		}
+		for key, value := range extra {
+			config[key] = value
+		}
+		return config
+	}
+
+	// 默认为订单最长等待时间（初次挂单加3次重新挂单）再加预留时间
+	task, err := NewPlugin().CreateTask(config(nil))
+	if err != nil {
+		t.Fatalf("创建任务失败: %v", err)
+	}
+	if timeout := task.(plugins.TimeoutTask).Timeout(); timeout != 40*time.Minute+executionMargin {
+		t.Errorf("默认执行超时时间不正确: %s", timeout)
+	}
+
+	if _, err := NewPlugin().CreateTask(config(map[string]interface{}{"execution_timeout": "30m"})); err == nil {
+		t.Error("执行超时时间短于订单最长等待时间时应报错")
+	}
+	task, err = NewPlugin().CreateTask(config(map[string]interface{}{"execution_timeout": "1h"}))
+	if err != nil || task.(plugins.TimeoutTask).Timeout() != time.Hour {
+		t.Errorf("配置的执行超时时间未生效: %v", err)
+	}
+}
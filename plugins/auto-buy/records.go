@@ -0,0 +1,70 @@
+package autobuy
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"task_scheduler/pkg/ccxt"
+	"task_scheduler/pkg/pushAPI"
+	"time"
+)
+
+// recordDir 默认的每日定投记录目录，每天一个CSV文件
+const recordDir = "plugins/auto-buy/buy_records"
+
+// recordHeader 定投记录CSV表头
+var recordHeader = []string{"time", "result", "amount_usdt", "price", "ahr999", "btc_balance"}
+
+// appendDailyRecord 将本次定投结果追加到 dir 下当天的CSV文件，返回文件路径
+func appendDailyRecord(dir string, report buyReport, now time.Time) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("创建定投记录目录失败: %w", err)
+	}
+
+	filePath := filepath.Join(dir, fmt.Sprintf("auto_buy_%s.csv", now.Format("2006-01-02")))
+	_, statErr := os.Stat(filePath)
+
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", fmt.Errorf("打开定投记录文件失败: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	if os.IsNotExist(statErr) {
+		writer.Write(recordHeader)
+	}
+	writer.Write([]string{
+		now.Format("2006-01-02 15:04:05"),
+		report.Result,
+		strconv.FormatFloat(report.Amount, 'f', 2, 64),
+		strconv.FormatFloat(report.Price, 'f', 2, 64),
+		strconv.FormatFloat(report.Ahr999, 'f', 3, 64),
+		report.BTCBalance,
+	})
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", fmt.Errorf("写入定投记录失败: %w", err)
+	}
+	return filePath, nil
+}
+
+// orderBlocks 返回订单摘要表格和订单JSON附件
+func orderBlocks(result *ccxt.TrackResult, now time.Time) []pushAPI.ContentBlock {
+	data, _ := json.MarshalIndent(result, "", "  ")
+	order := result.Order()
+	return []pushAPI.ContentBlock{
+		pushAPI.TableBlock("订单",
+			pushAPI.KeyValue{Key: "订单号", Value: order.ID},
+			pushAPI.KeyValue{Key: "状态", Value: string(result.Status)},
+			pushAPI.KeyValue{Key: "下单次数", Value: strconv.Itoa(len(result.Orders))},
+			pushAPI.KeyValue{Key: "价格", Value: strconv.FormatFloat(order.Price, 'f', -1, 64)},
+			pushAPI.KeyValue{Key: "成交数量", Value: strconv.FormatFloat(result.ExecutedQty, 'f', -1, 64)},
+			pushAPI.KeyValue{Key: "成交均价", Value: strconv.FormatFloat(result.AvgPrice, 'f', -1, 64)},
+		),
+		pushAPI.FileDataBlock(fmt.Sprintf("order_%s.json", now.Format("20060102_150405")), data),
+	}
+}